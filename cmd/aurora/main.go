@@ -1,21 +1,40 @@
 package main
 
 import (
+	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
 	"runtime/debug"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/aws-contrib/aurora/cmd"
 	"github.com/aws-contrib/aurora/internal/database/ent"
+	"github.com/aws-contrib/aurora/internal/database/ent/lint"
 	"github.com/aws-contrib/aurora/internal/database/ent/template"
 	"github.com/urfave/cli/v3"
 )
 
 func main() {
-	cmd := &cli.Command{
+	cmd := NewCommand()
+
+	if info, ok := debug.ReadBuildInfo(); ok {
+		cmd.Version = info.Main.Version
+	}
+
+	if err := cmd.Run(context.Background(), os.Args); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// NewCommand returns the aurora command and its subcommands.
+func NewCommand() *cli.Command {
+	return &cli.Command{
 		Name:  "aurora",
 		Usage: "Manage your database schema as code",
 		Commands: []*cli.Command{
@@ -34,38 +53,98 @@ func main() {
 						Usage:    "set which env from the config file to use",
 						Required: true,
 					},
+					NewVarFlag(),
 				},
 				Commands: []*cli.Command{
 					{
-						Name:  "apply",
-						Usage: "Applies pending migration files on the connected database.",
+						Name:      "apply",
+						Usage:     "Applies pending migration files on the connected database.",
+						ArgsUsage: "[amount]",
 						Flags: []cli.Flag{
-							&cli.DurationFlag{
-								Name:  "lock-timeout",
-								Usage: "set how long to wait for the database lock",
-								Value: 25 * time.Minute,
+							&cli.StringFlag{
+								Name:  "to-version",
+								Usage: "apply the pending migrations up to the given version (inclusive)",
+							},
+							&cli.StringFlag{
+								Name:  "baseline",
+								Usage: "record the migrations up to the given version (inclusive) as applied without executing them",
+							},
+							NewFormatFlag(),
+							NewLockTimeoutFlag(),
+							NewLockTTLFlag(),
+							NewJobTimeoutFlag(),
+							NewRetryLimitFlag(),
+							&cli.BoolFlag{
+								Name:  "parallel-indexes",
+								Usage: "build consecutive indexes of a migration in parallel",
+								Value: false,
+							},
+							&cli.BoolFlag{
+								Name:  "dry-run",
+								Usage: "print the statements to apply without executing them",
+								Value: false,
+							},
+							&cli.BoolFlag{
+								Name:  "allow-destructive",
+								Usage: "apply the statements that delete data or can lose existing data",
+								Value: false,
 							},
 						},
 						Action: func(ctx context.Context, command *cli.Command) error {
+							// The flags are checked before connecting to the database
+							if command.NArg() > 0 && command.String("to-version") != "" {
+								return cli.Exit("The amount and the --to-version flag are mutually exclusive", 1)
+							}
+
 							repository, err := NewRepository(ctx, command)
 							if err != nil {
 								return err
 							}
 
-							args := &ent.LockMigrationParams{}
-							args.Timeout = command.Duration("lock-timeout")
-							// lock the execution
-							if xerr := repository.LockMigration(ctx, args); xerr != nil {
-								return xerr
+							if command.Bool("dry-run") {
+								// The plan neither takes the lock nor writes the revisions
+								return PlanMigrations(ctx, command, repository)
+							}
+
+							// lock the execution and list the migrations
+							migrations, err := LockMigrations(ctx, command, repository)
+							if err != nil {
+								return err
 							}
 							// unlock the execution
-							defer repository.UnlockMigration(ctx)
+							defer UnlockMigration(ctx, repository)
 
-							migrations, err := repository.ListMigrations(ctx, &ent.ListMigrationsParams{})
+							if version := command.String("baseline"); version != "" {
+								// record the existing schema
+								if xerr := BaselineMigrations(ctx, repository, migrations, version); xerr != nil {
+									return xerr
+								}
+							}
+
+							filter, err := NewMigrationFilter(command, migrations)
 							if err != nil {
 								return err
 							}
 
+							var selected []*ent.Migration
+							// select the migrations to apply
+							for _, migration := range migrations {
+								if filter(migration) {
+									selected = append(selected, migration)
+								}
+							}
+
+							if !command.Bool("allow-destructive") {
+								// refuse the destructive changes before executing any statement
+								if findings := GetDestructiveChanges(selected); len(findings) > 0 {
+									if xerr := template.Format(os.Stdout, "lint", command.String("format"), findings); xerr != nil {
+										return xerr
+									}
+
+									return cli.Exit("There are destructive changes in the migrations, use --allow-destructive or the '-- +destructive' directive to apply them", 1)
+								}
+							}
+
 							state := &ent.MigrationState{}
 							// prepare the status
 							for _, migration := range migrations {
@@ -73,10 +152,13 @@ func main() {
 									state.Next = migration.Revision
 								}
 
-								if err == nil {
+								if err == nil && slices.Contains(selected, migration) {
 									if state.Current == nil || state.Current.Error == nil {
 										params := &ent.ApplyMigrationParams{}
 										params.Migration = migration
+										params.JobTimeout = command.Duration("job-timeout")
+										params.Parallel = command.Bool("parallel-indexes")
+										params.AllowDestructive = command.Bool("allow-destructive")
 										// apply the migration
 										err = repository.ApplyMigration(ctx, params)
 										// update the migration state
@@ -91,10 +173,22 @@ func main() {
 									state.Current = migration.Revision
 									state.Next = nil
 								}
+
+								if migration.HasDrift() {
+									state.Drifted = append(state.Drifted, migration.Revision)
+								}
 							}
 
+							lock, lerr := GetMigrationLock(ctx, repository)
+							if lerr != nil {
+								return lerr
+							}
+
+							state.Lock = lock
 							// print the status
-							template.Execute(os.Stdout, "status", state)
+							if xerr := template.Format(os.Stdout, "status", command.String("format"), state); xerr != nil {
+								return xerr
+							}
 
 							if state.Current != nil && state.Current.Error != nil {
 								// return the error
@@ -104,10 +198,456 @@ func main() {
 							return err
 						},
 					},
+					{
+						Name:  "down",
+						Usage: "Reverts applied migration files on the connected database.",
+						Flags: []cli.Flag{
+							NewFormatFlag(),
+							NewLockTimeoutFlag(),
+							NewLockTTLFlag(),
+							NewJobTimeoutFlag(),
+							NewRetryLimitFlag(),
+						},
+						MutuallyExclusiveFlags: []cli.MutuallyExclusiveFlags{
+							{
+								Flags: [][]cli.Flag{
+									{
+										&cli.StringFlag{
+											Name:  "to",
+											Usage: "revert the applied migrations down to the given version (exclusive)",
+										},
+									},
+									{
+										&cli.IntFlag{
+											Name:  "count",
+											Usage: "set how many applied migrations to revert",
+											Value: 1,
+										},
+									},
+								},
+							},
+						},
+						Action: func(ctx context.Context, command *cli.Command) error {
+							repository, err := NewRepository(ctx, command)
+							if err != nil {
+								return err
+							}
+
+							// lock the execution and list the migrations
+							migrations, err := LockMigrations(ctx, command, repository)
+							if err != nil {
+								return err
+							}
+							// unlock the execution
+							defer UnlockMigration(ctx, repository)
+
+							version := command.String("to")
+							if version != "" && !slices.ContainsFunc(migrations, func(migration *ent.Migration) bool {
+								return migration.Revision.ID == version
+							}) {
+								return fmt.Errorf("version %s not found in the migration directory", version)
+							}
+
+							var collection []*ent.Migration
+							// select the applied migrations starting from the last one
+							for index := len(migrations) - 1; index >= 0; index-- {
+								migration := migrations[index]
+
+								if migration.Revision.ExecutedAt.IsZero() {
+									continue
+								}
+
+								if version != "" && migration.Revision.ID <= version {
+									break
+								}
+
+								if version == "" && len(collection) == command.Int("count") {
+									break
+								}
+
+								collection = append(collection, migration)
+							}
+
+							// refuse to revert when a down script is missing
+							for _, migration := range collection {
+								if len(migration.Down) == 0 {
+									return fmt.Errorf("revision %s: %w", migration.Revision.ID, ent.ErrNoDownScript)
+								}
+							}
+
+							for _, migration := range collection {
+								params := &ent.RevertMigrationParams{}
+								params.Migration = migration
+								params.JobTimeout = command.Duration("job-timeout")
+								// revert the migration
+								if err := repository.RevertMigration(ctx, params); err != nil {
+									return err
+								}
+								// stop reverting if there is an error
+								if params.Migration.Revision.Error != nil {
+									break
+								}
+							}
+
+							state := NewMigrationState(migrations)
+							if state.Lock, err = GetMigrationLock(ctx, repository); err != nil {
+								return err
+							}
+
+							// print the status
+							if xerr := template.Format(os.Stdout, "status", command.String("format"), state); xerr != nil {
+								return xerr
+							}
+
+							if state.Current != nil && state.Current.Error != nil {
+								// return the error
+								return cli.Exit("There are errors in the migration", 1)
+							}
+							// done!
+							return nil
+						},
+					},
+					{
+						Name:      "set",
+						Usage:     "Records the migration files up to a version as applied without executing them.",
+						ArgsUsage: "<version>",
+						Flags: []cli.Flag{
+							NewFormatFlag(),
+							NewLockTimeoutFlag(),
+							NewLockTTLFlag(),
+							NewRetryLimitFlag(),
+							&cli.BoolFlag{
+								Name:  "dry-run",
+								Usage: "print the revisions to record without writing them",
+								Value: false,
+							},
+						},
+						Action: func(ctx context.Context, command *cli.Command) error {
+							version := command.Args().First()
+							if version == "" {
+								return cli.Exit("The migration version is required", 1)
+							}
+
+							repository, err := NewRepository(ctx, command)
+							if err != nil {
+								return err
+							}
+
+							if command.Bool("dry-run") {
+								// The plan neither takes the lock nor writes the revisions
+								return PlanMigrations(ctx, command, repository)
+							}
+
+							// lock the execution and list the migrations
+							migrations, err := LockMigrations(ctx, command, repository)
+							if err != nil {
+								return err
+							}
+							// unlock the execution
+							defer UnlockMigration(ctx, repository)
+
+							// record the existing schema
+							if xerr := BaselineMigrations(ctx, repository, migrations, version); xerr != nil {
+								return xerr
+							}
+
+							state := NewMigrationState(migrations)
+							if state.Lock, err = GetMigrationLock(ctx, repository); err != nil {
+								return err
+							}
+
+							// print the status
+							return template.Format(os.Stdout, "status", command.String("format"), state)
+						},
+					},
+					{
+						Name:      "resolve",
+						Usage:     "Repairs a failed migration file on the connected database.",
+						ArgsUsage: "<version>",
+						MutuallyExclusiveFlags: []cli.MutuallyExclusiveFlags{
+							{
+								Required: true,
+								Flags: [][]cli.Flag{
+									{
+										&cli.BoolFlag{
+											Name:  string(ent.ResolveRetry),
+											Usage: "clear the error, so the failed statement is executed again",
+										},
+									},
+									{
+										&cli.BoolFlag{
+											Name:  string(ent.ResolveSkipStatement),
+											Usage: "clear the error and skip the failed statement",
+										},
+									},
+									{
+										&cli.BoolFlag{
+											Name:  string(ent.ResolveReset),
+											Usage: "delete the revision, so the migration file is executed again",
+										},
+									},
+								},
+							},
+						},
+						Flags: []cli.Flag{
+							NewFormatFlag(),
+							NewLockTimeoutFlag(),
+							NewLockTTLFlag(),
+							NewRetryLimitFlag(),
+						},
+						Action: func(ctx context.Context, command *cli.Command) error {
+							version := command.Args().First()
+							if version == "" {
+								return cli.Exit("The migration version is required", 1)
+							}
+
+							repository, err := NewRepository(ctx, command)
+							if err != nil {
+								return err
+							}
+
+							// lock the execution and list the migrations
+							migrations, err := LockMigrations(ctx, command, repository)
+							if err != nil {
+								return err
+							}
+							// unlock the execution
+							defer UnlockMigration(ctx, repository)
+
+							index := slices.IndexFunc(migrations, func(migration *ent.Migration) bool {
+								return migration.Revision.ID == version
+							})
+
+							if index < 0 {
+								return fmt.Errorf("version %s not found in the migration directory", version)
+							}
+
+							params := &ent.ResolveMigrationParams{}
+							params.Migration = migrations[index]
+							// select the action
+							for _, action := range []ent.ResolveAction{ent.ResolveRetry, ent.ResolveSkipStatement, ent.ResolveReset} {
+								if command.Bool(string(action)) {
+									params.Action = action
+								}
+							}
+
+							// resolve the migration
+							if xerr := repository.ResolveMigration(ctx, params); xerr != nil {
+								return xerr
+							}
+
+							state := NewMigrationState(migrations)
+							if state.Lock, err = GetMigrationLock(ctx, repository); err != nil {
+								return err
+							}
+
+							// print the status
+							return template.Format(os.Stdout, "status", command.String("format"), state)
+						},
+					},
+					{
+						Name:  "history",
+						Usage: "Shows the audit log of the statements executed on the connected database.",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "version",
+								Usage: "show only the events of the given migration version",
+							},
+							NewFormatFlag(),
+						},
+						Action: func(ctx context.Context, command *cli.Command) error {
+							repository, err := NewRepository(ctx, command)
+							if err != nil {
+								return err
+							}
+
+							params := &ent.ListMigrationEventsParams{}
+							params.Version = command.String("version")
+							// list the events
+							events, err := repository.ListMigrationEvents(ctx, params)
+							if err != nil {
+								return err
+							}
+
+							// print the history
+							return template.Format(os.Stdout, "history", command.String("format"), events)
+						},
+					},
+					{
+						Name:  "lint",
+						Usage: "Checks the migration files for statements that Aurora DSQL does not support.",
+						Flags: []cli.Flag{
+							NewFormatFlag(),
+						},
+						Action: func(ctx context.Context, command *cli.Command) error {
+							// The linter does not connect to the database
+							repository, err := NewFileRepository(command)
+							if err != nil {
+								return err
+							}
+
+							findings, err := repository.LintMigrations(ctx, &ent.LintMigrationsParams{})
+							if err != nil {
+								return err
+							}
+
+							// print the findings
+							if xerr := template.Format(os.Stdout, "lint", command.String("format"), findings); xerr != nil {
+								return xerr
+							}
+
+							if lint.HasErrors(findings) {
+								return cli.Exit("There are errors in the migration files", 1)
+							}
+							// done!
+							return nil
+						},
+					},
+					{
+						Name:  "hash",
+						Usage: "Writes the atlas.sum integrity file of the migration directory.",
+						Action: func(ctx context.Context, command *cli.Command) error {
+							// The integrity file does not depend on the database
+							repository, err := NewFileRepository(command)
+							if err != nil {
+								return err
+							}
+
+							// write the integrity file
+							if _, err := repository.HashMigrations(ctx, &ent.HashMigrationsParams{}); err != nil {
+								return err
+							}
+
+							fmt.Fprintf(os.Stdout, "Integrity file written: %s\n", ent.HashFileName)
+							// done!
+							return nil
+						},
+					},
+					{
+						Name:      "new",
+						Usage:     "Creates a new empty migration file in the migration directory.",
+						ArgsUsage: "<description>",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "id-scheme",
+								Usage: "set the revision ID scheme (timestamp or sequential)",
+								Value: ent.RevisionSchemeTimestamp,
+							},
+						},
+						Action: func(ctx context.Context, command *cli.Command) error {
+							if command.NArg() == 0 {
+								return cli.Exit("The migration description is required", 1)
+							}
+
+							// The migration file is created from the migration directory only
+							repository, err := NewFileRepository(command)
+							if err != nil {
+								return err
+							}
+
+							params := &ent.CreateMigrationParams{}
+							params.Description = strings.Join(command.Args().Slice(), " ")
+							params.Scheme = command.String("id-scheme")
+							// create the migration file
+							migration, err := repository.CreateMigration(ctx, params)
+							if err != nil {
+								return err
+							}
+
+							fmt.Fprintf(os.Stdout, "Migration file created: %s\n", migration.Revision.GetName())
+							// done!
+							return nil
+						},
+					},
+					{
+						Name:  "lock",
+						Usage: "Inspect the database migration lock.",
+						Commands: []*cli.Command{
+							{
+								Name:  "status",
+								Usage: "Shows who holds the migration lock and since when.",
+								Flags: []cli.Flag{
+									NewFormatFlag(),
+								},
+								Action: func(ctx context.Context, command *cli.Command) error {
+									repository, err := NewRepository(ctx, command)
+									if err != nil {
+										return err
+									}
+
+									lock, err := GetMigrationLock(ctx, repository)
+									if err != nil {
+										return err
+									}
+
+									// print the lock
+									return template.Format(os.Stdout, "lock", command.String("format"), lock)
+								},
+							},
+						},
+					},
+					{
+						Name:  "unlock",
+						Usage: "Releases the database migration lock left behind by a stopped process.",
+						Flags: []cli.Flag{
+							NewFormatFlag(),
+							&cli.BoolFlag{
+								Name:  "force",
+								Usage: "release the lock without confirmation",
+								Value: false,
+							},
+						},
+						Action: func(ctx context.Context, command *cli.Command) error {
+							repository, err := NewRepository(ctx, command)
+							if err != nil {
+								return err
+							}
+
+							lock, err := GetMigrationLock(ctx, repository)
+							if err != nil {
+								return err
+							}
+
+							// print the lock
+							if xerr := template.Format(os.Stdout, "lock", command.String("format"), lock); xerr != nil {
+								return xerr
+							}
+
+							if lock == nil {
+								return nil
+							}
+
+							if !command.Bool("force") {
+								fmt.Fprint(os.Stdout, "\nRelease the migration lock? [y/N]: ")
+
+								answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+								if answer = strings.ToLower(strings.TrimSpace(answer)); answer != "y" && answer != "yes" {
+									return cli.Exit("The migration lock was not released", 1)
+								}
+							}
+
+							// release the lock that was shown, not the one that may have replaced it
+							params := &ent.ReleaseMigrationLockParams{}
+							params.Lock = lock
+
+							err = repository.ReleaseMigrationLock(ctx, params)
+							switch {
+							case errors.Is(err, ent.ErrLockChanged):
+								return cli.Exit("The migration lock changed, it was not released", 1)
+							case err != nil:
+								return err
+							}
+
+							fmt.Fprintln(os.Stdout, "The migration lock was released")
+							// done!
+							return nil
+						},
+					},
 					{
 						Name:  "status",
 						Usage: "Get information about the current migration status.",
 						Flags: []cli.Flag{
+							NewFormatFlag(),
 							&cli.BoolFlag{
 								Name:  "wait",
 								Usage: "wait for the database pending migrations to be applied",
@@ -118,6 +658,11 @@ func main() {
 								Usage: "set how long to wait for the database pending migrations",
 								Value: 25 * time.Minute,
 							},
+							&cli.BoolFlag{
+								Name:  "strict",
+								Usage: "exit with an error if applied migration files were changed",
+								Value: false,
+							},
 						},
 						Action: func(ctx context.Context, command *cli.Command) error {
 							repository, err := NewRepository(ctx, command)
@@ -125,6 +670,11 @@ func main() {
 								return err
 							}
 
+							// verify the migration directory
+							if xerr := repository.VerifyMigrations(ctx, &ent.VerifyMigrationsParams{}); xerr != nil {
+								return xerr
+							}
+
 							start := time.Now()
 							state := &ent.MigrationState{}
 
@@ -150,8 +700,19 @@ func main() {
 										state.Current = migration.Revision
 										state.Next = nil
 									}
+
+									if migration.HasDrift() {
+										state.Drifted = append(state.Drifted, migration.Revision)
+									}
 								}
 
+								lock, lerr := GetMigrationLock(ctx, repository)
+								if lerr != nil {
+									return lerr
+								}
+
+								state.Lock = lock
+
 								if state.Next != nil {
 									// Wait for the migrations to be applied
 									if command.Bool("wait") {
@@ -167,7 +728,14 @@ func main() {
 							}
 
 							// print the status
-							template.Execute(os.Stdout, "status", state)
+							if xerr := template.Format(os.Stdout, "status", command.String("format"), state); xerr != nil {
+								return xerr
+							}
+
+							if command.Bool("strict") && len(state.Drifted) > 0 {
+								// return the error
+								return cli.Exit("There are applied migration files that were changed", 1)
+							}
 							// done!
 							return err
 						},
@@ -176,17 +744,190 @@ func main() {
 			},
 		},
 	}
+}
 
-	if info, ok := debug.ReadBuildInfo(); ok {
-		cmd.Version = info.Main.Version
+func NewRepository(ctx context.Context, command *cli.Command) (*ent.MigrationRepository, error) {
+	config, err := NewEnvironment(command)
+	if err != nil {
+		return nil, err
 	}
 
-	if err := cmd.Run(context.Background(), os.Args); err != nil {
-		log.Fatal(err)
+	conn, err := config.GetURL()
+	if err != nil {
+		return nil, err
+	}
+
+	directory, err := NewFileSystem(config)
+	if err != nil {
+		return nil, err
 	}
+
+	emulate, err := config.GetEmulateJobs()
+	if err != nil {
+		return nil, err
+	}
+
+	var options []ent.GatewayOption
+	// The token of the URL expires, each new connection gets a fresh one
+	if token := config.GetDSQLToken(); token != nil {
+		options = append(options, ent.WithDSQLToken(token.Host, token.User, token.Config, token.Options...))
+	}
+
+	gateway, err := ent.Open(ctx, conn, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	dialect, err := NewDialect(ctx, config, gateway, conn)
+	if err != nil {
+		gateway.Close()
+		return nil, err
+	}
+
+	if emulate && dialect != ent.DialectPostgres {
+		gateway.Close()
+		return nil, fmt.Errorf("environment %s: emulate_jobs requires the %s dialect", config.Name, ent.DialectPostgres)
+	}
+
+	repository := &ent.MigrationRepository{
+		Gateway:     gateway,
+		FileSystem:  directory,
+		Version:     command.Root().Version,
+		Dialect:     dialect,
+		EmulateJobs: emulate,
+	}
+
+	return repository, nil
 }
 
-func NewRepository(ctx context.Context, command *cli.Command) (*ent.MigrationRepository, error) {
+// NewFileRepository returns a repository of the migration directory of the
+// environment that does not connect to the database.
+func NewFileRepository(command *cli.Command) (*ent.MigrationRepository, error) {
+	config, err := NewEnvironment(command)
+	if err != nil {
+		return nil, err
+	}
+
+	directory, err := NewFileSystem(config)
+	if err != nil {
+		return nil, err
+	}
+
+	repository := &ent.MigrationRepository{
+		FileSystem: directory,
+	}
+
+	return repository, nil
+}
+
+// NewFormatFlag returns the --format flag of the commands that print a report.
+func NewFormatFlag() cli.Flag {
+	return &cli.StringFlag{
+		Name:  "format",
+		Usage: "set the output format: text, json, yaml or a Go template",
+		Value: template.FormatText,
+	}
+}
+
+// NewVarFlag returns the --var flag that sets the value of a variable. The flag
+// can be repeated, and its values are not split at commas because the values
+// of a list or a map contain them.
+func NewVarFlag() cli.Flag {
+	return &VarFlag{
+		Name:  "var",
+		Usage: "set the value of a variable declared in the config file (key=value)",
+	}
+}
+
+// VarFlag is a flag whose values are appended as given, unlike the values of
+// a cli.StringSliceFlag that are split at the separator of the command that
+// parses them.
+type VarFlag = cli.FlagBase[[]string, cli.NoConfig, VarValue]
+
+// VarValue is the value of a VarFlag.
+type VarValue struct {
+	values *[]string
+}
+
+// Create implements cli.ValueCreator.
+func (x VarValue) Create(values []string, target *[]string, _ cli.NoConfig) cli.Value {
+	*target = slices.Clone(values)
+	return &VarValue{values: target}
+}
+
+// ToString implements cli.ValueCreator.
+func (x VarValue) ToString(values []string) string {
+	return strings.Join(values, " ")
+}
+
+// Set appends the value.
+func (x *VarValue) Set(value string) error {
+	*x.values = append(*x.values, value)
+	return nil
+}
+
+// Get returns the values.
+func (x *VarValue) Get() any {
+	return *x.values
+}
+
+// String returns the values.
+func (x *VarValue) String() string {
+	if x.values == nil {
+		return ""
+	}
+
+	return x.ToString(*x.values)
+}
+
+// NewLockTimeoutFlag returns the --lock-timeout flag of the commands that take the lock.
+func NewLockTimeoutFlag() cli.Flag {
+	return &cli.DurationFlag{
+		Name:  "lock-timeout",
+		Usage: "set how long to wait for the database lock",
+		Value: 25 * time.Minute,
+	}
+}
+
+// NewLockTTLFlag returns the --lock-ttl flag of the commands that take the lock.
+func NewLockTTLFlag() cli.Flag {
+	return &cli.DurationFlag{
+		Name:      "lock-ttl",
+		Usage:     "set how long the database lock is held without a heartbeat before another process can take it over",
+		Value:     ent.DefaultLockTTL,
+		Validator: ValidateLockTTL,
+	}
+}
+
+// NewJobTimeoutFlag returns the --job-timeout flag of the commands that execute statements.
+func NewJobTimeoutFlag() cli.Flag {
+	return &cli.DurationFlag{
+		Name:  "job-timeout",
+		Usage: "set how long to wait for an asynchronous job, such as an index build (0 waits forever)",
+		Value: time.Hour,
+	}
+}
+
+// NewRetryLimitFlag returns the --retry-limit flag of the commands that write to the database.
+func NewRetryLimitFlag() cli.Flag {
+	return &cli.IntFlag{
+		Name:  "retry-limit",
+		Usage: "set how many times an operation is retried after a concurrency conflict",
+		Value: 5,
+	}
+}
+
+// ValidateLockTTL reports an error if the --lock-ttl value is not positive.
+func ValidateLockTTL(ttl time.Duration) error {
+	if ttl <= 0 {
+		return fmt.Errorf("lock ttl must be positive, got %s", ttl)
+	}
+
+	return nil
+}
+
+// NewEnvironment returns the environment selected with the --env flag.
+func NewEnvironment(command *cli.Command) (*cmd.Environment, error) {
 	path, err := cmd.GetPath(command.String("config"))
 	if err != nil {
 		return nil, err
@@ -197,49 +938,338 @@ func NewRepository(ctx context.Context, command *cli.Command) (*ent.MigrationRep
 		return nil, err
 	}
 
-	config := &cmd.Config{}
+	name := command.String("env")
+
+	config := &cmd.Config{
+		Values: map[string]string{},
+		// Only the data sources of the environment are evaluated
+		Env: name,
+	}
+
+	for _, input := range command.StringSlice("var") {
+		key, value, ok := strings.Cut(input, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid variable %q, expected key=value", input)
+		}
+
+		config.Values[key] = value
+	}
+
 	if err := config.UnmarshalText(data); err != nil {
 		return nil, err
 	}
 
-	name := command.String("env")
 	// Get the environment configuration
 	if config := config.GetEnvironment(name); config != nil {
-		conn, err := config.GetURL()
-		if err != nil {
-			return nil, err
+		return config, nil
+	}
+
+	return nil, fmt.Errorf("environment %s not found in config", name)
+}
+
+// NewDialect returns the dialect of the environment. It is detected from
+// the URL, or from the server, when the environment does not set it.
+func NewDialect(ctx context.Context, config *cmd.Environment, querier ent.Querier, conn string) (ent.Dialect, error) {
+	name, err := config.GetDialect()
+	if err != nil {
+		return "", err
+	}
+
+	if name == "" {
+		return ent.DetectDialect(ctx, querier, conn)
+	}
+
+	return ent.ParseDialect(name)
+}
+
+// NewFileSystem returns the migration directory of the environment.
+func NewFileSystem(config *cmd.Environment) (ent.DirFS, error) {
+	directory, err := config.Migration.GetDir()
+	if err != nil {
+		return "", err
+	}
+
+	directory, err = cmd.GetPath(directory)
+	if err != nil {
+		return "", err
+	}
+
+	return ent.DirFS(directory), nil
+}
+
+// NewMigrationState returns the state of the given migrations.
+func NewMigrationState(migrations []*ent.Migration) *ent.MigrationState {
+	state := &ent.MigrationState{}
+	// prepare the status
+	for _, migration := range migrations {
+		if state.Next == nil {
+			state.Next = migration.Revision
 		}
 
-		directory, err := config.Migration.GetDir()
-		if err != nil {
-			return nil, err
+		if migration.Revision.ExecutedAt.IsZero() {
+			state.Pending = append(state.Pending, migration.Revision)
+		} else {
+			state.Executed = append(state.Executed, migration.Revision)
+			state.Current = migration.Revision
+			state.Next = nil
 		}
 
-		directory, err = cmd.GetPath(directory)
-		if err != nil {
-			return nil, err
+		if migration.HasDrift() {
+			state.Drifted = append(state.Drifted, migration.Revision)
+		}
+	}
+
+	return state
+}
+
+// PlanMigrations prints the statements that apply would execute.
+func PlanMigrations(ctx context.Context, command *cli.Command, repository *ent.MigrationRepository) error {
+	// verify the migration directory
+	if err := repository.VerifyMigrations(ctx, &ent.VerifyMigrationsParams{}); err != nil {
+		return err
+	}
+
+	migrations, err := repository.ListMigrations(ctx, &ent.ListMigrationsParams{})
+	if err != nil {
+		return err
+	}
+
+	var baseline []*ent.Migration
+	// The set command only records the existing schema
+	if command.Name == "set" {
+		if baseline, err = SelectBaseline(migrations, command.Args().First()); err != nil {
+			return err
+		}
+
+		migrations = baseline
+	} else if version := command.String("baseline"); version != "" {
+		if baseline, err = SelectBaseline(migrations, version); err != nil {
+			return err
+		}
+	}
+
+	var (
+		filter           = func(*ent.Migration) bool { return true }
+		allowDestructive bool
+	)
+	// The apply command can be limited to a number of files or a version
+	if command.Name == "apply" {
+		if filter, err = NewMigrationFilter(command, migrations); err != nil {
+			return err
+		}
+
+		allowDestructive = command.Bool("allow-destructive")
+	}
+
+	var collection []*ent.MigrationPlan
+	// prepare the plan
+	for _, migration := range migrations {
+		if slices.Contains(baseline, migration) {
+			params := &ent.PlanMigrationParams{}
+			params.Migration = migration
+			params.Baseline = true
+
+			plan, err := repository.PlanMigration(ctx, params)
+			if err != nil {
+				return err
+			}
+
+			// The baselined revision is not pending anymore
+			migration.Revision = plan.Revision
+			collection = append(collection, plan)
 		}
 
-		gateway, err := ent.Open(ctx, conn)
+		if !filter(migration) || !IsMigrationPending(migration) {
+			continue
+		}
+
+		params := &ent.PlanMigrationParams{}
+		params.Migration = migration
+		params.AllowDestructive = allowDestructive
+
+		plan, err := repository.PlanMigration(ctx, params)
 		if err != nil {
-			return nil, err
+			return err
+		}
+
+		collection = append(collection, plan)
+	}
+
+	// print the plan
+	return template.Format(os.Stdout, "plan", command.String("format"), collection)
+}
+
+// GetDestructiveChanges returns the destructive changes of the migrations
+// that do not allow them with the '-- +destructive' directive.
+func GetDestructiveChanges(migrations []*ent.Migration) []*lint.Finding {
+	var collection []*lint.Finding
+
+	for _, migration := range migrations {
+		if !migration.Destructive {
+			collection = append(collection, migration.GetDestructiveChanges()...)
 		}
+	}
+
+	return collection
+}
+
+// SelectBaseline returns the migrations up to the given version that are
+// not recorded in the database yet.
+func SelectBaseline(migrations []*ent.Migration, version string) ([]*ent.Migration, error) {
+	index := slices.IndexFunc(migrations, func(migration *ent.Migration) bool {
+		return migration.Revision.ID == version
+	})
+
+	if index < 0 {
+		return nil, fmt.Errorf("version %s not found in the migration directory", version)
+	}
+
+	var collection []*ent.Migration
+	// select the migrations that were not applied
+	for _, migration := range migrations[:index+1] {
+		if migration.Revision.ExecutedAt.IsZero() {
+			collection = append(collection, migration)
+		}
+	}
+
+	return collection, nil
+}
+
+// BaselineMigrations records the migrations up to the given version as
+// applied without executing their statements.
+func BaselineMigrations(ctx context.Context, repository *ent.MigrationRepository, migrations []*ent.Migration, version string) error {
+	collection, err := SelectBaseline(migrations, version)
+	if err != nil {
+		return err
+	}
 
-		if err := gateway.CreateTableLocks(ctx); err != nil {
-			return nil, err
+	for _, migration := range collection {
+		params := &ent.BaselineMigrationParams{}
+		params.Migration = migration
+		// record the migration
+		if err := repository.BaselineMigration(ctx, params); err != nil {
+			return err
 		}
+	}
+
+	return nil
+}
+
+// IsMigrationPending reports whether the migration has statements left to apply.
+func IsMigrationPending(migration *ent.Migration) bool {
+	return migration.Revision.ExecutedAt.IsZero() || migration.Revision.Count < len(migration.Statements)
+}
 
-		if err := gateway.CreateTableRevisions(ctx); err != nil {
-			return nil, err
+// NewMigrationFilter returns a function that reports whether a migration
+// should be applied. The pending migrations are limited by the amount
+// argument or by the --to-version flag. The function must be called for
+// each migration in order.
+func NewMigrationFilter(command *cli.Command, migrations []*ent.Migration) (func(*ent.Migration) bool, error) {
+	if version := command.String("to-version"); version != "" {
+		if !slices.ContainsFunc(migrations, func(migration *ent.Migration) bool {
+			return migration.Revision.ID == version
+		}) {
+			return nil, fmt.Errorf("version %s not found in the migration directory", version)
 		}
 
-		repository := &ent.MigrationRepository{
-			Gateway:    gateway,
-			FileSystem: os.DirFS(directory),
+		var done bool
+		// apply the migrations until the version is reached
+		fn := func(migration *ent.Migration) bool {
+			if done {
+				return false
+			}
+
+			done = migration.Revision.ID == version
+			return true
 		}
 
-		return repository, nil
+		return fn, nil
 	}
 
-	return nil, fmt.Errorf("environment %s not found in config", name)
+	if command.NArg() > 0 {
+		amount, err := strconv.Atoi(command.Args().First())
+		if err != nil || amount < 1 {
+			return nil, fmt.Errorf("the amount %q must be a positive number", command.Args().First())
+		}
+
+		// apply the first pending migrations
+		fn := func(migration *ent.Migration) bool {
+			if !IsMigrationPending(migration) {
+				return true
+			}
+
+			amount--
+			return amount >= 0
+		}
+
+		return fn, nil
+	}
+
+	fn := func(*ent.Migration) bool {
+		return true
+	}
+
+	return fn, nil
+}
+
+// NewRetryPolicy returns a retry policy that reports each retry.
+func NewRetryPolicy(limit int) *ent.RetryPolicy {
+	return &ent.RetryPolicy{
+		Limit: limit,
+		OnRetry: func(attempt int, err error) {
+			fmt.Fprintf(os.Stderr, "Retrying after a concurrency conflict (attempt %d of %d): %v\n", attempt, limit, err)
+		},
+	}
+}
+
+// GetMigrationLock returns the migration lock or nil if it is not held.
+func GetMigrationLock(ctx context.Context, repository *ent.MigrationRepository) (*ent.Lock, error) {
+	lock, err := repository.GetMigrationLock(ctx, &ent.GetMigrationLockParams{})
+	if ent.IsErrorNotFound(err) {
+		return nil, nil
+	}
+
+	return lock, err
+}
+
+// LockMigrations creates or upgrades the migration tables, takes the
+// migration lock, verifies the migration directory and lists the migrations.
+// When it succeeds, the caller releases the lock with UnlockMigration.
+func LockMigrations(ctx context.Context, command *cli.Command, repository *ent.MigrationRepository) ([]*ent.Migration, error) {
+	repository.Retry = NewRetryPolicy(command.Int("retry-limit"))
+
+	// create or upgrade the migration tables
+	if err := repository.InitMigrations(ctx, &ent.InitMigrationsParams{}); err != nil {
+		return nil, err
+	}
+
+	args := &ent.LockMigrationParams{}
+	args.Timeout = command.Duration("lock-timeout")
+	args.TTL = command.Duration("lock-ttl")
+	args.Version = command.Root().Version
+	// lock the execution
+	if err := repository.LockMigration(ctx, args); err != nil {
+		return nil, err
+	}
+
+	// verify the migration directory
+	if err := repository.VerifyMigrations(ctx, &ent.VerifyMigrationsParams{}); err != nil {
+		UnlockMigration(ctx, repository)
+		return nil, err
+	}
+
+	migrations, err := repository.ListMigrations(ctx, &ent.ListMigrationsParams{})
+	if err != nil {
+		UnlockMigration(ctx, repository)
+		return nil, err
+	}
+
+	return migrations, nil
+}
+
+// UnlockMigration releases the migration lock and reports a lock that was lost.
+func UnlockMigration(ctx context.Context, repository *ent.MigrationRepository) {
+	if err := repository.UnlockMigration(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "Unable to release the migration lock: %v\n", err)
+	}
 }
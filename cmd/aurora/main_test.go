@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/urfave/cli/v3"
+)
+
+var _ = Describe("NewCommand", func() {
+	var args []string
+
+	BeforeEach(func() {
+		args = []string{"aurora", "migrate", "--config", "file://testdata/aurora.hcl", "--env", "local", "lint"}
+	})
+
+	It("sets a variable whose value contains commas", func(ctx SpecContext) {
+		args = append(args, "--var", "ports=[5432, 5433]")
+		Expect(NewCommand().Run(ctx, args)).To(Succeed())
+	})
+
+	It("sets a variable before the subcommand", func(ctx SpecContext) {
+		args = []string{"aurora", "migrate", "--config", "file://testdata/aurora.hcl", "--env", "local", "--var", "ports=[5432,5433]", "lint"}
+		Expect(NewCommand().Run(ctx, args)).To(Succeed())
+	})
+
+	When("the variable has no value", func() {
+		It("returns an error", func(ctx SpecContext) {
+			args = append(args, "--var", "ports")
+			Expect(NewCommand().Run(ctx, args)).To(MatchError(`invalid variable "ports", expected key=value`))
+		})
+	})
+
+	When("the variable has no name", func() {
+		It("returns an error", func(ctx SpecContext) {
+			args = append(args, "--var", "=[5432, 5433]")
+			Expect(NewCommand().Run(ctx, args)).To(MatchError(`invalid variable "=[5432, 5433]", expected key=value`))
+		})
+	})
+
+	When("the amount and --to-version are both set", func() {
+		It("returns an error without connecting to the database", func(ctx SpecContext) {
+			args = []string{"aurora", "migrate", "--config", "file://testdata/aurora.hcl", "--env", "local", "--var", "ports=[5432, 5433]", "apply", "--to-version", "20060102150405", "1"}
+
+			command := NewCommand()
+			// The exit code is returned instead of exiting the test
+			command.ExitErrHandler = func(context.Context, *cli.Command, error) {}
+			Expect(command.Run(ctx, args)).To(MatchError("The amount and the --to-version flag are mutually exclusive"))
+		})
+	})
+})
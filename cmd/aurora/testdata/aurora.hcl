@@ -0,0 +1,16 @@
+env "local" {
+  migration {
+    dir = "file://testdata/migration"
+  }
+
+  url = "postgres://postgres@localhost:${var.ports[0]}/example"
+}
+
+variable "ports" {
+  type = list(number)
+
+  validation {
+    condition     = var.ports[1] == 5433
+    error_message = "The second port must be 5433."
+  }
+}
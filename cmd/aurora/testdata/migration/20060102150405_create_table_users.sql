@@ -0,0 +1 @@
+CREATE TABLE IF NOT EXISTS users (id UUID PRIMARY KEY);
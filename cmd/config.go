@@ -4,13 +4,16 @@ package cmd
 import (
 	"encoding"
 	"fmt"
+	"maps"
 	"net/url"
+	"slices"
 	"strings"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/gohcl"
 	"github.com/hashicorp/hcl/v2/hclparse"
 	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
 )
 
 type Node interface {
@@ -23,7 +26,13 @@ type Node interface {
 type Config struct {
 	Data         []*Data        `hcl:"data,block"`
 	Variables    []*Variable    `hcl:"variable,block"`
+	Locals       []*Locals      `hcl:"locals,block"`
 	Environments []*Environment `hcl:"env,block"`
+	// Values are the values of the variables given on the command line.
+	Values map[string]string
+	// Env is the name of the selected environment. When it is set, only the
+	// blocks the environment depends on are evaluated.
+	Env string
 }
 
 // GetEnvironment retrieves an environment by its name from the Config.
@@ -53,6 +62,9 @@ func (c *Config) UnmarshalText(text []byte) error {
 		return derr
 	}
 
+	config.Values = c.Values
+	config.Env = c.Env
+
 	if _, cerr := config.Eval(rootCtx); cerr != nil {
 		return cerr
 	}
@@ -68,37 +80,51 @@ func (c *Config) Eval(ctx *hcl.EvalContext) (_ cty.Value, err hcl.Diagnostics) {
 	ctx = &hcl.EvalContext{
 		Functions: ctx.Functions,
 		Variables: map[string]cty.Value{
-			"env":  cty.EmptyObjectVal,
-			"var":  cty.EmptyObjectVal,
-			"data": cty.EmptyObjectVal,
+			"env":   cty.EmptyObjectVal,
+			"var":   cty.EmptyObjectVal,
+			"local": cty.EmptyObjectVal,
+			"data":  cty.EmptyObjectVal,
 		},
 	}
 
-	for _, v := range c.Variables {
-		kv := GetValueMap(ctx.Variables["var"])
-		if kv[v.Name], err = v.Eval(ctx); err != nil {
-			return cty.Value{}, err
+	for _, name := range slices.Sorted(maps.Keys(c.Values)) {
+		if !slices.ContainsFunc(c.Variables, func(v *Variable) bool { return v.Name == name }) {
+			return cty.Value{}, hcl.Diagnostics{
+				{
+					Severity: hcl.DiagError,
+					Summary:  "Undeclared variable",
+					Detail:   fmt.Sprintf("A value was given for the variable %q, but it is not declared in the configuration.", name),
+				},
+			}
 		}
-		// Update the context with the evaluated variable
-		ctx.Variables["var"] = cty.ObjectVal(kv)
 	}
 
-	for _, d := range c.Data {
-		kv := GetValueMap(ctx.Variables["data"])
-		if kv[d.Type], err = d.Eval(ctx); err != nil {
-			return cty.Value{}, err
-		}
-		// Update the context with the evaluated variable
-		ctx.Variables["data"] = cty.ObjectVal(kv)
+	for _, v := range c.Variables {
+		v.Input = GetVariableInput(c.Values, v.Name)
+	}
+
+	graph, err := c.GetGraph()
+	if err != nil {
+		return cty.Value{}, err
 	}
 
 	for _, e := range c.Environments {
-		kv := GetValueMap(ctx.Variables["env"])
-		if kv[e.Name], err = e.Eval(ctx); err != nil {
-			return cty.Value{}, err
-		}
-		// Update the context with the evaluated variable
-		ctx.Variables["env"] = cty.ObjectVal(kv)
+		e.Graph = graph
+	}
+
+	var addresses []string
+	// The data sources of the other environments are not evaluated
+	if c.Env != "" {
+		addresses = append(addresses, "env."+c.Env)
+	}
+
+	vertices, err := graph.Sort(addresses...)
+	if err != nil {
+		return cty.Value{}, err
+	}
+
+	if err := graph.Eval(ctx, vertices); err != nil {
+		return cty.Value{}, err
 	}
 
 	return cty.ObjectVal(ctx.Variables), nil
@@ -106,10 +132,44 @@ func (c *Config) Eval(ctx *hcl.EvalContext) (_ cty.Value, err hcl.Diagnostics) {
 
 // Environment represents an 'env' block.
 type Environment struct {
-	Name      string           `hcl:"name,label"`
-	Migration *Migration       `hcl:"migration,block"`
-	URL       hcl.Expression   `hcl:"url"`
-	Context   *hcl.EvalContext `hcl:"-"`
+	Name        string           `hcl:"name,label"`
+	Migration   *Migration       `hcl:"migration,block"`
+	DefRange    hcl.Range        `hcl:",def_range"`
+	URL         hcl.Expression   `hcl:"url"`
+	Dialect     hcl.Expression   `hcl:"dialect,optional"`
+	EmulateJobs hcl.Expression   `hcl:"emulate_jobs,optional"`
+	Context     *hcl.EvalContext `hcl:"-"`
+	// Graph is the dependency graph the environment was evaluated with.
+	Graph *Graph
+}
+
+// GetDSQLToken returns the aws_dsql_token data source used by the URL of
+// the environment, directly or through variables and local values, or nil.
+func (x *Environment) GetDSQLToken() *DSQLToken {
+	if x.Graph == nil {
+		return nil
+	}
+
+	var addresses []string
+	for _, traversal := range x.URL.Variables() {
+		if address := GetAddress(traversal); address != "" {
+			addresses = append(addresses, address)
+		}
+	}
+
+	// All the vertices are returned without address
+	if len(addresses) == 0 {
+		return nil
+	}
+
+	vertices, _ := x.Graph.Sort(addresses...)
+	for _, vertex := range vertices {
+		if d, ok := vertex.Node.(*Data); ok && d.Type == "aws_dsql_token" && d.Token != nil {
+			return d.Token
+		}
+	}
+
+	return nil
 }
 
 // GetURL returns the URL of the environment.
@@ -121,6 +181,34 @@ func (x *Environment) GetURL() (string, error) {
 	return value.AsString(), nil
 }
 
+// GetDialect returns the dialect of the environment, or an empty string
+// when it is not set.
+func (x *Environment) GetDialect() (string, error) {
+	value, err := GetString(x.Context, x.Dialect)
+	if err.HasErrors() {
+		return "", err
+	}
+	return value, nil
+}
+
+// GetEmulateJobs reports whether the environment emulates the asynchronous jobs.
+func (x *Environment) GetEmulateJobs() (bool, error) {
+	value, err := x.EmulateJobs.Value(x.Context)
+	if err != nil {
+		return false, err
+	}
+
+	if value.IsNull() {
+		return false, nil
+	}
+
+	value, cerr := convert.Convert(value, cty.Bool)
+	if cerr != nil {
+		return false, cerr
+	}
+	return value.True(), nil
+}
+
 var _ Node = &Environment{}
 
 // Eval implements Node.
@@ -137,9 +225,21 @@ func (x *Environment) Eval(ctx *hcl.EvalContext) (cty.Value, hcl.Diagnostics) {
 		return cty.Value{}, err
 	}
 
+	dialect, err := x.Dialect.Value(ctx)
+	if err != nil {
+		return cty.Value{}, err
+	}
+
+	jobs, err := x.EmulateJobs.Value(ctx)
+	if err != nil {
+		return cty.Value{}, err
+	}
+
 	return cty.ObjectVal(map[string]cty.Value{
-		"migration": migration,
-		"url":       url,
+		"migration":    migration,
+		"url":          url,
+		"dialect":      dialect,
+		"emulate_jobs": jobs,
 	}), nil
 }
 
@@ -176,10 +276,13 @@ func (x *Migration) Eval(ctx *hcl.EvalContext) (cty.Value, hcl.Diagnostics) {
 
 // Data represents a 'data' block.
 type Data struct {
-	Type    string           `hcl:"type,label"`
-	Name    string           `hcl:"name,label"`
-	Remain  hcl.Body         `hcl:",remain"`
-	Context *hcl.EvalContext `hcl:"-"`
+	Type     string           `hcl:"type,label"`
+	Name     string           `hcl:"name,label"`
+	Remain   hcl.Body         `hcl:",remain"`
+	DefRange hcl.Range        `hcl:",def_range"`
+	Context  *hcl.EvalContext `hcl:"-"`
+	// Token is the evaluated aws_dsql_token data source.
+	Token *DSQLToken
 }
 
 var _ Node = &Data{}
@@ -205,6 +308,7 @@ func (x *Data) Eval(ctx *hcl.EvalContext) (cty.Value, hcl.Diagnostics) {
 
 		// done
 		attr[x.Name] = value
+		x.Token = token
 	default:
 		return cty.Value{}, hcl.Diagnostics{
 			{
@@ -218,22 +322,6 @@ func (x *Data) Eval(ctx *hcl.EvalContext) (cty.Value, hcl.Diagnostics) {
 	return cty.ObjectVal(attr), nil
 }
 
-// Variable represents a 'variable' block.
-type Variable struct {
-	Name    string           `hcl:"name,label"`
-	Type    hcl.Expression   `hcl:"type"`
-	Default hcl.Expression   `hcl:"default,optional"`
-	Context *hcl.EvalContext `hcl:"-"`
-}
-
-var _ Node = &Variable{}
-
-// Eval evaluates the variable's default expression in the context of the provided evaluation context.
-func (x *Variable) Eval(ctx *hcl.EvalContext) (cty.Value, hcl.Diagnostics) {
-	x.Context = ctx
-	return x.Default.Value(ctx)
-}
-
 // GetValueMap retrieves the value map from a cty.Value.
 func GetValueMap(v cty.Value) map[string]cty.Value {
 	if v.IsNull() {
@@ -3,12 +3,16 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
 	"github.com/aws/aws-sdk-go-v2/feature/dsql/auth"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
 	"github.com/hashicorp/hcl/v2"
 	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
 )
 
 // DSQLToken represents a token used in the configuration.
@@ -16,54 +20,169 @@ type DSQLToken struct {
 	Username hcl.Expression `hcl:"username"`
 	Endpoint hcl.Expression `hcl:"endpoint"`
 	Region   hcl.Expression `hcl:"region"`
+	// Profile is the name of the shared configuration profile.
+	Profile hcl.Expression `hcl:"profile,optional"`
+	// RoleARN is the role to assume with the loaded credentials.
+	RoleARN hcl.Expression `hcl:"role_arn,optional"`
+	// ExternalID is the external id passed when the role is assumed.
+	ExternalID hcl.Expression `hcl:"external_id,optional"`
+	// SessionName is the name of the assumed role session.
+	SessionName hcl.Expression `hcl:"session_name,optional"`
+	// WebIdentityTokenFile is the file of the OIDC token used to assume the
+	// role, e.g. in CI.
+	WebIdentityTokenFile hcl.Expression `hcl:"web_identity_token_file,optional"`
+	// TokenExpiresIn is how long the generated tokens are valid (e.g. "15m").
+	TokenExpiresIn hcl.Expression `hcl:"token_expires_in,optional"`
+	// Config is the AWS configuration the token was generated with. It is
+	// used to generate a fresh token for each new connection.
+	Config aws.Config
+	// Host is the evaluated endpoint the token was generated for.
+	Host string
+	// User is the evaluated username the token was generated for.
+	User string
+	// Options are the options of the generated tokens.
+	Options []func(*auth.TokenOptions)
 }
 
 func (x *DSQLToken) Eval(ctx *hcl.EvalContext) (cty.Value, hcl.Diagnostics) {
-	username, err := x.Username.Value(ctx)
-	if err != nil {
-		return cty.Value{}, err
+	var diags hcl.Diagnostics
+
+	value := func(expr hcl.Expression) string {
+		text, err := GetString(ctx, expr)
+		diags = diags.Extend(err)
+		return text
 	}
 
-	endpoint, err := x.Endpoint.Value(ctx)
-	if err != nil {
-		return cty.Value{}, err
+	var (
+		username    = value(x.Username)
+		endpoint    = value(x.Endpoint)
+		region      = value(x.Region)
+		profile     = value(x.Profile)
+		roleARN     = value(x.RoleARN)
+		externalID  = value(x.ExternalID)
+		sessionName = value(x.SessionName)
+		tokenFile   = value(x.WebIdentityTokenFile)
+		expiresIn   = value(x.TokenExpiresIn)
+	)
+
+	if diags.HasErrors() {
+		return cty.Value{}, diags
 	}
 
-	region, err := x.Region.Value(ctx)
-	if err != nil {
-		return cty.Value{}, err
+	x.Options = nil
+	// The tokens are valid for 15 minutes by default
+	if expiresIn != "" {
+		duration, err := time.ParseDuration(expiresIn)
+		if err != nil || duration <= 0 {
+			return cty.Value{}, hcl.Diagnostics{
+				{
+					Severity:    hcl.DiagError,
+					Summary:     "Invalid token expiration",
+					Detail:      fmt.Sprintf("The token_expires_in attribute must be a positive duration (e.g. \"15m\"), got %q.", expiresIn),
+					Subject:     x.TokenExpiresIn.Range().Ptr(),
+					Expression:  x.TokenExpiresIn,
+					EvalContext: ctx,
+				},
+			}
+		}
+
+		x.Options = append(x.Options, func(options *auth.TokenOptions) {
+			options.ExpiresIn = duration
+		})
 	}
 
-	cfg, xerr := config.LoadDefaultConfig(context.Background(), config.WithRegion(region.AsString()))
+	switch {
+	case roleARN == "" && externalID != "":
+		return cty.Value{}, x.requires(ctx, x.ExternalID, "external_id", "role_arn")
+	case roleARN == "" && sessionName != "":
+		return cty.Value{}, x.requires(ctx, x.SessionName, "session_name", "role_arn")
+	case roleARN == "" && tokenFile != "":
+		return cty.Value{}, x.requires(ctx, x.WebIdentityTokenFile, "web_identity_token_file", "role_arn")
+	case tokenFile != "" && externalID != "":
+		return cty.Value{}, hcl.Diagnostics{
+			{
+				Severity:    hcl.DiagError,
+				Summary:     "Unsupported argument",
+				Detail:      "The external_id attribute cannot be used with web_identity_token_file.",
+				Subject:     x.ExternalID.Range().Ptr(),
+				Expression:  x.ExternalID,
+				EvalContext: ctx,
+			},
+		}
+	}
 
-	if xerr != nil {
-		fmt.Println("XERR", xerr)
+	options := []func(*config.LoadOptions) error{
+		config.WithRegion(region),
+	}
+
+	subject := x.Region
+	if profile != "" {
+		subject = x.Profile
+		options = append(options, config.WithSharedConfigProfile(profile))
+	}
+
+	cfg, err := config.LoadDefaultConfig(context.Background(), options...)
+	if err != nil {
 		return cty.Value{}, hcl.Diagnostics{
 			{
-				Severity: hcl.DiagError,
-				Summary:  "Unable to load AWS configuration",
-				Detail:   xerr.Error(),
+				Severity:    hcl.DiagError,
+				Summary:     "Unable to load AWS configuration",
+				Detail:      err.Error(),
+				Subject:     subject.Range().Ptr(),
+				Expression:  subject,
+				EvalContext: ctx,
 			},
 		}
 	}
 
+	switch {
+	case tokenFile != "":
+		// The role is assumed with the OIDC token of the CI
+		provider := stscreds.NewWebIdentityRoleProvider(sts.NewFromConfig(cfg), roleARN, stscreds.IdentityTokenFile(tokenFile),
+			func(options *stscreds.WebIdentityRoleOptions) {
+				options.RoleSessionName = sessionName
+			},
+		)
+
+		cfg.Credentials = aws.NewCredentialsCache(provider)
+	case roleARN != "":
+		// The role is assumed with the loaded credentials
+		provider := stscreds.NewAssumeRoleProvider(sts.NewFromConfig(cfg), roleARN,
+			func(options *stscreds.AssumeRoleOptions) {
+				options.RoleSessionName = sessionName
+
+				if externalID != "" {
+					options.ExternalID = aws.String(externalID)
+				}
+			},
+		)
+
+		cfg.Credentials = aws.NewCredentialsCache(provider)
+	}
+
+	x.Config = cfg
+	x.Host = endpoint
+	x.User = username
+
 	var authz DSQLAuthFunc
 
-	if username.AsString() == "admin" {
+	if username == "admin" {
 		authz = auth.GenerateDBConnectAdminAuthToken
 	} else {
 		authz = auth.GenerateDbConnectAuthToken
 	}
 
 	// build token
-	token, terr := authz(context.Background(), endpoint.AsString(), cfg.Region, cfg.Credentials)
-	if terr != nil {
-		fmt.Println("TERR", terr)
+	token, err := authz(context.Background(), endpoint, cfg.Region, cfg.Credentials, x.Options...)
+	if err != nil {
 		return cty.Value{}, hcl.Diagnostics{
 			{
-				Severity: hcl.DiagError,
-				Summary:  "Unable to generate DSQL authentication token",
-				Detail:   terr.Error(),
+				Severity:    hcl.DiagError,
+				Summary:     "Unable to generate DSQL authentication token",
+				Detail:      err.Error(),
+				Subject:     x.Endpoint.Range().Ptr(),
+				Expression:  x.Endpoint,
+				EvalContext: ctx,
 			},
 		}
 	}
@@ -71,5 +190,47 @@ func (x *DSQLToken) Eval(ctx *hcl.EvalContext) (cty.Value, hcl.Diagnostics) {
 	return cty.StringVal(token), nil
 }
 
+// requires returns the diagnostics of an attribute that is set without the
+// attribute it depends on.
+func (x *DSQLToken) requires(ctx *hcl.EvalContext, expr hcl.Expression, name, dependency string) hcl.Diagnostics {
+	return hcl.Diagnostics{
+		{
+			Severity:    hcl.DiagError,
+			Summary:     "Missing required argument",
+			Detail:      fmt.Sprintf("The %s attribute requires the %s attribute.", name, dependency),
+			Subject:     expr.Range().Ptr(),
+			Expression:  expr,
+			EvalContext: ctx,
+		},
+	}
+}
+
 // DSQLAuthFunc is a function that generates an authentication token for AWS DSQL.
 type DSQLAuthFunc func(ctx context.Context, endpoint, region string, creds aws.CredentialsProvider, optFns ...func(options *auth.TokenOptions)) (string, error)
+
+// GetString returns the string value of the expression, or an empty string
+// when the value is null.
+func GetString(ctx *hcl.EvalContext, expr hcl.Expression) (string, hcl.Diagnostics) {
+	value, diags := expr.Value(ctx)
+	if diags.HasErrors() {
+		return "", diags
+	}
+
+	if value.IsNull() {
+		return "", diags
+	}
+
+	value, err := convert.Convert(value, cty.String)
+	if err != nil || !value.IsKnown() {
+		return "", diags.Append(&hcl.Diagnostic{
+			Severity:    hcl.DiagError,
+			Summary:     "Incorrect attribute value type",
+			Detail:      "A string is required.",
+			Subject:     expr.Range().Ptr(),
+			Expression:  expr,
+			EvalContext: ctx,
+		})
+	}
+
+	return value.AsString(), diags
+}
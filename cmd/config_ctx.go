@@ -1,12 +1,17 @@
 package cmd
 
 import (
+	"encoding/base64"
+	"fmt"
 	"net/url"
 	"os"
+	"unicode/utf8"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
 	"github.com/zclconf/go-cty/cty/function"
+	"github.com/zclconf/go-cty/cty/function/stdlib"
 )
 
 var rootCtx = &hcl.EvalContext{
@@ -39,5 +44,88 @@ var rootCtx = &hcl.EvalContext{
 				return cty.StringVal(escaped), nil
 			},
 		}),
+		"base64encode": function.New(&function.Spec{
+			Params: []function.Parameter{
+				{
+					Name: "value",
+					Type: cty.String,
+				},
+			},
+			Type: function.StaticReturnType(cty.String),
+			Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+				s := args[0].AsString()
+				encoded := base64.StdEncoding.EncodeToString([]byte(s))
+				return cty.StringVal(encoded), nil
+			},
+		}),
+		"file": function.New(&function.Spec{
+			Params: []function.Parameter{
+				{
+					Name: "path",
+					Type: cty.String,
+				},
+			},
+			Type: function.StaticReturnType(cty.String),
+			Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+				// The path is relative to the working directory
+				data, err := os.ReadFile(args[0].AsString())
+				if err != nil {
+					return cty.NilVal, err
+				}
+
+				if !utf8.Valid(data) {
+					return cty.NilVal, fmt.Errorf("contents of %s are not valid UTF-8", args[0].AsString())
+				}
+
+				return cty.StringVal(string(data)), nil
+			},
+		}),
+		"coalesce": function.New(&function.Spec{
+			VarParam: &function.Parameter{
+				Name:             "values",
+				Type:             cty.DynamicPseudoType,
+				AllowNull:        true,
+				AllowDynamicType: true,
+			},
+			Type: func(args []cty.Value) (cty.Type, error) {
+				types := make([]cty.Type, len(args))
+				for i, arg := range args {
+					types[i] = arg.Type()
+				}
+
+				retType, _ := convert.UnifyUnsafe(types)
+				if retType == cty.NilType {
+					return cty.NilType, fmt.Errorf("all arguments must have the same type")
+				}
+
+				return retType, nil
+			},
+			Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+				// Unlike the cty function, empty strings are skipped as well,
+				// e.g. coalesce(getenv("NAME"), "default")
+				for _, arg := range args {
+					value, err := convert.Convert(arg, retType)
+					if err != nil {
+						return cty.NilVal, err
+					}
+
+					if value.IsNull() || (retType == cty.String && value.AsString() == "") {
+						continue
+					}
+
+					return value, nil
+				}
+
+				return cty.NilVal, fmt.Errorf("no non-null, non-empty arguments")
+			},
+		}),
+		"format":     stdlib.FormatFunc,
+		"join":       stdlib.JoinFunc,
+		"jsonencode": stdlib.JSONEncodeFunc,
+		"lower":      stdlib.LowerFunc,
+		"regex":      stdlib.RegexFunc,
+		"replace":    stdlib.ReplaceFunc,
+		"split":      stdlib.SplitFunc,
+		"trimspace":  stdlib.TrimSpaceFunc,
 	},
 }
@@ -0,0 +1,282 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// Vertex represents a block of the configuration in the dependency graph.
+type Vertex struct {
+	// Address is the address the block is referred to with (e.g.
+	// "data.aws_dsql_token.this").
+	Address string
+	// Node is the evaluated block.
+	Node Node
+	// Range is the range of the block definition.
+	Range hcl.Range
+	// Traversals are the references of the block expressions.
+	Traversals []hcl.Traversal
+	// Path is where the value of the block is stored in the context.
+	Path []string
+}
+
+// GetDependencies returns the addresses of the blocks the vertex refers to.
+func (x *Vertex) GetDependencies() []string {
+	var addresses []string
+
+	for _, traversal := range x.Traversals {
+		address := GetAddress(traversal)
+		// A validation condition refers to its own variable
+		if address == "" || address == x.Address || slices.Contains(addresses, address) {
+			continue
+		}
+
+		addresses = append(addresses, address)
+	}
+
+	return addresses
+}
+
+// Graph represents the dependency graph of the configuration blocks.
+type Graph struct {
+	Vertices []*Vertex
+}
+
+// GetGraph returns the dependency graph of the configuration blocks.
+func (c *Config) GetGraph() (*Graph, hcl.Diagnostics) {
+	graph := &Graph{}
+
+	for _, v := range c.Variables {
+		vertex := &Vertex{
+			Address: "var." + v.Name,
+			Node:    v,
+			Range:   v.DefRange,
+			Path:    []string{"var", v.Name},
+		}
+
+		// The default value is not used when the value is given
+		if v.Input == nil {
+			vertex.Traversals = append(vertex.Traversals, v.Default.Variables()...)
+		}
+
+		for _, validation := range v.Validations {
+			vertex.Traversals = append(vertex.Traversals, validation.Condition.Variables()...)
+			vertex.Traversals = append(vertex.Traversals, validation.ErrorMessage.Variables()...)
+		}
+
+		graph.Vertices = append(graph.Vertices, vertex)
+	}
+
+	locals, err := GetLocals(c.Locals)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, l := range locals {
+		graph.Vertices = append(graph.Vertices, &Vertex{
+			Address:    "local." + l.Name,
+			Node:       l,
+			Range:      l.Range,
+			Traversals: l.Expr.Variables(),
+			Path:       []string{"local", l.Name},
+		})
+	}
+
+	for _, d := range c.Data {
+		graph.Vertices = append(graph.Vertices, &Vertex{
+			Address:    "data." + d.Type + "." + d.Name,
+			Node:       d,
+			Range:      d.DefRange,
+			Traversals: GetBodyVariables(d.Remain),
+			// The data sources return the values of all the sources of their type
+			Path: []string{"data", d.Type},
+		})
+	}
+
+	for _, e := range c.Environments {
+		vertex := &Vertex{
+			Address: "env." + e.Name,
+			Node:    e,
+			Range:   e.DefRange,
+			Path:    []string{"env", e.Name},
+		}
+
+		vertex.Traversals = append(vertex.Traversals, e.URL.Variables()...)
+		vertex.Traversals = append(vertex.Traversals, e.Dialect.Variables()...)
+		vertex.Traversals = append(vertex.Traversals, e.EmulateJobs.Variables()...)
+
+		if e.Migration != nil {
+			vertex.Traversals = append(vertex.Traversals, e.Migration.Dir.Variables()...)
+		}
+
+		graph.Vertices = append(graph.Vertices, vertex)
+	}
+
+	return graph, nil
+}
+
+// GetVertex returns the vertex of an address, or nil.
+func (x *Graph) GetVertex(address string) *Vertex {
+	for _, vertex := range x.Vertices {
+		if vertex.Address == address {
+			return vertex
+		}
+	}
+
+	return nil
+}
+
+// Sort returns the vertices the given addresses depend on, and the vertices
+// themselves, so that every vertex comes after its dependencies. All the
+// vertices are returned when no address is given.
+func (x *Graph) Sort(addresses ...string) ([]*Vertex, hcl.Diagnostics) {
+	roots := x.Vertices
+	if len(addresses) > 0 {
+		roots = nil
+
+		for _, address := range addresses {
+			if vertex := x.GetVertex(address); vertex != nil {
+				roots = append(roots, vertex)
+			}
+		}
+	}
+
+	var (
+		sorted  []*Vertex
+		visited = make(map[*Vertex]bool)
+		path    []*Vertex
+	)
+
+	var visit func(vertex *Vertex) hcl.Diagnostics
+	visit = func(vertex *Vertex) hcl.Diagnostics {
+		if visited[vertex] {
+			return nil
+		}
+
+		if n := slices.Index(path, vertex); n >= 0 {
+			var names []string
+			for _, item := range append(path[n:], vertex) {
+				names = append(names, item.Address)
+			}
+
+			return hcl.Diagnostics{
+				{
+					Severity: hcl.DiagError,
+					Summary:  "Dependency cycle",
+					Detail:   fmt.Sprintf("The blocks depend on each other: %s.", strings.Join(names, " -> ")),
+					Subject:  vertex.Range.Ptr(),
+				},
+			}
+		}
+
+		path = append(path, vertex)
+		for _, address := range vertex.GetDependencies() {
+			// An unknown reference is reported when the expression is evaluated
+			if dependency := x.GetVertex(address); dependency != nil {
+				if diags := visit(dependency); diags.HasErrors() {
+					return diags
+				}
+			}
+		}
+		path = path[:len(path)-1]
+
+		visited[vertex] = true
+		sorted = append(sorted, vertex)
+		return nil
+	}
+
+	for _, vertex := range roots {
+		if diags := visit(vertex); diags.HasErrors() {
+			return nil, diags
+		}
+	}
+
+	return sorted, nil
+}
+
+// Eval evaluates the vertices in order, and stores their values in the context.
+func (x *Graph) Eval(ctx *hcl.EvalContext, vertices []*Vertex) hcl.Diagnostics {
+	for _, vertex := range vertices {
+		value, err := vertex.Node.Eval(ctx)
+		if err.HasErrors() {
+			return err
+		}
+
+		root, name := vertex.Path[0], vertex.Path[1]
+
+		kv := GetValueMap(ctx.Variables[root])
+		kv[name] = value
+		// Update the context with the evaluated block
+		ctx.Variables[root] = cty.ObjectVal(kv)
+	}
+
+	return nil
+}
+
+// GetAddress returns the address of the block a traversal refers to, or an
+// empty string.
+func GetAddress(traversal hcl.Traversal) string {
+	var size int
+
+	switch traversal.RootName() {
+	case "var", "local", "env":
+		size = 2
+	case "data":
+		size = 3
+	default:
+		return ""
+	}
+
+	names := []string{traversal.RootName()}
+	for _, step := range traversal[1:] {
+		if len(names) == size {
+			break
+		}
+
+		attr, ok := step.(hcl.TraverseAttr)
+		if !ok {
+			return ""
+		}
+
+		names = append(names, attr.Name)
+	}
+
+	if len(names) < size {
+		return ""
+	}
+
+	return strings.Join(names, ".")
+}
+
+// GetBodyVariables returns the references of all the expressions of a body,
+// including its nested blocks.
+func GetBodyVariables(body hcl.Body) []hcl.Traversal {
+	var traversals []hcl.Traversal
+
+	if body, ok := body.(*hclsyntax.Body); ok {
+		for _, attr := range body.Attributes {
+			traversals = append(traversals, attr.Expr.Variables()...)
+		}
+
+		for _, block := range body.Blocks {
+			traversals = append(traversals, GetBodyVariables(block.Body)...)
+		}
+	} else {
+		attributes, _ := body.JustAttributes()
+		for _, attr := range attributes {
+			traversals = append(traversals, attr.Expr.Variables()...)
+		}
+	}
+
+	// The attributes of a body are not ordered
+	slices.SortFunc(traversals, func(x, y hcl.Traversal) int {
+		return x.SourceRange().Start.Byte - y.SourceRange().Start.Byte
+	})
+
+	return traversals
+}
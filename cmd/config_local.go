@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// Locals represents a 'locals' block.
+type Locals struct {
+	Body hcl.Body `hcl:",remain"`
+}
+
+// Local represents a named value of a 'locals' block.
+type Local struct {
+	Name string
+	Expr hcl.Expression
+	// Range is the range of the attribute that defines the value.
+	Range hcl.Range
+}
+
+var _ Node = &Local{}
+
+// Eval evaluates the expression of the local value.
+func (x *Local) Eval(ctx *hcl.EvalContext) (cty.Value, hcl.Diagnostics) {
+	return x.Expr.Value(ctx)
+}
+
+// GetLocals returns the local values of the blocks in the order they are
+// defined.
+func GetLocals(blocks []*Locals) ([]*Local, hcl.Diagnostics) {
+	var (
+		diags  hcl.Diagnostics
+		locals []*Local
+	)
+
+	for _, block := range blocks {
+		attributes, err := block.Body.JustAttributes()
+		if diags = diags.Extend(err); err.HasErrors() {
+			continue
+		}
+
+		for _, attr := range attributes {
+			locals = append(locals, &Local{
+				Name:  attr.Name,
+				Expr:  attr.Expr,
+				Range: attr.Range,
+			})
+		}
+	}
+
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	// The attributes of a body are not ordered
+	slices.SortFunc(locals, func(x, y *Local) int {
+		if n := strings.Compare(x.Range.Filename, y.Range.Filename); n != 0 {
+			return n
+		}
+
+		return x.Range.Start.Byte - y.Range.Start.Byte
+	})
+
+	index := make(map[string]*Local)
+	for _, local := range locals {
+		if prev, ok := index[local.Name]; ok {
+			return nil, hcl.Diagnostics{
+				{
+					Severity: hcl.DiagError,
+					Summary:  "Duplicate local value definition",
+					Detail:   fmt.Sprintf("A local value named %q was already defined at %s.", local.Name, prev.Range),
+					Subject:  local.Range.Ptr(),
+				},
+			}
+		}
+
+		index[local.Name] = local
+	}
+
+	return locals, nil
+}
@@ -0,0 +1,185 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/ext/typeexpr"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+)
+
+// VariableEnvPrefix is the prefix of the environment variables that set the
+// value of a variable (e.g. AURORA_VAR_aws_region).
+const VariableEnvPrefix = "AURORA_VAR_"
+
+// Variable represents a 'variable' block.
+type Variable struct {
+	Name        string           `hcl:"name,label"`
+	Type        hcl.Expression   `hcl:"type"`
+	Default     hcl.Expression   `hcl:"default,optional"`
+	Validations []*Validation    `hcl:"validation,block"`
+	DefRange    hcl.Range        `hcl:",def_range"`
+	Context     *hcl.EvalContext `hcl:"-"`
+	// Input is the value given on the command line or in the environment.
+	// It takes precedence over the default value.
+	Input *VariableInput
+}
+
+// VariableInput represents a value of a variable given as text.
+type VariableInput struct {
+	// Source is where the value comes from (e.g. "--var aws_region").
+	Source string
+	// Value is the text of the value.
+	Value string
+}
+
+// Validation represents a 'validation' block of a variable.
+type Validation struct {
+	Condition    hcl.Expression `hcl:"condition"`
+	ErrorMessage hcl.Expression `hcl:"error_message"`
+}
+
+var _ Node = &Variable{}
+
+// Eval evaluates the value of the variable, converts it to the declared type
+// and checks its validations.
+func (x *Variable) Eval(ctx *hcl.EvalContext) (cty.Value, hcl.Diagnostics) {
+	x.Context = ctx
+
+	kind, diags := typeexpr.TypeConstraint(x.Type)
+	if diags.HasErrors() {
+		return cty.Value{}, diags
+	}
+
+	value, subject, diags := x.value(ctx, kind)
+	if diags.HasErrors() {
+		return cty.Value{}, diags
+	}
+
+	value, err := convert.Convert(value, kind)
+	if err != nil {
+		return cty.Value{}, hcl.Diagnostics{
+			{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid value for variable",
+				Detail:   fmt.Sprintf("The value of variable %q is not a valid %s: %s.", x.Name, kind.FriendlyName(), err),
+				Subject:  subject.Ptr(),
+			},
+		}
+	}
+
+	return value, x.validate(ctx, value)
+}
+
+// value returns the value of the variable before its conversion, and the
+// range of the expression it comes from.
+func (x *Variable) value(ctx *hcl.EvalContext, kind cty.Type) (cty.Value, hcl.Range, hcl.Diagnostics) {
+	if input := x.Input; input != nil {
+		subject := hcl.Range{Filename: input.Source, Start: hcl.InitialPos, End: hcl.InitialPos}
+		// The strings are taken as is, any other type is an HCL expression
+		if kind == cty.String {
+			return cty.StringVal(input.Value), subject, nil
+		}
+
+		// A text that is not a literal expression (e.g. --var dialect=postgres)
+		// is a string, that the conversion to the type reports if needed
+		expr, diags := hclsyntax.ParseExpression([]byte(input.Value), input.Source, hcl.InitialPos)
+		if diags.HasErrors() {
+			return cty.StringVal(input.Value), subject, nil
+		}
+
+		value, diags := expr.Value(nil)
+		if diags.HasErrors() {
+			return cty.StringVal(input.Value), subject, nil
+		}
+
+		return value, expr.Range(), diags
+	}
+
+	value, diags := x.Default.Value(ctx)
+	if diags.HasErrors() {
+		return cty.Value{}, x.Default.Range(), diags
+	}
+
+	if value.IsNull() {
+		return cty.Value{}, x.DefRange, hcl.Diagnostics{
+			{
+				Severity: hcl.DiagError,
+				Summary:  "Missing required variable",
+				Detail:   fmt.Sprintf("The variable %q has no default value. Set it with --var %s=<value> or the %s%s environment variable.", x.Name, x.Name, VariableEnvPrefix, x.Name),
+				Subject:  x.DefRange.Ptr(),
+			},
+		}
+	}
+
+	return value, x.Default.Range(), diags
+}
+
+// validate checks the value against the validation blocks.
+func (x *Variable) validate(ctx *hcl.EvalContext, value cty.Value) (diags hcl.Diagnostics) {
+	// The conditions refer to the value being validated
+	kv := GetValueMap(ctx.Variables["var"])
+	kv[x.Name] = value
+
+	child := ctx.NewChild()
+	child.Variables = map[string]cty.Value{
+		"var": cty.ObjectVal(kv),
+	}
+
+	for _, validation := range x.Validations {
+		condition, err := validation.Condition.Value(child)
+		if diags = diags.Extend(err); err.HasErrors() {
+			continue
+		}
+
+		condition, cerr := convert.Convert(condition, cty.Bool)
+		if cerr != nil || condition.IsNull() || !condition.IsKnown() {
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity:    hcl.DiagError,
+				Summary:     "Invalid validation condition",
+				Detail:      "The condition must return true or false.",
+				Subject:     validation.Condition.Range().Ptr(),
+				Expression:  validation.Condition,
+				EvalContext: child,
+			})
+			continue
+		}
+
+		if condition.True() {
+			continue
+		}
+
+		msg, err := GetString(child, validation.ErrorMessage)
+		if diags = diags.Extend(err); err.HasErrors() {
+			continue
+		}
+
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity:    hcl.DiagError,
+			Summary:     "Invalid value for variable",
+			Detail:      fmt.Sprintf("%s\n\nThis was checked by the validation rule at %s.", msg, validation.Condition.Range()),
+			Subject:     validation.Condition.Range().Ptr(),
+			Expression:  validation.Condition,
+			EvalContext: child,
+		})
+	}
+
+	return diags
+}
+
+// GetVariableInput returns the value of the variable given in the values,
+// or in the environment, or nil.
+func GetVariableInput(values map[string]string, name string) *VariableInput {
+	if value, ok := values[name]; ok {
+		return &VariableInput{Source: "--var " + name, Value: value}
+	}
+
+	if value, ok := os.LookupEnv(VariableEnvPrefix + name); ok {
+		return &VariableInput{Source: VariableEnvPrefix + name, Value: value}
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+package ent
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// DefaultBackoff is the backoff policy used when none is provided.
+var DefaultBackoff = &Backoff{
+	Initial:    100 * time.Millisecond,
+	Max:        5 * time.Second,
+	Multiplier: 2,
+	Jitter:     0.2,
+}
+
+// Backoff represents an exponential backoff policy with jitter.
+type Backoff struct {
+	// Initial is the interval before the first retry.
+	Initial time.Duration
+	// Max is the maximum interval between retries.
+	Max time.Duration
+	// Multiplier is the factor applied to the interval after each retry.
+	Multiplier float64
+	// Jitter is the fraction of the interval that is randomized, between 0 and 1.
+	Jitter float64
+}
+
+// Interval returns the interval to wait before the given attempt, starting at 0.
+func (x *Backoff) Interval(attempt int) time.Duration {
+	interval := float64(x.Initial) * math.Pow(max(x.Multiplier, 1), float64(attempt))
+	if x.Max > 0 {
+		interval = min(interval, float64(x.Max))
+	}
+
+	if x.Jitter > 0 {
+		// spread the interval over [interval * (1 - jitter), interval * (1 + jitter)]
+		interval += interval * x.Jitter * (2*rand.Float64() - 1)
+	}
+
+	if x.Max > 0 {
+		interval = min(interval, float64(x.Max))
+	}
+
+	return time.Duration(interval)
+}
+
+// RetryPolicy represents the policy for retrying the operations that fail
+// with a serialization failure (SQLSTATE 40001). Aurora DSQL reports its
+// optimistic concurrency conflicts with it, with the OC000 or OC001 code in
+// the message, and PostgreSQL reports the conflicts of concurrent
+// transactions with it. The OC codes are not inspected: every serialization
+// failure is retried.
+type RetryPolicy struct {
+	// Limit is the maximum number of retries.
+	Limit int
+	// Backoff is the policy for the interval between retries. Defaults to DefaultBackoff.
+	Backoff *Backoff
+	// OnRetry is called before each retry with the attempt number, starting at 1.
+	OnRetry func(attempt int, err error)
+}
+
+// Do calls fn and retries it while it fails with a serialization failure, up
+// to the limit. The error returned after one or more retries reports their number.
+// A nil policy calls fn once.
+func (x *RetryPolicy) Do(ctx context.Context, fn func() error) error {
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		switch {
+		case err == nil:
+			return nil
+		case x == nil:
+			return err
+		case !IsErrorCode(err, ErrCodeSerializationFailure):
+		case attempt < x.Limit:
+			if x.OnRetry != nil {
+				x.OnRetry(attempt+1, err)
+			}
+
+			backoff := x.Backoff
+			if backoff == nil {
+				backoff = DefaultBackoff
+			}
+
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(backoff.Interval(attempt)):
+			}
+
+			continue
+		}
+
+		switch attempt {
+		case 0:
+		case 1:
+			return fmt.Errorf("%w (retried once)", err)
+		default:
+			return fmt.Errorf("%w (retried %d times)", err, attempt)
+		}
+
+		return err
+	}
+}
@@ -0,0 +1,123 @@
+package ent_test
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aws-contrib/aurora/internal/database/ent"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Backoff", func() {
+	var backoff *ent.Backoff
+
+	BeforeEach(func() {
+		backoff = &ent.Backoff{
+			Initial:    100 * time.Millisecond,
+			Max:        time.Second,
+			Multiplier: 2,
+		}
+	})
+
+	Describe("Interval", func() {
+		It("grows exponentially", func() {
+			Expect(backoff.Interval(0)).To(Equal(100 * time.Millisecond))
+			Expect(backoff.Interval(1)).To(Equal(200 * time.Millisecond))
+			Expect(backoff.Interval(2)).To(Equal(400 * time.Millisecond))
+		})
+
+		It("does not exceed the max interval", func() {
+			Expect(backoff.Interval(10)).To(Equal(time.Second))
+		})
+
+		When("the jitter is set", func() {
+			BeforeEach(func() {
+				backoff.Jitter = 0.5
+			})
+
+			It("randomizes the interval", func() {
+				for range 100 {
+					Expect(backoff.Interval(1)).To(BeNumerically("~", 200*time.Millisecond, 100*time.Millisecond))
+					Expect(backoff.Interval(10)).To(BeNumerically("<=", time.Second))
+				}
+			})
+		})
+	})
+})
+
+var _ = Describe("RetryPolicy", func() {
+	var (
+		policy  *ent.RetryPolicy
+		retries []int
+	)
+
+	BeforeEach(func() {
+		retries = nil
+		policy = &ent.RetryPolicy{
+			Limit:   2,
+			Backoff: &ent.Backoff{Initial: time.Millisecond},
+			OnRetry: func(attempt int, _ error) {
+				retries = append(retries, attempt)
+			},
+		}
+	})
+
+	Describe("Do", func() {
+		conflict := &ent.Error{Code: ent.ErrCodeSerializationFailure, Message: "change conflicts with another transaction (OC000)"}
+
+		It("retries the conflicts", func(ctx SpecContext) {
+			var calls int
+			Expect(policy.Do(ctx, func() error {
+				if calls++; calls < 3 {
+					return conflict
+				}
+				return nil
+			})).To(Succeed())
+
+			Expect(calls).To(Equal(3))
+			Expect(retries).To(Equal([]int{1, 2}))
+		})
+
+		When("the serialization failure has no OC code", func() {
+			It("retries it", func(ctx SpecContext) {
+				failure := &ent.Error{Code: ent.ErrCodeSerializationFailure, Message: "could not serialize access due to concurrent update"}
+
+				var calls int
+				Expect(policy.Do(ctx, func() error {
+					if calls++; calls < 2 {
+						return failure
+					}
+					return nil
+				})).To(Succeed())
+
+				Expect(calls).To(Equal(2))
+				Expect(retries).To(Equal([]int{1}))
+			})
+		})
+
+		When("the limit is reached", func() {
+			It("returns an error", func(ctx SpecContext) {
+				err := policy.Do(ctx, func() error { return conflict })
+				Expect(err).To(MatchError(ContainSubstring("(retried 2 times)")))
+				Expect(ent.IsErrorCode(err, ent.ErrCodeSerializationFailure)).To(BeTrue())
+			})
+		})
+
+		When("the error is not a conflict", func() {
+			It("returns an error", func(ctx SpecContext) {
+				err := policy.Do(ctx, func() error { return fmt.Errorf("oh no") })
+				Expect(err).To(MatchError("oh no"))
+				Expect(retries).To(BeEmpty())
+			})
+		})
+
+		When("the policy is nil", func() {
+			It("does not retry", func(ctx SpecContext) {
+				policy = nil
+				Expect(policy.Do(ctx, func() error { return conflict })).To(MatchError(conflict))
+			})
+		})
+	})
+})
@@ -8,13 +8,15 @@ import (
 	"context"
 	"os"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dsql/auth"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 //go:generate go tool goverter gen .
 //go:generate go tool counterfeiter -generate
-//go:generate go tool gomarkdoc --output README.md .
+//go:generate go tool gomarkdoc --repository.url https://github.com/aws-contrib/aurora --repository.default-branch main --repository.path /internal/database/ent/ --output README.md .
 
 // GatewayOption represents a gateway option.
 type GatewayOption interface {
@@ -32,6 +34,33 @@ func (fn GatewayOptionFunc) Apply(cfg *pgxpool.Config) error {
 	return fn(cfg)
 }
 
+// WithDSQLToken returns a GatewayOption that generates a fresh Aurora DSQL
+// authentication token of the given endpoint and username for each new
+// connection, so that the connections opened after the token of the URL has
+// expired can still authenticate. The token is signed for the endpoint, not
+// for the host of the connection, which can be a proxy or a tunnel. The token
+// of the admin user is generated with the admin action.
+func WithDSQLToken(endpoint, username string, cfg aws.Config, options ...func(*auth.TokenOptions)) GatewayOption {
+	return GatewayOptionFunc(func(config *pgxpool.Config) error {
+		config.BeforeConnect = func(ctx context.Context, conn *pgx.ConnConfig) error {
+			generate := auth.GenerateDbConnectAuthToken
+			if username == "admin" {
+				generate = auth.GenerateDBConnectAdminAuthToken
+			}
+
+			token, err := generate(ctx, endpoint, cfg.Region, cfg.Credentials, options...)
+			if err != nil {
+				return err
+			}
+
+			conn.Password = token
+			return nil
+		}
+
+		return nil
+	})
+}
+
 // WithURL returns the database URL.
 func WithURL() string {
 	keys := []string{
@@ -0,0 +1,23 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: dialect.sql
+
+package ent
+
+import (
+	"context"
+)
+
+const hasIAMRoleMappings = `-- name: HasIAMRoleMappings :one
+SELECT (to_regclass('sys.iam_pg_role_mappings') IS NOT NULL)::BOOLEAN AS exists
+`
+
+// Reports whether the database provides the view 'sys.iam_pg_role_mappings',
+// which only Aurora DSQL has
+func (q *Queries) HasIAMRoleMappings(ctx context.Context) (bool, error) {
+	row := q.db.QueryRow(ctx, hasIAMRoleMappings)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
@@ -0,0 +1,30 @@
+package ent_test
+
+import (
+	"github.com/aws-contrib/aurora/internal/database/ent"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Gateway", Ordered, func() {
+	var gateway ent.Gateway
+
+	BeforeEach(func() {
+		var err error
+		gateway, err = NewGateway()
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		gateway.Close()
+	})
+
+	Describe("Dialect", func() {
+		Describe("HasIAMRoleMappings", func() {
+			It("reports that PostgreSQL has no IAM role mappings", func(ctx SpecContext) {
+				Expect(gateway.HasIAMRoleMappings(ctx)).To(BeFalse())
+			})
+		})
+	})
+})
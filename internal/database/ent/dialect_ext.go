@@ -0,0 +1,65 @@
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Dialect represents the SQL dialect of the database.
+type Dialect string
+
+const (
+	// DialectDSQL is the dialect of Aurora DSQL. The indexes are built by
+	// asynchronous jobs that are tracked in sys.jobs.
+	DialectDSQL Dialect = "dsql"
+	// DialectPostgres is the dialect of PostgreSQL. The indexes are built
+	// concurrently and there are no asynchronous jobs.
+	DialectPostgres Dialect = "postgres"
+)
+
+var dsqlHostRegexp = regexp.MustCompile(`\.dsql(-[a-z0-9]+)?\.[a-z0-9-]+\.on\.aws$`)
+
+// ParseDialect returns the dialect with the given name.
+func ParseDialect(name string) (Dialect, error) {
+	switch dialect := Dialect(strings.ToLower(name)); dialect {
+	case DialectDSQL, DialectPostgres:
+		return dialect, nil
+	default:
+		return "", fmt.Errorf("unsupported dialect %q", name)
+	}
+}
+
+// DetectDialect returns the dialect of the database at the given URL. The
+// Aurora DSQL endpoints are recognized by their host name. For any other
+// host, such as a proxy or a custom domain, the server is asked whether it
+// provides the views of Aurora DSQL. DialectDSQL is kept when the server
+// cannot answer.
+func DetectDialect(ctx context.Context, querier Querier, uri string) (Dialect, error) {
+	config, err := pgconn.ParseConfig(uri)
+	if err != nil {
+		return "", err
+	}
+
+	if dsqlHostRegexp.MatchString(strings.ToLower(config.Host)) {
+		return DialectDSQL, nil
+	}
+
+	ok, err := querier.HasIAMRoleMappings(ctx)
+	var pgerr *Error
+	switch {
+	case errors.As(err, &pgerr):
+		// The server does not support the probe
+		return DialectDSQL, nil
+	case err != nil:
+		return "", err
+	case ok:
+		return DialectDSQL, nil
+	default:
+		return DialectPostgres, nil
+	}
+}
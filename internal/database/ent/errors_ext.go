@@ -13,6 +13,14 @@ var (
 	ErrTooManyRows = pgx.ErrTooManyRows
 	// ErrNoRows occurs when rows are expected but none are returned.
 	ErrNoRows = pgx.ErrNoRows
+	// ErrNoDownScript occurs when a revision that has no down statements is reverted.
+	ErrNoDownScript = errors.New("no down script")
+	// ErrChecksumMismatch occurs when the migration directory does not match its integrity file.
+	ErrChecksumMismatch = errors.New("checksum mismatch")
+	// ErrLockLost occurs when the migration lock was taken over by another process while it was held.
+	ErrLockLost = errors.New("migration lock lost")
+	// ErrLockChanged occurs when the migration lock was released or taken over after it was read.
+	ErrLockChanged = errors.New("migration lock changed")
 )
 
 // IsErrorNotFound reports whether the error is a "not found" error.
@@ -25,6 +33,18 @@ type Error = pgconn.PgError
 
 var ErrCodeUniqueViolation = pgerrcode.UniqueViolation
 
+// ErrCodeSerializationFailure is reported when a transaction conflicts with a
+// concurrent one. Aurora DSQL reports optimistic concurrency conflicts with it.
+var ErrCodeSerializationFailure = pgerrcode.SerializationFailure
+
+// ErrCodeUndefinedTable is reported when a table does not exist, such as the
+// migration tables of a database no migration was applied to.
+var ErrCodeUndefinedTable = pgerrcode.UndefinedTable
+
+// ErrCodeUndefinedColumn is reported when a column does not exist, such as the
+// columns added to the migration tables by a newer version of aurora.
+var ErrCodeUndefinedColumn = pgerrcode.UndefinedColumn
+
 // IsErrorCode reports whether the error is a PostgreSQL error with the given code.
 func IsErrorCode(err error, code string) bool {
 	var pgerr *Error
@@ -0,0 +1,301 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: event.sql
+
+package ent
+
+import (
+	"context"
+	"time"
+)
+
+const createTableRevisionEvents = `-- name: CreateTableRevisionEvents :exec
+CREATE TABLE IF NOT EXISTS aurora_schema_revision_events (
+    -- primary key column
+    id TEXT PRIMARY KEY,
+    -- revision of the event
+    revision_id TEXT NOT NULL,
+    -- action that was taken on the revision
+    action TEXT NOT NULL,
+    -- error issued by the action
+    error TEXT NULL,
+    -- operator who took the action
+    operator TEXT NULL,
+    -- version of the tool that took the action
+    version TEXT NULL,
+    -- creation timestamp column
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    -- position of the statement in the migration file
+    statement_index INT NULL,
+    -- statement that was executed
+    statement TEXT NULL,
+    -- asynchronous job created by the statement
+    job_id TEXT NULL,
+    -- outcome of the action
+    outcome TEXT NOT NULL,
+    -- start timestamp column
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    -- end timestamp column
+    finished_at TIMESTAMP WITH TIME ZONE NULL
+)
+`
+
+// Creates a table named 'aurora_schema_revision_events' with the following columns:
+func (q *Queries) CreateTableRevisionEvents(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, createTableRevisionEvents)
+	return err
+}
+
+const execInsertRevisionEvent = `-- name: ExecInsertRevisionEvent :exec
+INSERT INTO aurora_schema_revision_events (
+    id,
+    revision_id,
+    action,
+    error,
+    operator,
+    version,
+    created_at,
+    statement_index,
+    statement,
+    job_id,
+    outcome,
+    started_at,
+    finished_at
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8,
+    $9,
+    $10,
+    $11,
+    $12,
+    $13
+)
+`
+
+type ExecInsertRevisionEventParams struct {
+	ID             string     `db:"id" json:"id"`
+	RevisionID     string     `db:"revision_id" json:"revision_id"`
+	Action         string     `db:"action" json:"action"`
+	Error          *string    `db:"error" json:"error"`
+	Operator       *string    `db:"operator" json:"operator"`
+	Version        *string    `db:"version" json:"version"`
+	CreatedAt      time.Time  `db:"created_at" json:"created_at"`
+	StatementIndex *int       `db:"statement_index" json:"statement_index"`
+	Statement      *string    `db:"statement" json:"statement"`
+	JobID          *string    `db:"job_id" json:"job_id"`
+	Outcome        string     `db:"outcome" json:"outcome"`
+	StartedAt      time.Time  `db:"started_at" json:"started_at"`
+	FinishedAt     *time.Time `db:"finished_at" json:"finished_at"`
+}
+
+// Inserts a row into the table 'aurora_schema_revision_events' with option ':exec'
+func (q *Queries) ExecInsertRevisionEvent(ctx context.Context, arg *ExecInsertRevisionEventParams) error {
+	_, err := q.db.Exec(ctx, execInsertRevisionEvent,
+		arg.ID,
+		arg.RevisionID,
+		arg.Action,
+		arg.Error,
+		arg.Operator,
+		arg.Version,
+		arg.CreatedAt,
+		arg.StatementIndex,
+		arg.Statement,
+		arg.JobID,
+		arg.Outcome,
+		arg.StartedAt,
+		arg.FinishedAt,
+	)
+	return err
+}
+
+const execUpdateRevisionEvent = `-- name: ExecUpdateRevisionEvent :exec
+UPDATE aurora_schema_revision_events
+SET
+    job_id = $1,
+    outcome = $2,
+    error = $3,
+    finished_at = $4
+WHERE
+    id = $5
+`
+
+type ExecUpdateRevisionEventParams struct {
+	JobID      *string    `db:"job_id" json:"job_id"`
+	Outcome    string     `db:"outcome" json:"outcome"`
+	Error      *string    `db:"error" json:"error"`
+	FinishedAt *time.Time `db:"finished_at" json:"finished_at"`
+	ID         string     `db:"id" json:"id"`
+}
+
+// Records the outcome of a row of the table 'aurora_schema_revision_events' once its action is finished
+func (q *Queries) ExecUpdateRevisionEvent(ctx context.Context, arg *ExecUpdateRevisionEventParams) error {
+	_, err := q.db.Exec(ctx, execUpdateRevisionEvent,
+		arg.JobID,
+		arg.Outcome,
+		arg.Error,
+		arg.FinishedAt,
+		arg.ID,
+	)
+	return err
+}
+
+const insertRevisionEvent = `-- name: InsertRevisionEvent :one
+INSERT INTO aurora_schema_revision_events (
+    id,
+    revision_id,
+    action,
+    error,
+    operator,
+    version,
+    created_at,
+    statement_index,
+    statement,
+    job_id,
+    outcome,
+    started_at,
+    finished_at
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8,
+    $9,
+    $10,
+    $11,
+    $12,
+    $13
+)
+RETURNING id, revision_id, action, error, operator, version, created_at, statement_index, statement, job_id, outcome, started_at, finished_at
+`
+
+type InsertRevisionEventParams struct {
+	ID             string     `db:"id" json:"id"`
+	RevisionID     string     `db:"revision_id" json:"revision_id"`
+	Action         string     `db:"action" json:"action"`
+	Error          *string    `db:"error" json:"error"`
+	Operator       *string    `db:"operator" json:"operator"`
+	Version        *string    `db:"version" json:"version"`
+	CreatedAt      time.Time  `db:"created_at" json:"created_at"`
+	StatementIndex *int       `db:"statement_index" json:"statement_index"`
+	Statement      *string    `db:"statement" json:"statement"`
+	JobID          *string    `db:"job_id" json:"job_id"`
+	Outcome        string     `db:"outcome" json:"outcome"`
+	StartedAt      time.Time  `db:"started_at" json:"started_at"`
+	FinishedAt     *time.Time `db:"finished_at" json:"finished_at"`
+}
+
+// Inserts a row into the table 'aurora_schema_revision_events' with option ':one'
+func (q *Queries) InsertRevisionEvent(ctx context.Context, arg *InsertRevisionEventParams) (*RevisionEvent, error) {
+	row := q.db.QueryRow(ctx, insertRevisionEvent,
+		arg.ID,
+		arg.RevisionID,
+		arg.Action,
+		arg.Error,
+		arg.Operator,
+		arg.Version,
+		arg.CreatedAt,
+		arg.StatementIndex,
+		arg.Statement,
+		arg.JobID,
+		arg.Outcome,
+		arg.StartedAt,
+		arg.FinishedAt,
+	)
+	var i RevisionEvent
+	err := row.Scan(
+		&i.ID,
+		&i.RevisionID,
+		&i.Action,
+		&i.Error,
+		&i.Operator,
+		&i.Version,
+		&i.CreatedAt,
+		&i.StatementIndex,
+		&i.Statement,
+		&i.JobID,
+		&i.Outcome,
+		&i.StartedAt,
+		&i.FinishedAt,
+	)
+	return &i, err
+}
+
+const listRevisionEvents = `-- name: ListRevisionEvents :many
+SELECT
+    id,
+    revision_id,
+    action,
+    error,
+    operator,
+    version,
+    created_at,
+    statement_index,
+    statement,
+    job_id,
+    outcome,
+    started_at,
+    finished_at
+FROM
+    aurora_schema_revision_events
+WHERE
+    revision_id = COALESCE($1, revision_id)
+ORDER BY
+    started_at,
+    id
+LIMIT
+    $3::INT
+    OFFSET
+    $2::INT
+`
+
+type ListRevisionEventsParams struct {
+	RevisionID *string `db:"revision_id" json:"revision_id"`
+	PageOffset *int32  `db:"page_offset" json:"page_offset"`
+	PageLimit  *int32  `db:"page_limit" json:"page_limit"`
+}
+
+// Retrieves a list of rows from the table 'aurora_schema_revision_events' with option ':many'
+func (q *Queries) ListRevisionEvents(ctx context.Context, arg *ListRevisionEventsParams) ([]*RevisionEvent, error) {
+	rows, err := q.db.Query(ctx, listRevisionEvents, arg.RevisionID, arg.PageOffset, arg.PageLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*RevisionEvent{}
+	for rows.Next() {
+		var i RevisionEvent
+		if err := rows.Scan(
+			&i.ID,
+			&i.RevisionID,
+			&i.Action,
+			&i.Error,
+			&i.Operator,
+			&i.Version,
+			&i.CreatedAt,
+			&i.StatementIndex,
+			&i.Statement,
+			&i.JobID,
+			&i.Outcome,
+			&i.StartedAt,
+			&i.FinishedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,93 @@
+package ent_test
+
+import (
+	"github.com/aws-contrib/aurora/internal/database/ent"
+
+	. "github.com/aws-contrib/aurora/internal/database/ent/fake"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Gateway", Ordered, func() {
+	var gateway ent.Gateway
+
+	BeforeEach(func() {
+		var err error
+		gateway, err = NewGateway()
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		gateway.Close()
+	})
+
+	Describe("RevisionEvent", func() {
+		var entity *ent.RevisionEvent
+
+		BeforeAll(func() {
+			entity = NewFakeRevisionEvent()
+		})
+
+		Describe("CreateTableRevisionEvents", func() {
+			It("creates the aurora_schema_revision_events table", func(ctx SpecContext) {
+				Expect(gateway.CreateTableRevisionEvents(ctx)).To(Succeed())
+			})
+		})
+
+		Describe("InsertRevisionEvent", func() {
+			var params *ent.InsertRevisionEventParams
+
+			BeforeEach(func() {
+				params = &ent.InsertRevisionEventParams{}
+				params.SetRevisionEvent(entity)
+			})
+
+			It("inserts a revision event", func(ctx SpecContext) {
+				event, err := gateway.InsertRevisionEvent(ctx, params)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(event).To(BeComparableTo(entity))
+			})
+		})
+
+		Describe("ListRevisionEvents", func() {
+			var params *ent.ListRevisionEventsParams
+
+			BeforeEach(func() {
+				params = &ent.ListRevisionEventsParams{}
+				params.RevisionID = &entity.RevisionID
+			})
+
+			It("returns the revision events", func(ctx SpecContext) {
+				events, err := gateway.ListRevisionEvents(ctx, params)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(events).To(ContainElement(BeComparableTo(entity)))
+			})
+		})
+
+		Describe("ExecInsertRevisionEvent", func() {
+			var params *ent.ExecInsertRevisionEventParams
+
+			BeforeEach(func() {
+				params = &ent.ExecInsertRevisionEventParams{}
+				params.SetRevisionEvent(NewFakeRevisionEvent())
+			})
+
+			It("inserts a revision event", func(ctx SpecContext) {
+				Expect(gateway.ExecInsertRevisionEvent(ctx, params)).To(Succeed())
+			})
+		})
+
+		Describe("ExecUpdateRevisionEvent", func() {
+			var params *ent.ExecUpdateRevisionEventParams
+
+			BeforeEach(func() {
+				params = &ent.ExecUpdateRevisionEventParams{}
+				params.SetRevisionEvent(entity)
+			})
+
+			It("updates a revision event", func(ctx SpecContext) {
+				Expect(gateway.ExecUpdateRevisionEvent(ctx, params)).To(Succeed())
+			})
+		})
+	})
+})
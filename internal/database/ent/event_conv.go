@@ -0,0 +1,28 @@
+package ent
+
+// goverter:converter
+// goverter:skipCopySameType yes
+// goverter:output:file models_conv_gen.go
+// goverter:output:package github.com/aws-contrib/aurora/internal/database/ent
+type InsertRevisionEventParamsConverter interface {
+	// goverter:update target
+	SetFromRevisionEvent(target *InsertRevisionEventParams, source *RevisionEvent)
+}
+
+// goverter:converter
+// goverter:skipCopySameType yes
+// goverter:output:file models_conv_gen.go
+// goverter:output:package github.com/aws-contrib/aurora/internal/database/ent
+type ExecInsertRevisionEventParamsConverter interface {
+	// goverter:update target
+	SetFromRevisionEvent(target *ExecInsertRevisionEventParams, source *RevisionEvent)
+}
+
+// goverter:converter
+// goverter:skipCopySameType yes
+// goverter:output:file models_conv_gen.go
+// goverter:output:package github.com/aws-contrib/aurora/internal/database/ent
+type ExecUpdateRevisionEventParamsConverter interface {
+	// goverter:update target
+	SetFromRevisionEvent(target *ExecUpdateRevisionEventParams, source *RevisionEvent)
+}
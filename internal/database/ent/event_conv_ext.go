@@ -0,0 +1,21 @@
+//go:build !goverter
+
+package ent
+
+// SetRevisionEvent sets the params from the entity.
+func (x *InsertRevisionEventParams) SetRevisionEvent(entity *RevisionEvent) {
+	converter := &InsertRevisionEventParamsConverterImpl{}
+	converter.SetFromRevisionEvent(x, entity)
+}
+
+// SetRevisionEvent sets the params from the entity.
+func (x *ExecInsertRevisionEventParams) SetRevisionEvent(entity *RevisionEvent) {
+	converter := &ExecInsertRevisionEventParamsConverterImpl{}
+	converter.SetFromRevisionEvent(x, entity)
+}
+
+// SetRevisionEvent sets the params from the entity.
+func (x *ExecUpdateRevisionEventParams) SetRevisionEvent(entity *RevisionEvent) {
+	converter := &ExecUpdateRevisionEventParamsConverterImpl{}
+	converter.SetFromRevisionEvent(x, entity)
+}
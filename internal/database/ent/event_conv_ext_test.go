@@ -0,0 +1,74 @@
+//go:build !goverter
+
+package ent_test
+
+import (
+	"github.com/aws-contrib/aurora/internal/database/ent"
+
+	. "github.com/aws-contrib/aurora/internal/database/ent/fake"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("InsertRevisionEventParams", func() {
+	var params ent.InsertRevisionEventParams
+
+	BeforeEach(func() {
+		params = ent.InsertRevisionEventParams{}
+	})
+
+	Describe("SetRevisionEvent", func() {
+		var entity *ent.RevisionEvent
+
+		BeforeEach(func() {
+			entity = NewFakeRevisionEvent()
+		})
+
+		It("sets the entity", func() {
+			params.SetRevisionEvent(entity)
+			Expect(params).NotTo(BeZero())
+		})
+	})
+})
+
+var _ = Describe("ExecInsertRevisionEventParams", func() {
+	var params ent.ExecInsertRevisionEventParams
+
+	BeforeEach(func() {
+		params = ent.ExecInsertRevisionEventParams{}
+	})
+
+	Describe("SetRevisionEvent", func() {
+		var entity *ent.RevisionEvent
+
+		BeforeEach(func() {
+			entity = NewFakeRevisionEvent()
+		})
+
+		It("sets the entity", func() {
+			params.SetRevisionEvent(entity)
+			Expect(params).NotTo(BeZero())
+		})
+	})
+})
+
+var _ = Describe("ExecUpdateRevisionEventParams", func() {
+	var params ent.ExecUpdateRevisionEventParams
+
+	BeforeEach(func() {
+		params = ent.ExecUpdateRevisionEventParams{}
+	})
+
+	Describe("SetRevisionEvent", func() {
+		var entity *ent.RevisionEvent
+
+		BeforeEach(func() {
+			entity = NewFakeRevisionEvent()
+		})
+
+		It("sets the entity", func() {
+			params.SetRevisionEvent(entity)
+			Expect(params).NotTo(BeZero())
+		})
+	})
+})
@@ -9,6 +9,105 @@ import (
 )
 
 type FakeGateway struct {
+	AlterTableLocksAddExpiresAtStub        func(context.Context) error
+	alterTableLocksAddExpiresAtMutex       sync.RWMutex
+	alterTableLocksAddExpiresAtArgsForCall []struct {
+		arg1 context.Context
+	}
+	alterTableLocksAddExpiresAtReturns struct {
+		result1 error
+	}
+	alterTableLocksAddExpiresAtReturnsOnCall map[int]struct {
+		result1 error
+	}
+	AlterTableLocksAddHostnameStub        func(context.Context) error
+	alterTableLocksAddHostnameMutex       sync.RWMutex
+	alterTableLocksAddHostnameArgsForCall []struct {
+		arg1 context.Context
+	}
+	alterTableLocksAddHostnameReturns struct {
+		result1 error
+	}
+	alterTableLocksAddHostnameReturnsOnCall map[int]struct {
+		result1 error
+	}
+	AlterTableLocksAddOwnerStub        func(context.Context) error
+	alterTableLocksAddOwnerMutex       sync.RWMutex
+	alterTableLocksAddOwnerArgsForCall []struct {
+		arg1 context.Context
+	}
+	alterTableLocksAddOwnerReturns struct {
+		result1 error
+	}
+	alterTableLocksAddOwnerReturnsOnCall map[int]struct {
+		result1 error
+	}
+	AlterTableLocksAddPidStub        func(context.Context) error
+	alterTableLocksAddPidMutex       sync.RWMutex
+	alterTableLocksAddPidArgsForCall []struct {
+		arg1 context.Context
+	}
+	alterTableLocksAddPidReturns struct {
+		result1 error
+	}
+	alterTableLocksAddPidReturnsOnCall map[int]struct {
+		result1 error
+	}
+	AlterTableLocksAddVersionStub        func(context.Context) error
+	alterTableLocksAddVersionMutex       sync.RWMutex
+	alterTableLocksAddVersionArgsForCall []struct {
+		arg1 context.Context
+	}
+	alterTableLocksAddVersionReturns struct {
+		result1 error
+	}
+	alterTableLocksAddVersionReturnsOnCall map[int]struct {
+		result1 error
+	}
+	AlterTableRevisionsAddHashStub        func(context.Context) error
+	alterTableRevisionsAddHashMutex       sync.RWMutex
+	alterTableRevisionsAddHashArgsForCall []struct {
+		arg1 context.Context
+	}
+	alterTableRevisionsAddHashReturns struct {
+		result1 error
+	}
+	alterTableRevisionsAddHashReturnsOnCall map[int]struct {
+		result1 error
+	}
+	AlterTableRevisionsAddPartialHashesStub        func(context.Context) error
+	alterTableRevisionsAddPartialHashesMutex       sync.RWMutex
+	alterTableRevisionsAddPartialHashesArgsForCall []struct {
+		arg1 context.Context
+	}
+	alterTableRevisionsAddPartialHashesReturns struct {
+		result1 error
+	}
+	alterTableRevisionsAddPartialHashesReturnsOnCall map[int]struct {
+		result1 error
+	}
+	AlterTableRevisionsAddRevertCountStub        func(context.Context) error
+	alterTableRevisionsAddRevertCountMutex       sync.RWMutex
+	alterTableRevisionsAddRevertCountArgsForCall []struct {
+		arg1 context.Context
+	}
+	alterTableRevisionsAddRevertCountReturns struct {
+		result1 error
+	}
+	alterTableRevisionsAddRevertCountReturnsOnCall map[int]struct {
+		result1 error
+	}
+	AlterTableRevisionsAddRevertedAtStub        func(context.Context) error
+	alterTableRevisionsAddRevertedAtMutex       sync.RWMutex
+	alterTableRevisionsAddRevertedAtArgsForCall []struct {
+		arg1 context.Context
+	}
+	alterTableRevisionsAddRevertedAtReturns struct {
+		result1 error
+	}
+	alterTableRevisionsAddRevertedAtReturnsOnCall map[int]struct {
+		result1 error
+	}
 	CloseStub        func()
 	closeMutex       sync.RWMutex
 	closeArgsForCall []struct {
@@ -46,6 +145,17 @@ type FakeGateway struct {
 	createTableLocksReturnsOnCall map[int]struct {
 		result1 error
 	}
+	CreateTableRevisionEventsStub        func(context.Context) error
+	createTableRevisionEventsMutex       sync.RWMutex
+	createTableRevisionEventsArgsForCall []struct {
+		arg1 context.Context
+	}
+	createTableRevisionEventsReturns struct {
+		result1 error
+	}
+	createTableRevisionEventsReturnsOnCall map[int]struct {
+		result1 error
+	}
 	CreateTableRevisionsStub        func(context.Context) error
 	createTableRevisionsMutex       sync.RWMutex
 	createTableRevisionsArgsForCall []struct {
@@ -181,6 +291,72 @@ type FakeGateway struct {
 	execInsertRevisionReturnsOnCall map[int]struct {
 		result1 error
 	}
+	ExecInsertRevisionEventStub        func(context.Context, *ent.ExecInsertRevisionEventParams) error
+	execInsertRevisionEventMutex       sync.RWMutex
+	execInsertRevisionEventArgsForCall []struct {
+		arg1 context.Context
+		arg2 *ent.ExecInsertRevisionEventParams
+	}
+	execInsertRevisionEventReturns struct {
+		result1 error
+	}
+	execInsertRevisionEventReturnsOnCall map[int]struct {
+		result1 error
+	}
+	ExecRefreshLockStub        func(context.Context, *ent.ExecRefreshLockParams) (int64, error)
+	execRefreshLockMutex       sync.RWMutex
+	execRefreshLockArgsForCall []struct {
+		arg1 context.Context
+		arg2 *ent.ExecRefreshLockParams
+	}
+	execRefreshLockReturns struct {
+		result1 int64
+		result2 error
+	}
+	execRefreshLockReturnsOnCall map[int]struct {
+		result1 int64
+		result2 error
+	}
+	ExecReleaseLockStub        func(context.Context, *ent.ExecReleaseLockParams) error
+	execReleaseLockMutex       sync.RWMutex
+	execReleaseLockArgsForCall []struct {
+		arg1 context.Context
+		arg2 *ent.ExecReleaseLockParams
+	}
+	execReleaseLockReturns struct {
+		result1 error
+	}
+	execReleaseLockReturnsOnCall map[int]struct {
+		result1 error
+	}
+	ExecReleaseObservedLockStub        func(context.Context, *ent.ExecReleaseObservedLockParams) (int64, error)
+	execReleaseObservedLockMutex       sync.RWMutex
+	execReleaseObservedLockArgsForCall []struct {
+		arg1 context.Context
+		arg2 *ent.ExecReleaseObservedLockParams
+	}
+	execReleaseObservedLockReturns struct {
+		result1 int64
+		result2 error
+	}
+	execReleaseObservedLockReturnsOnCall map[int]struct {
+		result1 int64
+		result2 error
+	}
+	ExecTakeoverLockStub        func(context.Context, *ent.ExecTakeoverLockParams) (int64, error)
+	execTakeoverLockMutex       sync.RWMutex
+	execTakeoverLockArgsForCall []struct {
+		arg1 context.Context
+		arg2 *ent.ExecTakeoverLockParams
+	}
+	execTakeoverLockReturns struct {
+		result1 int64
+		result2 error
+	}
+	execTakeoverLockReturnsOnCall map[int]struct {
+		result1 int64
+		result2 error
+	}
 	ExecUpdateRevisionStub        func(context.Context, *ent.ExecUpdateRevisionParams) error
 	execUpdateRevisionMutex       sync.RWMutex
 	execUpdateRevisionArgsForCall []struct {
@@ -193,6 +369,18 @@ type FakeGateway struct {
 	execUpdateRevisionReturnsOnCall map[int]struct {
 		result1 error
 	}
+	ExecUpdateRevisionEventStub        func(context.Context, *ent.ExecUpdateRevisionEventParams) error
+	execUpdateRevisionEventMutex       sync.RWMutex
+	execUpdateRevisionEventArgsForCall []struct {
+		arg1 context.Context
+		arg2 *ent.ExecUpdateRevisionEventParams
+	}
+	execUpdateRevisionEventReturns struct {
+		result1 error
+	}
+	execUpdateRevisionEventReturnsOnCall map[int]struct {
+		result1 error
+	}
 	ExecUpsertRevisionStub        func(context.Context, *ent.ExecUpsertRevisionParams) error
 	execUpsertRevisionMutex       sync.RWMutex
 	execUpsertRevisionArgsForCall []struct {
@@ -219,6 +407,34 @@ type FakeGateway struct {
 		result1 *ent.Job
 		result2 error
 	}
+	GetLegacyLockStub        func(context.Context, *ent.GetLegacyLockParams) (*ent.GetLegacyLockRow, error)
+	getLegacyLockMutex       sync.RWMutex
+	getLegacyLockArgsForCall []struct {
+		arg1 context.Context
+		arg2 *ent.GetLegacyLockParams
+	}
+	getLegacyLockReturns struct {
+		result1 *ent.GetLegacyLockRow
+		result2 error
+	}
+	getLegacyLockReturnsOnCall map[int]struct {
+		result1 *ent.GetLegacyLockRow
+		result2 error
+	}
+	GetLegacyRevisionStub        func(context.Context, *ent.GetLegacyRevisionParams) (*ent.GetLegacyRevisionRow, error)
+	getLegacyRevisionMutex       sync.RWMutex
+	getLegacyRevisionArgsForCall []struct {
+		arg1 context.Context
+		arg2 *ent.GetLegacyRevisionParams
+	}
+	getLegacyRevisionReturns struct {
+		result1 *ent.GetLegacyRevisionRow
+		result2 error
+	}
+	getLegacyRevisionReturnsOnCall map[int]struct {
+		result1 *ent.GetLegacyRevisionRow
+		result2 error
+	}
 	GetLockStub        func(context.Context, *ent.GetLockParams) (*ent.Lock, error)
 	getLockMutex       sync.RWMutex
 	getLockArgsForCall []struct {
@@ -247,6 +463,19 @@ type FakeGateway struct {
 		result1 *ent.Revision
 		result2 error
 	}
+	HasIAMRoleMappingsStub        func(context.Context) (bool, error)
+	hasIAMRoleMappingsMutex       sync.RWMutex
+	hasIAMRoleMappingsArgsForCall []struct {
+		arg1 context.Context
+	}
+	hasIAMRoleMappingsReturns struct {
+		result1 bool
+		result2 error
+	}
+	hasIAMRoleMappingsReturnsOnCall map[int]struct {
+		result1 bool
+		result2 error
+	}
 	InsertJobStub        func(context.Context, *ent.InsertJobParams) (*ent.Job, error)
 	insertJobMutex       sync.RWMutex
 	insertJobArgsForCall []struct {
@@ -289,6 +518,34 @@ type FakeGateway struct {
 		result1 *ent.Revision
 		result2 error
 	}
+	InsertRevisionEventStub        func(context.Context, *ent.InsertRevisionEventParams) (*ent.RevisionEvent, error)
+	insertRevisionEventMutex       sync.RWMutex
+	insertRevisionEventArgsForCall []struct {
+		arg1 context.Context
+		arg2 *ent.InsertRevisionEventParams
+	}
+	insertRevisionEventReturns struct {
+		result1 *ent.RevisionEvent
+		result2 error
+	}
+	insertRevisionEventReturnsOnCall map[int]struct {
+		result1 *ent.RevisionEvent
+		result2 error
+	}
+	ListRevisionEventsStub        func(context.Context, *ent.ListRevisionEventsParams) ([]*ent.RevisionEvent, error)
+	listRevisionEventsMutex       sync.RWMutex
+	listRevisionEventsArgsForCall []struct {
+		arg1 context.Context
+		arg2 *ent.ListRevisionEventsParams
+	}
+	listRevisionEventsReturns struct {
+		result1 []*ent.RevisionEvent
+		result2 error
+	}
+	listRevisionEventsReturnsOnCall map[int]struct {
+		result1 []*ent.RevisionEvent
+		result2 error
+	}
 	ListRevisionsStub        func(context.Context, *ent.ListRevisionsParams) ([]*ent.Revision, error)
 	listRevisionsMutex       sync.RWMutex
 	listRevisionsArgsForCall []struct {
@@ -350,12 +607,561 @@ type FakeGateway struct {
 		result1 *ent.Revision
 		result2 error
 	}
-	upsertRevisionReturnsOnCall map[int]struct {
-		result1 *ent.Revision
-		result2 error
+	upsertRevisionReturnsOnCall map[int]struct {
+		result1 *ent.Revision
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeGateway) AlterTableLocksAddExpiresAt(arg1 context.Context) error {
+	fake.alterTableLocksAddExpiresAtMutex.Lock()
+	ret, specificReturn := fake.alterTableLocksAddExpiresAtReturnsOnCall[len(fake.alterTableLocksAddExpiresAtArgsForCall)]
+	fake.alterTableLocksAddExpiresAtArgsForCall = append(fake.alterTableLocksAddExpiresAtArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.AlterTableLocksAddExpiresAtStub
+	fakeReturns := fake.alterTableLocksAddExpiresAtReturns
+	fake.recordInvocation("AlterTableLocksAddExpiresAt", []interface{}{arg1})
+	fake.alterTableLocksAddExpiresAtMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeGateway) AlterTableLocksAddExpiresAtCallCount() int {
+	fake.alterTableLocksAddExpiresAtMutex.RLock()
+	defer fake.alterTableLocksAddExpiresAtMutex.RUnlock()
+	return len(fake.alterTableLocksAddExpiresAtArgsForCall)
+}
+
+func (fake *FakeGateway) AlterTableLocksAddExpiresAtCalls(stub func(context.Context) error) {
+	fake.alterTableLocksAddExpiresAtMutex.Lock()
+	defer fake.alterTableLocksAddExpiresAtMutex.Unlock()
+	fake.AlterTableLocksAddExpiresAtStub = stub
+}
+
+func (fake *FakeGateway) AlterTableLocksAddExpiresAtArgsForCall(i int) context.Context {
+	fake.alterTableLocksAddExpiresAtMutex.RLock()
+	defer fake.alterTableLocksAddExpiresAtMutex.RUnlock()
+	argsForCall := fake.alterTableLocksAddExpiresAtArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeGateway) AlterTableLocksAddExpiresAtReturns(result1 error) {
+	fake.alterTableLocksAddExpiresAtMutex.Lock()
+	defer fake.alterTableLocksAddExpiresAtMutex.Unlock()
+	fake.AlterTableLocksAddExpiresAtStub = nil
+	fake.alterTableLocksAddExpiresAtReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGateway) AlterTableLocksAddExpiresAtReturnsOnCall(i int, result1 error) {
+	fake.alterTableLocksAddExpiresAtMutex.Lock()
+	defer fake.alterTableLocksAddExpiresAtMutex.Unlock()
+	fake.AlterTableLocksAddExpiresAtStub = nil
+	if fake.alterTableLocksAddExpiresAtReturnsOnCall == nil {
+		fake.alterTableLocksAddExpiresAtReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.alterTableLocksAddExpiresAtReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGateway) AlterTableLocksAddHostname(arg1 context.Context) error {
+	fake.alterTableLocksAddHostnameMutex.Lock()
+	ret, specificReturn := fake.alterTableLocksAddHostnameReturnsOnCall[len(fake.alterTableLocksAddHostnameArgsForCall)]
+	fake.alterTableLocksAddHostnameArgsForCall = append(fake.alterTableLocksAddHostnameArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.AlterTableLocksAddHostnameStub
+	fakeReturns := fake.alterTableLocksAddHostnameReturns
+	fake.recordInvocation("AlterTableLocksAddHostname", []interface{}{arg1})
+	fake.alterTableLocksAddHostnameMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeGateway) AlterTableLocksAddHostnameCallCount() int {
+	fake.alterTableLocksAddHostnameMutex.RLock()
+	defer fake.alterTableLocksAddHostnameMutex.RUnlock()
+	return len(fake.alterTableLocksAddHostnameArgsForCall)
+}
+
+func (fake *FakeGateway) AlterTableLocksAddHostnameCalls(stub func(context.Context) error) {
+	fake.alterTableLocksAddHostnameMutex.Lock()
+	defer fake.alterTableLocksAddHostnameMutex.Unlock()
+	fake.AlterTableLocksAddHostnameStub = stub
+}
+
+func (fake *FakeGateway) AlterTableLocksAddHostnameArgsForCall(i int) context.Context {
+	fake.alterTableLocksAddHostnameMutex.RLock()
+	defer fake.alterTableLocksAddHostnameMutex.RUnlock()
+	argsForCall := fake.alterTableLocksAddHostnameArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeGateway) AlterTableLocksAddHostnameReturns(result1 error) {
+	fake.alterTableLocksAddHostnameMutex.Lock()
+	defer fake.alterTableLocksAddHostnameMutex.Unlock()
+	fake.AlterTableLocksAddHostnameStub = nil
+	fake.alterTableLocksAddHostnameReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGateway) AlterTableLocksAddHostnameReturnsOnCall(i int, result1 error) {
+	fake.alterTableLocksAddHostnameMutex.Lock()
+	defer fake.alterTableLocksAddHostnameMutex.Unlock()
+	fake.AlterTableLocksAddHostnameStub = nil
+	if fake.alterTableLocksAddHostnameReturnsOnCall == nil {
+		fake.alterTableLocksAddHostnameReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.alterTableLocksAddHostnameReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGateway) AlterTableLocksAddOwner(arg1 context.Context) error {
+	fake.alterTableLocksAddOwnerMutex.Lock()
+	ret, specificReturn := fake.alterTableLocksAddOwnerReturnsOnCall[len(fake.alterTableLocksAddOwnerArgsForCall)]
+	fake.alterTableLocksAddOwnerArgsForCall = append(fake.alterTableLocksAddOwnerArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.AlterTableLocksAddOwnerStub
+	fakeReturns := fake.alterTableLocksAddOwnerReturns
+	fake.recordInvocation("AlterTableLocksAddOwner", []interface{}{arg1})
+	fake.alterTableLocksAddOwnerMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeGateway) AlterTableLocksAddOwnerCallCount() int {
+	fake.alterTableLocksAddOwnerMutex.RLock()
+	defer fake.alterTableLocksAddOwnerMutex.RUnlock()
+	return len(fake.alterTableLocksAddOwnerArgsForCall)
+}
+
+func (fake *FakeGateway) AlterTableLocksAddOwnerCalls(stub func(context.Context) error) {
+	fake.alterTableLocksAddOwnerMutex.Lock()
+	defer fake.alterTableLocksAddOwnerMutex.Unlock()
+	fake.AlterTableLocksAddOwnerStub = stub
+}
+
+func (fake *FakeGateway) AlterTableLocksAddOwnerArgsForCall(i int) context.Context {
+	fake.alterTableLocksAddOwnerMutex.RLock()
+	defer fake.alterTableLocksAddOwnerMutex.RUnlock()
+	argsForCall := fake.alterTableLocksAddOwnerArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeGateway) AlterTableLocksAddOwnerReturns(result1 error) {
+	fake.alterTableLocksAddOwnerMutex.Lock()
+	defer fake.alterTableLocksAddOwnerMutex.Unlock()
+	fake.AlterTableLocksAddOwnerStub = nil
+	fake.alterTableLocksAddOwnerReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGateway) AlterTableLocksAddOwnerReturnsOnCall(i int, result1 error) {
+	fake.alterTableLocksAddOwnerMutex.Lock()
+	defer fake.alterTableLocksAddOwnerMutex.Unlock()
+	fake.AlterTableLocksAddOwnerStub = nil
+	if fake.alterTableLocksAddOwnerReturnsOnCall == nil {
+		fake.alterTableLocksAddOwnerReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.alterTableLocksAddOwnerReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGateway) AlterTableLocksAddPid(arg1 context.Context) error {
+	fake.alterTableLocksAddPidMutex.Lock()
+	ret, specificReturn := fake.alterTableLocksAddPidReturnsOnCall[len(fake.alterTableLocksAddPidArgsForCall)]
+	fake.alterTableLocksAddPidArgsForCall = append(fake.alterTableLocksAddPidArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.AlterTableLocksAddPidStub
+	fakeReturns := fake.alterTableLocksAddPidReturns
+	fake.recordInvocation("AlterTableLocksAddPid", []interface{}{arg1})
+	fake.alterTableLocksAddPidMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeGateway) AlterTableLocksAddPidCallCount() int {
+	fake.alterTableLocksAddPidMutex.RLock()
+	defer fake.alterTableLocksAddPidMutex.RUnlock()
+	return len(fake.alterTableLocksAddPidArgsForCall)
+}
+
+func (fake *FakeGateway) AlterTableLocksAddPidCalls(stub func(context.Context) error) {
+	fake.alterTableLocksAddPidMutex.Lock()
+	defer fake.alterTableLocksAddPidMutex.Unlock()
+	fake.AlterTableLocksAddPidStub = stub
+}
+
+func (fake *FakeGateway) AlterTableLocksAddPidArgsForCall(i int) context.Context {
+	fake.alterTableLocksAddPidMutex.RLock()
+	defer fake.alterTableLocksAddPidMutex.RUnlock()
+	argsForCall := fake.alterTableLocksAddPidArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeGateway) AlterTableLocksAddPidReturns(result1 error) {
+	fake.alterTableLocksAddPidMutex.Lock()
+	defer fake.alterTableLocksAddPidMutex.Unlock()
+	fake.AlterTableLocksAddPidStub = nil
+	fake.alterTableLocksAddPidReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGateway) AlterTableLocksAddPidReturnsOnCall(i int, result1 error) {
+	fake.alterTableLocksAddPidMutex.Lock()
+	defer fake.alterTableLocksAddPidMutex.Unlock()
+	fake.AlterTableLocksAddPidStub = nil
+	if fake.alterTableLocksAddPidReturnsOnCall == nil {
+		fake.alterTableLocksAddPidReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.alterTableLocksAddPidReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGateway) AlterTableLocksAddVersion(arg1 context.Context) error {
+	fake.alterTableLocksAddVersionMutex.Lock()
+	ret, specificReturn := fake.alterTableLocksAddVersionReturnsOnCall[len(fake.alterTableLocksAddVersionArgsForCall)]
+	fake.alterTableLocksAddVersionArgsForCall = append(fake.alterTableLocksAddVersionArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.AlterTableLocksAddVersionStub
+	fakeReturns := fake.alterTableLocksAddVersionReturns
+	fake.recordInvocation("AlterTableLocksAddVersion", []interface{}{arg1})
+	fake.alterTableLocksAddVersionMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeGateway) AlterTableLocksAddVersionCallCount() int {
+	fake.alterTableLocksAddVersionMutex.RLock()
+	defer fake.alterTableLocksAddVersionMutex.RUnlock()
+	return len(fake.alterTableLocksAddVersionArgsForCall)
+}
+
+func (fake *FakeGateway) AlterTableLocksAddVersionCalls(stub func(context.Context) error) {
+	fake.alterTableLocksAddVersionMutex.Lock()
+	defer fake.alterTableLocksAddVersionMutex.Unlock()
+	fake.AlterTableLocksAddVersionStub = stub
+}
+
+func (fake *FakeGateway) AlterTableLocksAddVersionArgsForCall(i int) context.Context {
+	fake.alterTableLocksAddVersionMutex.RLock()
+	defer fake.alterTableLocksAddVersionMutex.RUnlock()
+	argsForCall := fake.alterTableLocksAddVersionArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeGateway) AlterTableLocksAddVersionReturns(result1 error) {
+	fake.alterTableLocksAddVersionMutex.Lock()
+	defer fake.alterTableLocksAddVersionMutex.Unlock()
+	fake.AlterTableLocksAddVersionStub = nil
+	fake.alterTableLocksAddVersionReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGateway) AlterTableLocksAddVersionReturnsOnCall(i int, result1 error) {
+	fake.alterTableLocksAddVersionMutex.Lock()
+	defer fake.alterTableLocksAddVersionMutex.Unlock()
+	fake.AlterTableLocksAddVersionStub = nil
+	if fake.alterTableLocksAddVersionReturnsOnCall == nil {
+		fake.alterTableLocksAddVersionReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.alterTableLocksAddVersionReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGateway) AlterTableRevisionsAddHash(arg1 context.Context) error {
+	fake.alterTableRevisionsAddHashMutex.Lock()
+	ret, specificReturn := fake.alterTableRevisionsAddHashReturnsOnCall[len(fake.alterTableRevisionsAddHashArgsForCall)]
+	fake.alterTableRevisionsAddHashArgsForCall = append(fake.alterTableRevisionsAddHashArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.AlterTableRevisionsAddHashStub
+	fakeReturns := fake.alterTableRevisionsAddHashReturns
+	fake.recordInvocation("AlterTableRevisionsAddHash", []interface{}{arg1})
+	fake.alterTableRevisionsAddHashMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeGateway) AlterTableRevisionsAddHashCallCount() int {
+	fake.alterTableRevisionsAddHashMutex.RLock()
+	defer fake.alterTableRevisionsAddHashMutex.RUnlock()
+	return len(fake.alterTableRevisionsAddHashArgsForCall)
+}
+
+func (fake *FakeGateway) AlterTableRevisionsAddHashCalls(stub func(context.Context) error) {
+	fake.alterTableRevisionsAddHashMutex.Lock()
+	defer fake.alterTableRevisionsAddHashMutex.Unlock()
+	fake.AlterTableRevisionsAddHashStub = stub
+}
+
+func (fake *FakeGateway) AlterTableRevisionsAddHashArgsForCall(i int) context.Context {
+	fake.alterTableRevisionsAddHashMutex.RLock()
+	defer fake.alterTableRevisionsAddHashMutex.RUnlock()
+	argsForCall := fake.alterTableRevisionsAddHashArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeGateway) AlterTableRevisionsAddHashReturns(result1 error) {
+	fake.alterTableRevisionsAddHashMutex.Lock()
+	defer fake.alterTableRevisionsAddHashMutex.Unlock()
+	fake.AlterTableRevisionsAddHashStub = nil
+	fake.alterTableRevisionsAddHashReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGateway) AlterTableRevisionsAddHashReturnsOnCall(i int, result1 error) {
+	fake.alterTableRevisionsAddHashMutex.Lock()
+	defer fake.alterTableRevisionsAddHashMutex.Unlock()
+	fake.AlterTableRevisionsAddHashStub = nil
+	if fake.alterTableRevisionsAddHashReturnsOnCall == nil {
+		fake.alterTableRevisionsAddHashReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.alterTableRevisionsAddHashReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGateway) AlterTableRevisionsAddPartialHashes(arg1 context.Context) error {
+	fake.alterTableRevisionsAddPartialHashesMutex.Lock()
+	ret, specificReturn := fake.alterTableRevisionsAddPartialHashesReturnsOnCall[len(fake.alterTableRevisionsAddPartialHashesArgsForCall)]
+	fake.alterTableRevisionsAddPartialHashesArgsForCall = append(fake.alterTableRevisionsAddPartialHashesArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.AlterTableRevisionsAddPartialHashesStub
+	fakeReturns := fake.alterTableRevisionsAddPartialHashesReturns
+	fake.recordInvocation("AlterTableRevisionsAddPartialHashes", []interface{}{arg1})
+	fake.alterTableRevisionsAddPartialHashesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeGateway) AlterTableRevisionsAddPartialHashesCallCount() int {
+	fake.alterTableRevisionsAddPartialHashesMutex.RLock()
+	defer fake.alterTableRevisionsAddPartialHashesMutex.RUnlock()
+	return len(fake.alterTableRevisionsAddPartialHashesArgsForCall)
+}
+
+func (fake *FakeGateway) AlterTableRevisionsAddPartialHashesCalls(stub func(context.Context) error) {
+	fake.alterTableRevisionsAddPartialHashesMutex.Lock()
+	defer fake.alterTableRevisionsAddPartialHashesMutex.Unlock()
+	fake.AlterTableRevisionsAddPartialHashesStub = stub
+}
+
+func (fake *FakeGateway) AlterTableRevisionsAddPartialHashesArgsForCall(i int) context.Context {
+	fake.alterTableRevisionsAddPartialHashesMutex.RLock()
+	defer fake.alterTableRevisionsAddPartialHashesMutex.RUnlock()
+	argsForCall := fake.alterTableRevisionsAddPartialHashesArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeGateway) AlterTableRevisionsAddPartialHashesReturns(result1 error) {
+	fake.alterTableRevisionsAddPartialHashesMutex.Lock()
+	defer fake.alterTableRevisionsAddPartialHashesMutex.Unlock()
+	fake.AlterTableRevisionsAddPartialHashesStub = nil
+	fake.alterTableRevisionsAddPartialHashesReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGateway) AlterTableRevisionsAddPartialHashesReturnsOnCall(i int, result1 error) {
+	fake.alterTableRevisionsAddPartialHashesMutex.Lock()
+	defer fake.alterTableRevisionsAddPartialHashesMutex.Unlock()
+	fake.AlterTableRevisionsAddPartialHashesStub = nil
+	if fake.alterTableRevisionsAddPartialHashesReturnsOnCall == nil {
+		fake.alterTableRevisionsAddPartialHashesReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.alterTableRevisionsAddPartialHashesReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGateway) AlterTableRevisionsAddRevertCount(arg1 context.Context) error {
+	fake.alterTableRevisionsAddRevertCountMutex.Lock()
+	ret, specificReturn := fake.alterTableRevisionsAddRevertCountReturnsOnCall[len(fake.alterTableRevisionsAddRevertCountArgsForCall)]
+	fake.alterTableRevisionsAddRevertCountArgsForCall = append(fake.alterTableRevisionsAddRevertCountArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.AlterTableRevisionsAddRevertCountStub
+	fakeReturns := fake.alterTableRevisionsAddRevertCountReturns
+	fake.recordInvocation("AlterTableRevisionsAddRevertCount", []interface{}{arg1})
+	fake.alterTableRevisionsAddRevertCountMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeGateway) AlterTableRevisionsAddRevertCountCallCount() int {
+	fake.alterTableRevisionsAddRevertCountMutex.RLock()
+	defer fake.alterTableRevisionsAddRevertCountMutex.RUnlock()
+	return len(fake.alterTableRevisionsAddRevertCountArgsForCall)
+}
+
+func (fake *FakeGateway) AlterTableRevisionsAddRevertCountCalls(stub func(context.Context) error) {
+	fake.alterTableRevisionsAddRevertCountMutex.Lock()
+	defer fake.alterTableRevisionsAddRevertCountMutex.Unlock()
+	fake.AlterTableRevisionsAddRevertCountStub = stub
+}
+
+func (fake *FakeGateway) AlterTableRevisionsAddRevertCountArgsForCall(i int) context.Context {
+	fake.alterTableRevisionsAddRevertCountMutex.RLock()
+	defer fake.alterTableRevisionsAddRevertCountMutex.RUnlock()
+	argsForCall := fake.alterTableRevisionsAddRevertCountArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeGateway) AlterTableRevisionsAddRevertCountReturns(result1 error) {
+	fake.alterTableRevisionsAddRevertCountMutex.Lock()
+	defer fake.alterTableRevisionsAddRevertCountMutex.Unlock()
+	fake.AlterTableRevisionsAddRevertCountStub = nil
+	fake.alterTableRevisionsAddRevertCountReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGateway) AlterTableRevisionsAddRevertCountReturnsOnCall(i int, result1 error) {
+	fake.alterTableRevisionsAddRevertCountMutex.Lock()
+	defer fake.alterTableRevisionsAddRevertCountMutex.Unlock()
+	fake.AlterTableRevisionsAddRevertCountStub = nil
+	if fake.alterTableRevisionsAddRevertCountReturnsOnCall == nil {
+		fake.alterTableRevisionsAddRevertCountReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.alterTableRevisionsAddRevertCountReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGateway) AlterTableRevisionsAddRevertedAt(arg1 context.Context) error {
+	fake.alterTableRevisionsAddRevertedAtMutex.Lock()
+	ret, specificReturn := fake.alterTableRevisionsAddRevertedAtReturnsOnCall[len(fake.alterTableRevisionsAddRevertedAtArgsForCall)]
+	fake.alterTableRevisionsAddRevertedAtArgsForCall = append(fake.alterTableRevisionsAddRevertedAtArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.AlterTableRevisionsAddRevertedAtStub
+	fakeReturns := fake.alterTableRevisionsAddRevertedAtReturns
+	fake.recordInvocation("AlterTableRevisionsAddRevertedAt", []interface{}{arg1})
+	fake.alterTableRevisionsAddRevertedAtMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
 	}
-	invocations      map[string][][]interface{}
-	invocationsMutex sync.RWMutex
+	return fakeReturns.result1
+}
+
+func (fake *FakeGateway) AlterTableRevisionsAddRevertedAtCallCount() int {
+	fake.alterTableRevisionsAddRevertedAtMutex.RLock()
+	defer fake.alterTableRevisionsAddRevertedAtMutex.RUnlock()
+	return len(fake.alterTableRevisionsAddRevertedAtArgsForCall)
+}
+
+func (fake *FakeGateway) AlterTableRevisionsAddRevertedAtCalls(stub func(context.Context) error) {
+	fake.alterTableRevisionsAddRevertedAtMutex.Lock()
+	defer fake.alterTableRevisionsAddRevertedAtMutex.Unlock()
+	fake.AlterTableRevisionsAddRevertedAtStub = stub
+}
+
+func (fake *FakeGateway) AlterTableRevisionsAddRevertedAtArgsForCall(i int) context.Context {
+	fake.alterTableRevisionsAddRevertedAtMutex.RLock()
+	defer fake.alterTableRevisionsAddRevertedAtMutex.RUnlock()
+	argsForCall := fake.alterTableRevisionsAddRevertedAtArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeGateway) AlterTableRevisionsAddRevertedAtReturns(result1 error) {
+	fake.alterTableRevisionsAddRevertedAtMutex.Lock()
+	defer fake.alterTableRevisionsAddRevertedAtMutex.Unlock()
+	fake.AlterTableRevisionsAddRevertedAtStub = nil
+	fake.alterTableRevisionsAddRevertedAtReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGateway) AlterTableRevisionsAddRevertedAtReturnsOnCall(i int, result1 error) {
+	fake.alterTableRevisionsAddRevertedAtMutex.Lock()
+	defer fake.alterTableRevisionsAddRevertedAtMutex.Unlock()
+	fake.AlterTableRevisionsAddRevertedAtStub = nil
+	if fake.alterTableRevisionsAddRevertedAtReturnsOnCall == nil {
+		fake.alterTableRevisionsAddRevertedAtReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.alterTableRevisionsAddRevertedAtReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
 }
 
 func (fake *FakeGateway) Close() {
@@ -565,6 +1371,67 @@ func (fake *FakeGateway) CreateTableLocksReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeGateway) CreateTableRevisionEvents(arg1 context.Context) error {
+	fake.createTableRevisionEventsMutex.Lock()
+	ret, specificReturn := fake.createTableRevisionEventsReturnsOnCall[len(fake.createTableRevisionEventsArgsForCall)]
+	fake.createTableRevisionEventsArgsForCall = append(fake.createTableRevisionEventsArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.CreateTableRevisionEventsStub
+	fakeReturns := fake.createTableRevisionEventsReturns
+	fake.recordInvocation("CreateTableRevisionEvents", []interface{}{arg1})
+	fake.createTableRevisionEventsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeGateway) CreateTableRevisionEventsCallCount() int {
+	fake.createTableRevisionEventsMutex.RLock()
+	defer fake.createTableRevisionEventsMutex.RUnlock()
+	return len(fake.createTableRevisionEventsArgsForCall)
+}
+
+func (fake *FakeGateway) CreateTableRevisionEventsCalls(stub func(context.Context) error) {
+	fake.createTableRevisionEventsMutex.Lock()
+	defer fake.createTableRevisionEventsMutex.Unlock()
+	fake.CreateTableRevisionEventsStub = stub
+}
+
+func (fake *FakeGateway) CreateTableRevisionEventsArgsForCall(i int) context.Context {
+	fake.createTableRevisionEventsMutex.RLock()
+	defer fake.createTableRevisionEventsMutex.RUnlock()
+	argsForCall := fake.createTableRevisionEventsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeGateway) CreateTableRevisionEventsReturns(result1 error) {
+	fake.createTableRevisionEventsMutex.Lock()
+	defer fake.createTableRevisionEventsMutex.Unlock()
+	fake.CreateTableRevisionEventsStub = nil
+	fake.createTableRevisionEventsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGateway) CreateTableRevisionEventsReturnsOnCall(i int, result1 error) {
+	fake.createTableRevisionEventsMutex.Lock()
+	defer fake.createTableRevisionEventsMutex.Unlock()
+	fake.CreateTableRevisionEventsStub = nil
+	if fake.createTableRevisionEventsReturnsOnCall == nil {
+		fake.createTableRevisionEventsReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.createTableRevisionEventsReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeGateway) CreateTableRevisions(arg1 context.Context) error {
 	fake.createTableRevisionsMutex.Lock()
 	ret, specificReturn := fake.createTableRevisionsReturnsOnCall[len(fake.createTableRevisionsArgsForCall)]
@@ -1142,108 +2009,427 @@ func (fake *FakeGateway) ExecInsertLock(arg1 context.Context, arg2 *ent.ExecInse
 	return fakeReturns.result1
 }
 
-func (fake *FakeGateway) ExecInsertLockCallCount() int {
-	fake.execInsertLockMutex.RLock()
-	defer fake.execInsertLockMutex.RUnlock()
-	return len(fake.execInsertLockArgsForCall)
+func (fake *FakeGateway) ExecInsertLockCallCount() int {
+	fake.execInsertLockMutex.RLock()
+	defer fake.execInsertLockMutex.RUnlock()
+	return len(fake.execInsertLockArgsForCall)
+}
+
+func (fake *FakeGateway) ExecInsertLockCalls(stub func(context.Context, *ent.ExecInsertLockParams) error) {
+	fake.execInsertLockMutex.Lock()
+	defer fake.execInsertLockMutex.Unlock()
+	fake.ExecInsertLockStub = stub
+}
+
+func (fake *FakeGateway) ExecInsertLockArgsForCall(i int) (context.Context, *ent.ExecInsertLockParams) {
+	fake.execInsertLockMutex.RLock()
+	defer fake.execInsertLockMutex.RUnlock()
+	argsForCall := fake.execInsertLockArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeGateway) ExecInsertLockReturns(result1 error) {
+	fake.execInsertLockMutex.Lock()
+	defer fake.execInsertLockMutex.Unlock()
+	fake.ExecInsertLockStub = nil
+	fake.execInsertLockReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGateway) ExecInsertLockReturnsOnCall(i int, result1 error) {
+	fake.execInsertLockMutex.Lock()
+	defer fake.execInsertLockMutex.Unlock()
+	fake.ExecInsertLockStub = nil
+	if fake.execInsertLockReturnsOnCall == nil {
+		fake.execInsertLockReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.execInsertLockReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGateway) ExecInsertRevision(arg1 context.Context, arg2 *ent.ExecInsertRevisionParams) error {
+	fake.execInsertRevisionMutex.Lock()
+	ret, specificReturn := fake.execInsertRevisionReturnsOnCall[len(fake.execInsertRevisionArgsForCall)]
+	fake.execInsertRevisionArgsForCall = append(fake.execInsertRevisionArgsForCall, struct {
+		arg1 context.Context
+		arg2 *ent.ExecInsertRevisionParams
+	}{arg1, arg2})
+	stub := fake.ExecInsertRevisionStub
+	fakeReturns := fake.execInsertRevisionReturns
+	fake.recordInvocation("ExecInsertRevision", []interface{}{arg1, arg2})
+	fake.execInsertRevisionMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeGateway) ExecInsertRevisionCallCount() int {
+	fake.execInsertRevisionMutex.RLock()
+	defer fake.execInsertRevisionMutex.RUnlock()
+	return len(fake.execInsertRevisionArgsForCall)
+}
+
+func (fake *FakeGateway) ExecInsertRevisionCalls(stub func(context.Context, *ent.ExecInsertRevisionParams) error) {
+	fake.execInsertRevisionMutex.Lock()
+	defer fake.execInsertRevisionMutex.Unlock()
+	fake.ExecInsertRevisionStub = stub
+}
+
+func (fake *FakeGateway) ExecInsertRevisionArgsForCall(i int) (context.Context, *ent.ExecInsertRevisionParams) {
+	fake.execInsertRevisionMutex.RLock()
+	defer fake.execInsertRevisionMutex.RUnlock()
+	argsForCall := fake.execInsertRevisionArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeGateway) ExecInsertRevisionReturns(result1 error) {
+	fake.execInsertRevisionMutex.Lock()
+	defer fake.execInsertRevisionMutex.Unlock()
+	fake.ExecInsertRevisionStub = nil
+	fake.execInsertRevisionReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGateway) ExecInsertRevisionReturnsOnCall(i int, result1 error) {
+	fake.execInsertRevisionMutex.Lock()
+	defer fake.execInsertRevisionMutex.Unlock()
+	fake.ExecInsertRevisionStub = nil
+	if fake.execInsertRevisionReturnsOnCall == nil {
+		fake.execInsertRevisionReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.execInsertRevisionReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGateway) ExecInsertRevisionEvent(arg1 context.Context, arg2 *ent.ExecInsertRevisionEventParams) error {
+	fake.execInsertRevisionEventMutex.Lock()
+	ret, specificReturn := fake.execInsertRevisionEventReturnsOnCall[len(fake.execInsertRevisionEventArgsForCall)]
+	fake.execInsertRevisionEventArgsForCall = append(fake.execInsertRevisionEventArgsForCall, struct {
+		arg1 context.Context
+		arg2 *ent.ExecInsertRevisionEventParams
+	}{arg1, arg2})
+	stub := fake.ExecInsertRevisionEventStub
+	fakeReturns := fake.execInsertRevisionEventReturns
+	fake.recordInvocation("ExecInsertRevisionEvent", []interface{}{arg1, arg2})
+	fake.execInsertRevisionEventMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeGateway) ExecInsertRevisionEventCallCount() int {
+	fake.execInsertRevisionEventMutex.RLock()
+	defer fake.execInsertRevisionEventMutex.RUnlock()
+	return len(fake.execInsertRevisionEventArgsForCall)
+}
+
+func (fake *FakeGateway) ExecInsertRevisionEventCalls(stub func(context.Context, *ent.ExecInsertRevisionEventParams) error) {
+	fake.execInsertRevisionEventMutex.Lock()
+	defer fake.execInsertRevisionEventMutex.Unlock()
+	fake.ExecInsertRevisionEventStub = stub
+}
+
+func (fake *FakeGateway) ExecInsertRevisionEventArgsForCall(i int) (context.Context, *ent.ExecInsertRevisionEventParams) {
+	fake.execInsertRevisionEventMutex.RLock()
+	defer fake.execInsertRevisionEventMutex.RUnlock()
+	argsForCall := fake.execInsertRevisionEventArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeGateway) ExecInsertRevisionEventReturns(result1 error) {
+	fake.execInsertRevisionEventMutex.Lock()
+	defer fake.execInsertRevisionEventMutex.Unlock()
+	fake.ExecInsertRevisionEventStub = nil
+	fake.execInsertRevisionEventReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGateway) ExecInsertRevisionEventReturnsOnCall(i int, result1 error) {
+	fake.execInsertRevisionEventMutex.Lock()
+	defer fake.execInsertRevisionEventMutex.Unlock()
+	fake.ExecInsertRevisionEventStub = nil
+	if fake.execInsertRevisionEventReturnsOnCall == nil {
+		fake.execInsertRevisionEventReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.execInsertRevisionEventReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGateway) ExecRefreshLock(arg1 context.Context, arg2 *ent.ExecRefreshLockParams) (int64, error) {
+	fake.execRefreshLockMutex.Lock()
+	ret, specificReturn := fake.execRefreshLockReturnsOnCall[len(fake.execRefreshLockArgsForCall)]
+	fake.execRefreshLockArgsForCall = append(fake.execRefreshLockArgsForCall, struct {
+		arg1 context.Context
+		arg2 *ent.ExecRefreshLockParams
+	}{arg1, arg2})
+	stub := fake.ExecRefreshLockStub
+	fakeReturns := fake.execRefreshLockReturns
+	fake.recordInvocation("ExecRefreshLock", []interface{}{arg1, arg2})
+	fake.execRefreshLockMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeGateway) ExecRefreshLockCallCount() int {
+	fake.execRefreshLockMutex.RLock()
+	defer fake.execRefreshLockMutex.RUnlock()
+	return len(fake.execRefreshLockArgsForCall)
+}
+
+func (fake *FakeGateway) ExecRefreshLockCalls(stub func(context.Context, *ent.ExecRefreshLockParams) (int64, error)) {
+	fake.execRefreshLockMutex.Lock()
+	defer fake.execRefreshLockMutex.Unlock()
+	fake.ExecRefreshLockStub = stub
+}
+
+func (fake *FakeGateway) ExecRefreshLockArgsForCall(i int) (context.Context, *ent.ExecRefreshLockParams) {
+	fake.execRefreshLockMutex.RLock()
+	defer fake.execRefreshLockMutex.RUnlock()
+	argsForCall := fake.execRefreshLockArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeGateway) ExecRefreshLockReturns(result1 int64, result2 error) {
+	fake.execRefreshLockMutex.Lock()
+	defer fake.execRefreshLockMutex.Unlock()
+	fake.ExecRefreshLockStub = nil
+	fake.execRefreshLockReturns = struct {
+		result1 int64
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGateway) ExecRefreshLockReturnsOnCall(i int, result1 int64, result2 error) {
+	fake.execRefreshLockMutex.Lock()
+	defer fake.execRefreshLockMutex.Unlock()
+	fake.ExecRefreshLockStub = nil
+	if fake.execRefreshLockReturnsOnCall == nil {
+		fake.execRefreshLockReturnsOnCall = make(map[int]struct {
+			result1 int64
+			result2 error
+		})
+	}
+	fake.execRefreshLockReturnsOnCall[i] = struct {
+		result1 int64
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGateway) ExecReleaseLock(arg1 context.Context, arg2 *ent.ExecReleaseLockParams) error {
+	fake.execReleaseLockMutex.Lock()
+	ret, specificReturn := fake.execReleaseLockReturnsOnCall[len(fake.execReleaseLockArgsForCall)]
+	fake.execReleaseLockArgsForCall = append(fake.execReleaseLockArgsForCall, struct {
+		arg1 context.Context
+		arg2 *ent.ExecReleaseLockParams
+	}{arg1, arg2})
+	stub := fake.ExecReleaseLockStub
+	fakeReturns := fake.execReleaseLockReturns
+	fake.recordInvocation("ExecReleaseLock", []interface{}{arg1, arg2})
+	fake.execReleaseLockMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeGateway) ExecReleaseLockCallCount() int {
+	fake.execReleaseLockMutex.RLock()
+	defer fake.execReleaseLockMutex.RUnlock()
+	return len(fake.execReleaseLockArgsForCall)
+}
+
+func (fake *FakeGateway) ExecReleaseLockCalls(stub func(context.Context, *ent.ExecReleaseLockParams) error) {
+	fake.execReleaseLockMutex.Lock()
+	defer fake.execReleaseLockMutex.Unlock()
+	fake.ExecReleaseLockStub = stub
+}
+
+func (fake *FakeGateway) ExecReleaseLockArgsForCall(i int) (context.Context, *ent.ExecReleaseLockParams) {
+	fake.execReleaseLockMutex.RLock()
+	defer fake.execReleaseLockMutex.RUnlock()
+	argsForCall := fake.execReleaseLockArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeGateway) ExecReleaseLockReturns(result1 error) {
+	fake.execReleaseLockMutex.Lock()
+	defer fake.execReleaseLockMutex.Unlock()
+	fake.ExecReleaseLockStub = nil
+	fake.execReleaseLockReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGateway) ExecReleaseLockReturnsOnCall(i int, result1 error) {
+	fake.execReleaseLockMutex.Lock()
+	defer fake.execReleaseLockMutex.Unlock()
+	fake.ExecReleaseLockStub = nil
+	if fake.execReleaseLockReturnsOnCall == nil {
+		fake.execReleaseLockReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.execReleaseLockReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGateway) ExecReleaseObservedLock(arg1 context.Context, arg2 *ent.ExecReleaseObservedLockParams) (int64, error) {
+	fake.execReleaseObservedLockMutex.Lock()
+	ret, specificReturn := fake.execReleaseObservedLockReturnsOnCall[len(fake.execReleaseObservedLockArgsForCall)]
+	fake.execReleaseObservedLockArgsForCall = append(fake.execReleaseObservedLockArgsForCall, struct {
+		arg1 context.Context
+		arg2 *ent.ExecReleaseObservedLockParams
+	}{arg1, arg2})
+	stub := fake.ExecReleaseObservedLockStub
+	fakeReturns := fake.execReleaseObservedLockReturns
+	fake.recordInvocation("ExecReleaseObservedLock", []interface{}{arg1, arg2})
+	fake.execReleaseObservedLockMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeGateway) ExecReleaseObservedLockCallCount() int {
+	fake.execReleaseObservedLockMutex.RLock()
+	defer fake.execReleaseObservedLockMutex.RUnlock()
+	return len(fake.execReleaseObservedLockArgsForCall)
 }
 
-func (fake *FakeGateway) ExecInsertLockCalls(stub func(context.Context, *ent.ExecInsertLockParams) error) {
-	fake.execInsertLockMutex.Lock()
-	defer fake.execInsertLockMutex.Unlock()
-	fake.ExecInsertLockStub = stub
+func (fake *FakeGateway) ExecReleaseObservedLockCalls(stub func(context.Context, *ent.ExecReleaseObservedLockParams) (int64, error)) {
+	fake.execReleaseObservedLockMutex.Lock()
+	defer fake.execReleaseObservedLockMutex.Unlock()
+	fake.ExecReleaseObservedLockStub = stub
 }
 
-func (fake *FakeGateway) ExecInsertLockArgsForCall(i int) (context.Context, *ent.ExecInsertLockParams) {
-	fake.execInsertLockMutex.RLock()
-	defer fake.execInsertLockMutex.RUnlock()
-	argsForCall := fake.execInsertLockArgsForCall[i]
+func (fake *FakeGateway) ExecReleaseObservedLockArgsForCall(i int) (context.Context, *ent.ExecReleaseObservedLockParams) {
+	fake.execReleaseObservedLockMutex.RLock()
+	defer fake.execReleaseObservedLockMutex.RUnlock()
+	argsForCall := fake.execReleaseObservedLockArgsForCall[i]
 	return argsForCall.arg1, argsForCall.arg2
 }
 
-func (fake *FakeGateway) ExecInsertLockReturns(result1 error) {
-	fake.execInsertLockMutex.Lock()
-	defer fake.execInsertLockMutex.Unlock()
-	fake.ExecInsertLockStub = nil
-	fake.execInsertLockReturns = struct {
-		result1 error
-	}{result1}
+func (fake *FakeGateway) ExecReleaseObservedLockReturns(result1 int64, result2 error) {
+	fake.execReleaseObservedLockMutex.Lock()
+	defer fake.execReleaseObservedLockMutex.Unlock()
+	fake.ExecReleaseObservedLockStub = nil
+	fake.execReleaseObservedLockReturns = struct {
+		result1 int64
+		result2 error
+	}{result1, result2}
 }
 
-func (fake *FakeGateway) ExecInsertLockReturnsOnCall(i int, result1 error) {
-	fake.execInsertLockMutex.Lock()
-	defer fake.execInsertLockMutex.Unlock()
-	fake.ExecInsertLockStub = nil
-	if fake.execInsertLockReturnsOnCall == nil {
-		fake.execInsertLockReturnsOnCall = make(map[int]struct {
-			result1 error
+func (fake *FakeGateway) ExecReleaseObservedLockReturnsOnCall(i int, result1 int64, result2 error) {
+	fake.execReleaseObservedLockMutex.Lock()
+	defer fake.execReleaseObservedLockMutex.Unlock()
+	fake.ExecReleaseObservedLockStub = nil
+	if fake.execReleaseObservedLockReturnsOnCall == nil {
+		fake.execReleaseObservedLockReturnsOnCall = make(map[int]struct {
+			result1 int64
+			result2 error
 		})
 	}
-	fake.execInsertLockReturnsOnCall[i] = struct {
-		result1 error
-	}{result1}
+	fake.execReleaseObservedLockReturnsOnCall[i] = struct {
+		result1 int64
+		result2 error
+	}{result1, result2}
 }
 
-func (fake *FakeGateway) ExecInsertRevision(arg1 context.Context, arg2 *ent.ExecInsertRevisionParams) error {
-	fake.execInsertRevisionMutex.Lock()
-	ret, specificReturn := fake.execInsertRevisionReturnsOnCall[len(fake.execInsertRevisionArgsForCall)]
-	fake.execInsertRevisionArgsForCall = append(fake.execInsertRevisionArgsForCall, struct {
+func (fake *FakeGateway) ExecTakeoverLock(arg1 context.Context, arg2 *ent.ExecTakeoverLockParams) (int64, error) {
+	fake.execTakeoverLockMutex.Lock()
+	ret, specificReturn := fake.execTakeoverLockReturnsOnCall[len(fake.execTakeoverLockArgsForCall)]
+	fake.execTakeoverLockArgsForCall = append(fake.execTakeoverLockArgsForCall, struct {
 		arg1 context.Context
-		arg2 *ent.ExecInsertRevisionParams
+		arg2 *ent.ExecTakeoverLockParams
 	}{arg1, arg2})
-	stub := fake.ExecInsertRevisionStub
-	fakeReturns := fake.execInsertRevisionReturns
-	fake.recordInvocation("ExecInsertRevision", []interface{}{arg1, arg2})
-	fake.execInsertRevisionMutex.Unlock()
+	stub := fake.ExecTakeoverLockStub
+	fakeReturns := fake.execTakeoverLockReturns
+	fake.recordInvocation("ExecTakeoverLock", []interface{}{arg1, arg2})
+	fake.execTakeoverLockMutex.Unlock()
 	if stub != nil {
 		return stub(arg1, arg2)
 	}
 	if specificReturn {
-		return ret.result1
+		return ret.result1, ret.result2
 	}
-	return fakeReturns.result1
+	return fakeReturns.result1, fakeReturns.result2
 }
 
-func (fake *FakeGateway) ExecInsertRevisionCallCount() int {
-	fake.execInsertRevisionMutex.RLock()
-	defer fake.execInsertRevisionMutex.RUnlock()
-	return len(fake.execInsertRevisionArgsForCall)
+func (fake *FakeGateway) ExecTakeoverLockCallCount() int {
+	fake.execTakeoverLockMutex.RLock()
+	defer fake.execTakeoverLockMutex.RUnlock()
+	return len(fake.execTakeoverLockArgsForCall)
 }
 
-func (fake *FakeGateway) ExecInsertRevisionCalls(stub func(context.Context, *ent.ExecInsertRevisionParams) error) {
-	fake.execInsertRevisionMutex.Lock()
-	defer fake.execInsertRevisionMutex.Unlock()
-	fake.ExecInsertRevisionStub = stub
+func (fake *FakeGateway) ExecTakeoverLockCalls(stub func(context.Context, *ent.ExecTakeoverLockParams) (int64, error)) {
+	fake.execTakeoverLockMutex.Lock()
+	defer fake.execTakeoverLockMutex.Unlock()
+	fake.ExecTakeoverLockStub = stub
 }
 
-func (fake *FakeGateway) ExecInsertRevisionArgsForCall(i int) (context.Context, *ent.ExecInsertRevisionParams) {
-	fake.execInsertRevisionMutex.RLock()
-	defer fake.execInsertRevisionMutex.RUnlock()
-	argsForCall := fake.execInsertRevisionArgsForCall[i]
+func (fake *FakeGateway) ExecTakeoverLockArgsForCall(i int) (context.Context, *ent.ExecTakeoverLockParams) {
+	fake.execTakeoverLockMutex.RLock()
+	defer fake.execTakeoverLockMutex.RUnlock()
+	argsForCall := fake.execTakeoverLockArgsForCall[i]
 	return argsForCall.arg1, argsForCall.arg2
 }
 
-func (fake *FakeGateway) ExecInsertRevisionReturns(result1 error) {
-	fake.execInsertRevisionMutex.Lock()
-	defer fake.execInsertRevisionMutex.Unlock()
-	fake.ExecInsertRevisionStub = nil
-	fake.execInsertRevisionReturns = struct {
-		result1 error
-	}{result1}
+func (fake *FakeGateway) ExecTakeoverLockReturns(result1 int64, result2 error) {
+	fake.execTakeoverLockMutex.Lock()
+	defer fake.execTakeoverLockMutex.Unlock()
+	fake.ExecTakeoverLockStub = nil
+	fake.execTakeoverLockReturns = struct {
+		result1 int64
+		result2 error
+	}{result1, result2}
 }
 
-func (fake *FakeGateway) ExecInsertRevisionReturnsOnCall(i int, result1 error) {
-	fake.execInsertRevisionMutex.Lock()
-	defer fake.execInsertRevisionMutex.Unlock()
-	fake.ExecInsertRevisionStub = nil
-	if fake.execInsertRevisionReturnsOnCall == nil {
-		fake.execInsertRevisionReturnsOnCall = make(map[int]struct {
-			result1 error
+func (fake *FakeGateway) ExecTakeoverLockReturnsOnCall(i int, result1 int64, result2 error) {
+	fake.execTakeoverLockMutex.Lock()
+	defer fake.execTakeoverLockMutex.Unlock()
+	fake.ExecTakeoverLockStub = nil
+	if fake.execTakeoverLockReturnsOnCall == nil {
+		fake.execTakeoverLockReturnsOnCall = make(map[int]struct {
+			result1 int64
+			result2 error
 		})
 	}
-	fake.execInsertRevisionReturnsOnCall[i] = struct {
-		result1 error
-	}{result1}
+	fake.execTakeoverLockReturnsOnCall[i] = struct {
+		result1 int64
+		result2 error
+	}{result1, result2}
 }
 
 func (fake *FakeGateway) ExecUpdateRevision(arg1 context.Context, arg2 *ent.ExecUpdateRevisionParams) error {
@@ -1308,6 +2494,68 @@ func (fake *FakeGateway) ExecUpdateRevisionReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeGateway) ExecUpdateRevisionEvent(arg1 context.Context, arg2 *ent.ExecUpdateRevisionEventParams) error {
+	fake.execUpdateRevisionEventMutex.Lock()
+	ret, specificReturn := fake.execUpdateRevisionEventReturnsOnCall[len(fake.execUpdateRevisionEventArgsForCall)]
+	fake.execUpdateRevisionEventArgsForCall = append(fake.execUpdateRevisionEventArgsForCall, struct {
+		arg1 context.Context
+		arg2 *ent.ExecUpdateRevisionEventParams
+	}{arg1, arg2})
+	stub := fake.ExecUpdateRevisionEventStub
+	fakeReturns := fake.execUpdateRevisionEventReturns
+	fake.recordInvocation("ExecUpdateRevisionEvent", []interface{}{arg1, arg2})
+	fake.execUpdateRevisionEventMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeGateway) ExecUpdateRevisionEventCallCount() int {
+	fake.execUpdateRevisionEventMutex.RLock()
+	defer fake.execUpdateRevisionEventMutex.RUnlock()
+	return len(fake.execUpdateRevisionEventArgsForCall)
+}
+
+func (fake *FakeGateway) ExecUpdateRevisionEventCalls(stub func(context.Context, *ent.ExecUpdateRevisionEventParams) error) {
+	fake.execUpdateRevisionEventMutex.Lock()
+	defer fake.execUpdateRevisionEventMutex.Unlock()
+	fake.ExecUpdateRevisionEventStub = stub
+}
+
+func (fake *FakeGateway) ExecUpdateRevisionEventArgsForCall(i int) (context.Context, *ent.ExecUpdateRevisionEventParams) {
+	fake.execUpdateRevisionEventMutex.RLock()
+	defer fake.execUpdateRevisionEventMutex.RUnlock()
+	argsForCall := fake.execUpdateRevisionEventArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeGateway) ExecUpdateRevisionEventReturns(result1 error) {
+	fake.execUpdateRevisionEventMutex.Lock()
+	defer fake.execUpdateRevisionEventMutex.Unlock()
+	fake.ExecUpdateRevisionEventStub = nil
+	fake.execUpdateRevisionEventReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGateway) ExecUpdateRevisionEventReturnsOnCall(i int, result1 error) {
+	fake.execUpdateRevisionEventMutex.Lock()
+	defer fake.execUpdateRevisionEventMutex.Unlock()
+	fake.ExecUpdateRevisionEventStub = nil
+	if fake.execUpdateRevisionEventReturnsOnCall == nil {
+		fake.execUpdateRevisionEventReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.execUpdateRevisionEventReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeGateway) ExecUpsertRevision(arg1 context.Context, arg2 *ent.ExecUpsertRevisionParams) error {
 	fake.execUpsertRevisionMutex.Lock()
 	ret, specificReturn := fake.execUpsertRevisionReturnsOnCall[len(fake.execUpsertRevisionArgsForCall)]
@@ -1435,6 +2683,136 @@ func (fake *FakeGateway) GetJobReturnsOnCall(i int, result1 *ent.Job, result2 er
 	}{result1, result2}
 }
 
+func (fake *FakeGateway) GetLegacyLock(arg1 context.Context, arg2 *ent.GetLegacyLockParams) (*ent.GetLegacyLockRow, error) {
+	fake.getLegacyLockMutex.Lock()
+	ret, specificReturn := fake.getLegacyLockReturnsOnCall[len(fake.getLegacyLockArgsForCall)]
+	fake.getLegacyLockArgsForCall = append(fake.getLegacyLockArgsForCall, struct {
+		arg1 context.Context
+		arg2 *ent.GetLegacyLockParams
+	}{arg1, arg2})
+	stub := fake.GetLegacyLockStub
+	fakeReturns := fake.getLegacyLockReturns
+	fake.recordInvocation("GetLegacyLock", []interface{}{arg1, arg2})
+	fake.getLegacyLockMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeGateway) GetLegacyLockCallCount() int {
+	fake.getLegacyLockMutex.RLock()
+	defer fake.getLegacyLockMutex.RUnlock()
+	return len(fake.getLegacyLockArgsForCall)
+}
+
+func (fake *FakeGateway) GetLegacyLockCalls(stub func(context.Context, *ent.GetLegacyLockParams) (*ent.GetLegacyLockRow, error)) {
+	fake.getLegacyLockMutex.Lock()
+	defer fake.getLegacyLockMutex.Unlock()
+	fake.GetLegacyLockStub = stub
+}
+
+func (fake *FakeGateway) GetLegacyLockArgsForCall(i int) (context.Context, *ent.GetLegacyLockParams) {
+	fake.getLegacyLockMutex.RLock()
+	defer fake.getLegacyLockMutex.RUnlock()
+	argsForCall := fake.getLegacyLockArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeGateway) GetLegacyLockReturns(result1 *ent.GetLegacyLockRow, result2 error) {
+	fake.getLegacyLockMutex.Lock()
+	defer fake.getLegacyLockMutex.Unlock()
+	fake.GetLegacyLockStub = nil
+	fake.getLegacyLockReturns = struct {
+		result1 *ent.GetLegacyLockRow
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGateway) GetLegacyLockReturnsOnCall(i int, result1 *ent.GetLegacyLockRow, result2 error) {
+	fake.getLegacyLockMutex.Lock()
+	defer fake.getLegacyLockMutex.Unlock()
+	fake.GetLegacyLockStub = nil
+	if fake.getLegacyLockReturnsOnCall == nil {
+		fake.getLegacyLockReturnsOnCall = make(map[int]struct {
+			result1 *ent.GetLegacyLockRow
+			result2 error
+		})
+	}
+	fake.getLegacyLockReturnsOnCall[i] = struct {
+		result1 *ent.GetLegacyLockRow
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGateway) GetLegacyRevision(arg1 context.Context, arg2 *ent.GetLegacyRevisionParams) (*ent.GetLegacyRevisionRow, error) {
+	fake.getLegacyRevisionMutex.Lock()
+	ret, specificReturn := fake.getLegacyRevisionReturnsOnCall[len(fake.getLegacyRevisionArgsForCall)]
+	fake.getLegacyRevisionArgsForCall = append(fake.getLegacyRevisionArgsForCall, struct {
+		arg1 context.Context
+		arg2 *ent.GetLegacyRevisionParams
+	}{arg1, arg2})
+	stub := fake.GetLegacyRevisionStub
+	fakeReturns := fake.getLegacyRevisionReturns
+	fake.recordInvocation("GetLegacyRevision", []interface{}{arg1, arg2})
+	fake.getLegacyRevisionMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeGateway) GetLegacyRevisionCallCount() int {
+	fake.getLegacyRevisionMutex.RLock()
+	defer fake.getLegacyRevisionMutex.RUnlock()
+	return len(fake.getLegacyRevisionArgsForCall)
+}
+
+func (fake *FakeGateway) GetLegacyRevisionCalls(stub func(context.Context, *ent.GetLegacyRevisionParams) (*ent.GetLegacyRevisionRow, error)) {
+	fake.getLegacyRevisionMutex.Lock()
+	defer fake.getLegacyRevisionMutex.Unlock()
+	fake.GetLegacyRevisionStub = stub
+}
+
+func (fake *FakeGateway) GetLegacyRevisionArgsForCall(i int) (context.Context, *ent.GetLegacyRevisionParams) {
+	fake.getLegacyRevisionMutex.RLock()
+	defer fake.getLegacyRevisionMutex.RUnlock()
+	argsForCall := fake.getLegacyRevisionArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeGateway) GetLegacyRevisionReturns(result1 *ent.GetLegacyRevisionRow, result2 error) {
+	fake.getLegacyRevisionMutex.Lock()
+	defer fake.getLegacyRevisionMutex.Unlock()
+	fake.GetLegacyRevisionStub = nil
+	fake.getLegacyRevisionReturns = struct {
+		result1 *ent.GetLegacyRevisionRow
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGateway) GetLegacyRevisionReturnsOnCall(i int, result1 *ent.GetLegacyRevisionRow, result2 error) {
+	fake.getLegacyRevisionMutex.Lock()
+	defer fake.getLegacyRevisionMutex.Unlock()
+	fake.GetLegacyRevisionStub = nil
+	if fake.getLegacyRevisionReturnsOnCall == nil {
+		fake.getLegacyRevisionReturnsOnCall = make(map[int]struct {
+			result1 *ent.GetLegacyRevisionRow
+			result2 error
+		})
+	}
+	fake.getLegacyRevisionReturnsOnCall[i] = struct {
+		result1 *ent.GetLegacyRevisionRow
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeGateway) GetLock(arg1 context.Context, arg2 *ent.GetLockParams) (*ent.Lock, error) {
 	fake.getLockMutex.Lock()
 	ret, specificReturn := fake.getLockReturnsOnCall[len(fake.getLockArgsForCall)]
@@ -1565,6 +2943,70 @@ func (fake *FakeGateway) GetRevisionReturnsOnCall(i int, result1 *ent.Revision,
 	}{result1, result2}
 }
 
+func (fake *FakeGateway) HasIAMRoleMappings(arg1 context.Context) (bool, error) {
+	fake.hasIAMRoleMappingsMutex.Lock()
+	ret, specificReturn := fake.hasIAMRoleMappingsReturnsOnCall[len(fake.hasIAMRoleMappingsArgsForCall)]
+	fake.hasIAMRoleMappingsArgsForCall = append(fake.hasIAMRoleMappingsArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.HasIAMRoleMappingsStub
+	fakeReturns := fake.hasIAMRoleMappingsReturns
+	fake.recordInvocation("HasIAMRoleMappings", []interface{}{arg1})
+	fake.hasIAMRoleMappingsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeGateway) HasIAMRoleMappingsCallCount() int {
+	fake.hasIAMRoleMappingsMutex.RLock()
+	defer fake.hasIAMRoleMappingsMutex.RUnlock()
+	return len(fake.hasIAMRoleMappingsArgsForCall)
+}
+
+func (fake *FakeGateway) HasIAMRoleMappingsCalls(stub func(context.Context) (bool, error)) {
+	fake.hasIAMRoleMappingsMutex.Lock()
+	defer fake.hasIAMRoleMappingsMutex.Unlock()
+	fake.HasIAMRoleMappingsStub = stub
+}
+
+func (fake *FakeGateway) HasIAMRoleMappingsArgsForCall(i int) context.Context {
+	fake.hasIAMRoleMappingsMutex.RLock()
+	defer fake.hasIAMRoleMappingsMutex.RUnlock()
+	argsForCall := fake.hasIAMRoleMappingsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeGateway) HasIAMRoleMappingsReturns(result1 bool, result2 error) {
+	fake.hasIAMRoleMappingsMutex.Lock()
+	defer fake.hasIAMRoleMappingsMutex.Unlock()
+	fake.HasIAMRoleMappingsStub = nil
+	fake.hasIAMRoleMappingsReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGateway) HasIAMRoleMappingsReturnsOnCall(i int, result1 bool, result2 error) {
+	fake.hasIAMRoleMappingsMutex.Lock()
+	defer fake.hasIAMRoleMappingsMutex.Unlock()
+	fake.HasIAMRoleMappingsStub = nil
+	if fake.hasIAMRoleMappingsReturnsOnCall == nil {
+		fake.hasIAMRoleMappingsReturnsOnCall = make(map[int]struct {
+			result1 bool
+			result2 error
+		})
+	}
+	fake.hasIAMRoleMappingsReturnsOnCall[i] = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeGateway) InsertJob(arg1 context.Context, arg2 *ent.InsertJobParams) (*ent.Job, error) {
 	fake.insertJobMutex.Lock()
 	ret, specificReturn := fake.insertJobReturnsOnCall[len(fake.insertJobArgsForCall)]
@@ -1760,6 +3202,136 @@ func (fake *FakeGateway) InsertRevisionReturnsOnCall(i int, result1 *ent.Revisio
 	}{result1, result2}
 }
 
+func (fake *FakeGateway) InsertRevisionEvent(arg1 context.Context, arg2 *ent.InsertRevisionEventParams) (*ent.RevisionEvent, error) {
+	fake.insertRevisionEventMutex.Lock()
+	ret, specificReturn := fake.insertRevisionEventReturnsOnCall[len(fake.insertRevisionEventArgsForCall)]
+	fake.insertRevisionEventArgsForCall = append(fake.insertRevisionEventArgsForCall, struct {
+		arg1 context.Context
+		arg2 *ent.InsertRevisionEventParams
+	}{arg1, arg2})
+	stub := fake.InsertRevisionEventStub
+	fakeReturns := fake.insertRevisionEventReturns
+	fake.recordInvocation("InsertRevisionEvent", []interface{}{arg1, arg2})
+	fake.insertRevisionEventMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeGateway) InsertRevisionEventCallCount() int {
+	fake.insertRevisionEventMutex.RLock()
+	defer fake.insertRevisionEventMutex.RUnlock()
+	return len(fake.insertRevisionEventArgsForCall)
+}
+
+func (fake *FakeGateway) InsertRevisionEventCalls(stub func(context.Context, *ent.InsertRevisionEventParams) (*ent.RevisionEvent, error)) {
+	fake.insertRevisionEventMutex.Lock()
+	defer fake.insertRevisionEventMutex.Unlock()
+	fake.InsertRevisionEventStub = stub
+}
+
+func (fake *FakeGateway) InsertRevisionEventArgsForCall(i int) (context.Context, *ent.InsertRevisionEventParams) {
+	fake.insertRevisionEventMutex.RLock()
+	defer fake.insertRevisionEventMutex.RUnlock()
+	argsForCall := fake.insertRevisionEventArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeGateway) InsertRevisionEventReturns(result1 *ent.RevisionEvent, result2 error) {
+	fake.insertRevisionEventMutex.Lock()
+	defer fake.insertRevisionEventMutex.Unlock()
+	fake.InsertRevisionEventStub = nil
+	fake.insertRevisionEventReturns = struct {
+		result1 *ent.RevisionEvent
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGateway) InsertRevisionEventReturnsOnCall(i int, result1 *ent.RevisionEvent, result2 error) {
+	fake.insertRevisionEventMutex.Lock()
+	defer fake.insertRevisionEventMutex.Unlock()
+	fake.InsertRevisionEventStub = nil
+	if fake.insertRevisionEventReturnsOnCall == nil {
+		fake.insertRevisionEventReturnsOnCall = make(map[int]struct {
+			result1 *ent.RevisionEvent
+			result2 error
+		})
+	}
+	fake.insertRevisionEventReturnsOnCall[i] = struct {
+		result1 *ent.RevisionEvent
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGateway) ListRevisionEvents(arg1 context.Context, arg2 *ent.ListRevisionEventsParams) ([]*ent.RevisionEvent, error) {
+	fake.listRevisionEventsMutex.Lock()
+	ret, specificReturn := fake.listRevisionEventsReturnsOnCall[len(fake.listRevisionEventsArgsForCall)]
+	fake.listRevisionEventsArgsForCall = append(fake.listRevisionEventsArgsForCall, struct {
+		arg1 context.Context
+		arg2 *ent.ListRevisionEventsParams
+	}{arg1, arg2})
+	stub := fake.ListRevisionEventsStub
+	fakeReturns := fake.listRevisionEventsReturns
+	fake.recordInvocation("ListRevisionEvents", []interface{}{arg1, arg2})
+	fake.listRevisionEventsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeGateway) ListRevisionEventsCallCount() int {
+	fake.listRevisionEventsMutex.RLock()
+	defer fake.listRevisionEventsMutex.RUnlock()
+	return len(fake.listRevisionEventsArgsForCall)
+}
+
+func (fake *FakeGateway) ListRevisionEventsCalls(stub func(context.Context, *ent.ListRevisionEventsParams) ([]*ent.RevisionEvent, error)) {
+	fake.listRevisionEventsMutex.Lock()
+	defer fake.listRevisionEventsMutex.Unlock()
+	fake.ListRevisionEventsStub = stub
+}
+
+func (fake *FakeGateway) ListRevisionEventsArgsForCall(i int) (context.Context, *ent.ListRevisionEventsParams) {
+	fake.listRevisionEventsMutex.RLock()
+	defer fake.listRevisionEventsMutex.RUnlock()
+	argsForCall := fake.listRevisionEventsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeGateway) ListRevisionEventsReturns(result1 []*ent.RevisionEvent, result2 error) {
+	fake.listRevisionEventsMutex.Lock()
+	defer fake.listRevisionEventsMutex.Unlock()
+	fake.ListRevisionEventsStub = nil
+	fake.listRevisionEventsReturns = struct {
+		result1 []*ent.RevisionEvent
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGateway) ListRevisionEventsReturnsOnCall(i int, result1 []*ent.RevisionEvent, result2 error) {
+	fake.listRevisionEventsMutex.Lock()
+	defer fake.listRevisionEventsMutex.Unlock()
+	fake.ListRevisionEventsStub = nil
+	if fake.listRevisionEventsReturnsOnCall == nil {
+		fake.listRevisionEventsReturnsOnCall = make(map[int]struct {
+			result1 []*ent.RevisionEvent
+			result2 error
+		})
+	}
+	fake.listRevisionEventsReturnsOnCall[i] = struct {
+		result1 []*ent.RevisionEvent
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeGateway) ListRevisions(arg1 context.Context, arg2 *ent.ListRevisionsParams) ([]*ent.Revision, error) {
 	fake.listRevisionsMutex.Lock()
 	ret, specificReturn := fake.listRevisionsReturnsOnCall[len(fake.listRevisionsArgsForCall)]
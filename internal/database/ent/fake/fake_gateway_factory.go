@@ -1,5 +1,7 @@
 package fake
 
+import "github.com/aws-contrib/aurora/internal/database/ent"
+
 // NewFakeGateway returns a new instance of FakeGateway with a predefined transaction return value.
 func NewFakeGateway() *FakeGateway {
 	gateway := &FakeGateway{}
@@ -10,6 +12,9 @@ func NewFakeGateway() *FakeGateway {
 	gateway.UpsertRevisionReturns(NewFakeRevision(), nil)
 	gateway.UpdateRevisionReturns(NewFakeRevision(), nil)
 	gateway.DeleteRevisionReturns(NewFakeRevision(), nil)
+	// revision event operations
+	gateway.InsertRevisionEventReturns(NewFakeRevisionEvent(), nil)
+	gateway.ListRevisionEventsReturns([]*ent.RevisionEvent{NewFakeRevisionEvent()}, nil)
 	// job operations
 	gateway.GetJobReturns(NewFakeJob(), nil)
 	gateway.InsertJobReturns(NewFakeJob(), nil)
@@ -18,6 +23,8 @@ func NewFakeGateway() *FakeGateway {
 	gateway.GetLockReturns(NewFakeLock(), nil)
 	gateway.InsertLockReturns(NewFakeLock(), nil)
 	gateway.DeleteLockReturns(NewFakeLock(), nil)
+	gateway.ExecRefreshLockReturns(1, nil)
+	gateway.ExecTakeoverLockReturns(1, nil)
 
 	return gateway
 }
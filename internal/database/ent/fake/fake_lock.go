@@ -9,8 +9,21 @@ import (
 
 // NewFakeLock returns a new fake revision.
 func NewFakeLock() *ent.Lock {
+	var (
+		owner     = uuid.New().String()
+		hostname  = "localhost"
+		pid       = 42
+		version   = "v1.0.0"
+		expiresAt = time.Now().Add(time.Minute).Truncate(time.Millisecond)
+	)
+
 	return &ent.Lock{
 		ID:        uuid.New().String(),
 		CreatedAt: time.Now().Truncate(time.Millisecond),
+		Owner:     &owner,
+		Hostname:  &hostname,
+		Pid:       &pid,
+		Version:   &version,
+		ExpiresAt: &expiresAt,
 	}
 }
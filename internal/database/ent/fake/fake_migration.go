@@ -1,14 +1,16 @@
 package fake
 
-import "github.com/aws-contrib/aurora/internal/database/ent"
+import (
+	"github.com/aws-contrib/aurora/internal/database/ent"
+	"github.com/aws-contrib/aurora/internal/database/ent/parser"
+)
 
 // NewFakeMigration creates a new fake migration entity with a revision.
 func NewFakeMigration() *ent.Migration {
 	entity := &ent.Migration{}
 	entity.Revision = NewFakeRevision()
-	entity.Statements = []string{
-		"CREATE TABLE IF NOT EXISTS example (id SERIAL PRIMARY KEY, name VARCHAR(255));",
-	}
+	entity.Statements, _ = parser.Split("CREATE TABLE IF NOT EXISTS example (id SERIAL PRIMARY KEY, name VARCHAR(255));")
+	entity.Hash = ent.Checksum([]byte(entity.Statements[0].Text))
 
 	return entity
 }
@@ -0,0 +1,34 @@
+package fake
+
+import (
+	"time"
+
+	"github.com/aws-contrib/aurora/internal/database/ent"
+	"github.com/google/uuid"
+)
+
+// NewFakeRevisionEvent returns a new fake revision event.
+func NewFakeRevisionEvent() *ent.RevisionEvent {
+	var (
+		index      = 0
+		statement  = "CREATE TABLE IF NOT EXISTS example (id INT PRIMARY KEY);"
+		operator   = "root@localhost"
+		version    = "v1.0.0"
+		startedAt  = time.Now().Truncate(time.Millisecond)
+		finishedAt = startedAt.Add(500 * time.Millisecond)
+	)
+
+	return &ent.RevisionEvent{
+		ID:             uuid.New().String(),
+		RevisionID:     uuid.New().String(),
+		Action:         ent.RevisionEventApply,
+		CreatedAt:      startedAt,
+		StatementIndex: &index,
+		Statement:      &statement,
+		Outcome:        ent.RevisionEventSucceeded,
+		Operator:       &operator,
+		Version:        &version,
+		StartedAt:      startedAt,
+		FinishedAt:     &finishedAt,
+	}
+}
@@ -4,6 +4,7 @@ package ent
 
 import (
 	"context"
+	"fmt"
 	"time"
 )
 
@@ -12,18 +13,45 @@ type JobRepository struct {
 	Gateway Gateway
 }
 
+// JobTimeoutError occurs when a job does not complete within the timeout.
+type JobTimeoutError struct {
+	// JobID is the ID of the job.
+	JobID string
+	// Status is the last known status of the job.
+	Status string
+	// Timeout is the time waited for the job.
+	Timeout time.Duration
+}
+
+// Error implements error.
+func (x *JobTimeoutError) Error() string {
+	return fmt.Sprintf("job %s did not complete within %s (last status: %s)", x.JobID, x.Timeout, x.Status)
+}
+
 // WaitJobParams is the parameters for the WaitJob method.
 type WaitJobParams struct {
 	// Job is the job to wait for.
 	JobID string
+	// Timeout is the maximum time to wait for the job. Zero means no timeout.
+	Timeout time.Duration
+	// Backoff is the polling policy. Defaults to DefaultBackoff.
+	Backoff *Backoff
 }
 
-// WaitJob waits for a job to complete and returns the job details.
+// WaitJob waits for a job to complete and returns the job details. It
+// returns a *JobTimeoutError if the job does not complete within the timeout.
 func (x *JobRepository) WaitJob(ctx context.Context, params *WaitJobParams) (*Job, error) {
 	args := &GetJobParams{}
 	args.JobID = params.JobID
 
-	for {
+	backoff := params.Backoff
+	if backoff == nil {
+		backoff = DefaultBackoff
+	}
+
+	start := time.Now()
+
+	for attempt := 0; ; attempt++ {
 		job, err := x.Gateway.GetJob(ctx, args)
 		switch {
 		case err != nil:
@@ -31,9 +59,25 @@ func (x *JobRepository) WaitJob(ctx context.Context, params *WaitJobParams) (*Jo
 		case
 			job.Status == "submitted",
 			job.Status == "processing":
-			time.Sleep(100 * time.Millisecond)
 		default:
 			return job, nil
 		}
+
+		interval := backoff.Interval(attempt)
+		// Do not sleep past the timeout
+		if params.Timeout > 0 {
+			remaining := params.Timeout - time.Since(start)
+			if remaining <= 0 {
+				return nil, &JobTimeoutError{JobID: job.JobID, Status: job.Status, Timeout: params.Timeout}
+			}
+
+			interval = min(interval, remaining)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(interval):
+		}
 	}
 }
@@ -3,7 +3,10 @@
 package ent_test
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/aws-contrib/aurora/internal/database/ent"
 
@@ -67,6 +70,48 @@ var _ = Describe("JobRepository", func() {
 			})
 		})
 
+		When("the job does not complete within the timeout", func() {
+			BeforeEach(func() {
+				entity := NewFakeJob()
+				entity.Status = "processing"
+
+				gateway := repository.Gateway.(*FakeGateway)
+				gateway.GetJobReturns(entity, nil)
+
+				params.Timeout = 50 * time.Millisecond
+				params.Backoff = &ent.Backoff{Initial: 10 * time.Millisecond}
+			})
+
+			It("returns a timeout error", func(ctx SpecContext) {
+				job, err := repository.WaitJob(ctx, params)
+				Expect(job).To(BeNil())
+
+				var timeout *ent.JobTimeoutError
+				Expect(errors.As(err, &timeout)).To(BeTrue())
+				Expect(timeout.Status).To(Equal("processing"))
+				Expect(timeout.Timeout).To(Equal(params.Timeout))
+			})
+		})
+
+		When("the context is done", func() {
+			BeforeEach(func() {
+				entity := NewFakeJob()
+				entity.Status = "processing"
+
+				gateway := repository.Gateway.(*FakeGateway)
+				gateway.GetJobReturns(entity, nil)
+			})
+
+			It("returns an error", func(ctx SpecContext) {
+				cctx, cancel := context.WithCancel(ctx)
+				cancel()
+
+				job, err := repository.WaitJob(cctx, params)
+				Expect(err).To(MatchError(context.Canceled))
+				Expect(job).To(BeNil())
+			})
+		})
+
 		When("the gateway fails", func() {
 			BeforeEach(func() {
 				gateway := repository.Gateway.(*FakeGateway)
@@ -0,0 +1,160 @@
+package lint
+
+import (
+	pg "github.com/pganalyze/pg_query_go/v6"
+)
+
+// Destructive reports the statements that delete data, or that can lose or
+// reject existing data. Such statements are only applied when they are
+// explicitly allowed.
+var Destructive Analyzer = Catalogue{
+	{
+		Name:       "drop-table",
+		Severity:   SeverityWarning,
+		Message:    "DROP TABLE deletes the table and its data",
+		Suggestion: "make sure the data is no longer needed, or back it up first",
+		Match: func(stmt *Statement) (int, bool) {
+			return 0, stmt.Node.GetDropStmt().GetRemoveType() == pg.ObjectType_OBJECT_TABLE
+		},
+	},
+	{
+		Name:       "drop-schema",
+		Severity:   SeverityWarning,
+		Message:    "DROP SCHEMA deletes the schema and, with CASCADE, the tables it contains",
+		Suggestion: "make sure the data is no longer needed, or back it up first",
+		Match: func(stmt *Statement) (int, bool) {
+			return 0, stmt.Node.GetDropStmt().GetRemoveType() == pg.ObjectType_OBJECT_SCHEMA
+		},
+	},
+	{
+		Name:       "drop-column",
+		Severity:   SeverityWarning,
+		Message:    "DROP COLUMN deletes the data of the column",
+		Suggestion: "make sure the data is no longer needed, or back it up first",
+		Match: func(stmt *Statement) (int, bool) {
+			for index, cmd := range stmt.Commands() {
+				if cmd.GetSubtype() == pg.AlterTableType_AT_DropColumn {
+					return stmt.command(index), true
+				}
+			}
+
+			return -1, false
+		},
+	},
+	{
+		Name:       "alter-column-type",
+		Severity:   SeverityWarning,
+		Message:    "changing the type of a column can truncate its data, or fail on the existing rows",
+		Suggestion: "make sure the new type can hold every existing value, or add a new column and copy the data",
+		Match: func(stmt *Statement) (int, bool) {
+			for index, cmd := range stmt.Commands() {
+				if cmd.GetSubtype() == pg.AlterTableType_AT_AlterColumnType {
+					return alteration(stmt, index, cmd.GetName()), true
+				}
+			}
+
+			return -1, false
+		},
+	},
+	{
+		Name:       "set-not-null",
+		Severity:   SeverityWarning,
+		Message:    "SET NOT NULL fails when an existing row has no value for the column",
+		Suggestion: "fill the missing values with an UPDATE before the constraint is added",
+		Match: func(stmt *Statement) (int, bool) {
+			for index, cmd := range stmt.Commands() {
+				if cmd.GetSubtype() == pg.AlterTableType_AT_SetNotNull {
+					return alteration(stmt, index, cmd.GetName()), true
+				}
+			}
+
+			return -1, false
+		},
+	},
+	{
+		Name:       "add-column-not-null",
+		Severity:   SeverityWarning,
+		Message:    "ADD COLUMN with NOT NULL and without DEFAULT fails when the table has rows",
+		Suggestion: "add a DEFAULT, or add the column as nullable, fill it, and then SET NOT NULL",
+		Match: func(stmt *Statement) (int, bool) {
+			for _, cmd := range stmt.Commands() {
+				if column := cmd.GetDef().GetColumnDef(); column != nil && cmd.GetSubtype() == pg.AlterTableType_AT_AddColumn {
+					if offset, ok := required(column); ok {
+						return offset, true
+					}
+				}
+			}
+
+			return -1, false
+		},
+	},
+	{
+		Name:       "unbounded-delete",
+		Severity:   SeverityWarning,
+		Message:    "the statement deletes every row of the table",
+		Suggestion: "add a WHERE clause, or make sure the data is no longer needed",
+		Match: func(stmt *Statement) (int, bool) {
+			switch node := stmt.Node; {
+			case node.GetTruncateStmt() != nil:
+				return 0, true
+			case node.GetDeleteStmt() != nil:
+				return 0, node.GetDeleteStmt().GetWhereClause() == nil
+			default:
+				return -1, false
+			}
+		},
+	},
+	{
+		Name:       "unbounded-update",
+		Severity:   SeverityWarning,
+		Message:    "the statement updates every row of the table",
+		Suggestion: "add a WHERE clause, or make sure every row must be updated",
+		Match: func(stmt *Statement) (int, bool) {
+			update := stmt.Node.GetUpdateStmt()
+			return 0, update != nil && update.GetWhereClause() == nil
+		},
+	},
+}
+
+// alteration returns the offset of the change of the ALTER COLUMN command
+// at the given index (e.g. TYPE, SET NOT NULL), that follows the name of
+// the column.
+func alteration(stmt *Statement, index int, column string) int {
+	tokens := stmt.Command(index)
+
+	next := 2
+	if len(tokens) > 3 && tokens[1].Is("COLUMN") && tokens[2].Value() == column {
+		next = 3
+	}
+
+	if next < len(tokens) {
+		return tokens[next].Pos.Offset
+	}
+
+	return 0
+}
+
+// required returns the location of the NOT NULL, or PRIMARY KEY, constraint
+// of a column that has no value for the existing rows: a column without a
+// default, an identity or a generated expression.
+func required(column *pg.ColumnDef) (int, bool) {
+	location := -1
+
+	for _, node := range column.GetConstraints() {
+		switch constraint := node.GetConstraint(); constraint.GetContype() {
+		case pg.ConstrType_CONSTR_NOTNULL, pg.ConstrType_CONSTR_PRIMARY:
+			if location < 0 {
+				location = int(constraint.GetLocation())
+			}
+		case pg.ConstrType_CONSTR_DEFAULT:
+			// DEFAULT NULL is no value
+			if value := constraint.GetRawExpr().GetAConst(); value == nil || !value.GetIsnull() {
+				return -1, false
+			}
+		case pg.ConstrType_CONSTR_IDENTITY, pg.ConstrType_CONSTR_GENERATED:
+			return -1, false
+		}
+	}
+
+	return location, location >= 0
+}
@@ -0,0 +1,63 @@
+package lint_test
+
+import (
+	"github.com/aws-contrib/aurora/internal/database/ent/lint"
+	"github.com/aws-contrib/aurora/internal/database/ent/parser"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Destructive", func() {
+	analyze := func(text string) []*lint.Finding {
+		statements, err := parser.Split(text)
+		Expect(err).NotTo(HaveOccurred())
+
+		file := &lint.File{Name: "20250101000000_schema.sql", Statements: statements}
+		return lint.Lint(file, lint.Destructive)
+	}
+
+	rules := func(findings []*lint.Finding) []string {
+		var collection []string
+		for _, finding := range findings {
+			collection = append(collection, finding.Rule)
+		}
+		return collection
+	}
+
+	It("accepts the statements that keep the data", func() {
+		findings := analyze("CREATE TABLE IF NOT EXISTS users (id UUID PRIMARY KEY, name TEXT);\n" +
+			"ALTER TABLE users ADD COLUMN IF NOT EXISTS email TEXT, DROP CONSTRAINT IF EXISTS uq_name;\n" +
+			"ALTER TABLE users ALTER COLUMN email DROP NOT NULL, ALTER COLUMN name SET DEFAULT 'none';\n" +
+			"ALTER TABLE users ADD COLUMN IF NOT EXISTS active BOOLEAN NOT NULL DEFAULT true;\n" +
+			"ALTER TABLE users ADD COLUMN IF NOT EXISTS number BIGINT GENERATED ALWAYS AS IDENTITY NOT NULL;\n" +
+			"DROP INDEX IF EXISTS idx_users_name;\n" +
+			"DROP VIEW IF EXISTS names;\n" +
+			"DELETE FROM users WHERE id IN (SELECT id FROM users);\n" +
+			"UPDATE users SET name = 'none' WHERE name IS NULL;\n")
+		Expect(findings).To(BeEmpty())
+	})
+
+	DescribeTable("reports the destructive statements",
+		func(text, rule string, column int) {
+			findings := analyze(text)
+			Expect(rules(findings)).To(Equal([]string{rule}))
+			Expect(findings[0].Severity).To(Equal(lint.SeverityWarning))
+			Expect(findings[0].Pos.Column).To(Equal(column))
+			Expect(findings[0].Suggestion).NotTo(BeEmpty())
+		},
+		Entry("drop table", "DROP TABLE IF EXISTS users;", "drop-table", 1),
+		Entry("drop schema", "DROP SCHEMA IF EXISTS app CASCADE;", "drop-schema", 1),
+		Entry("drop column", "ALTER TABLE users DROP COLUMN IF EXISTS name;", "drop-column", 19),
+		Entry("drop column without keyword", "ALTER TABLE app.users ADD email TEXT, DROP name;", "drop-column", 39),
+		Entry("alter column type", "ALTER TABLE users ALTER COLUMN name TYPE VARCHAR(10);", "alter-column-type", 37),
+		Entry("alter column data type", "ALTER TABLE users ALTER name SET DATA TYPE VARCHAR(10);", "alter-column-type", 30),
+		Entry("set not null", "ALTER TABLE users ALTER COLUMN name SET NOT NULL;", "set-not-null", 37),
+		Entry("add column not null", "ALTER TABLE users ADD COLUMN IF NOT EXISTS active BOOLEAN NOT NULL;", "add-column-not-null", 59),
+		Entry("add column not null with a null default", "ALTER TABLE users ADD active BOOLEAN DEFAULT NULL NOT NULL;", "add-column-not-null", 51),
+		Entry("add column primary key", "ALTER TABLE users ADD COLUMN IF NOT EXISTS code TEXT PRIMARY KEY;", "add-column-not-null", 54),
+		Entry("delete without where", "DELETE FROM users USING (SELECT id FROM users WHERE name IS NULL) AS x;", "unbounded-delete", 1),
+		Entry("truncate", "TRUNCATE users;", "unbounded-delete", 1),
+		Entry("update without where", "UPDATE users SET name = 'none';", "unbounded-update", 1),
+	)
+})
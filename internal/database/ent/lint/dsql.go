@@ -0,0 +1,182 @@
+package lint
+
+import (
+	"slices"
+
+	pg "github.com/pganalyze/pg_query_go/v6"
+)
+
+// DSQL reports the statements that Aurora DSQL does not support.
+var DSQL Analyzer = AnalyzerFunc(func(file *File) []*Finding {
+	collection := unsupported.Analyze(file)
+	return append(collection, transactions(file)...)
+})
+
+// unsupported is the catalogue of the features that Aurora DSQL does not support.
+var unsupported = Catalogue{
+	{
+		Name:       "foreign-key",
+		Severity:   SeverityError,
+		Message:    "Aurora DSQL does not support foreign key constraints",
+		Suggestion: "remove the constraint and enforce the relationship in the application",
+		Match: func(stmt *Statement) (int, bool) {
+			for _, constraint := range stmt.Constraints() {
+				if constraint.GetContype() == pg.ConstrType_CONSTR_FOREIGN {
+					// A named constraint starts with CONSTRAINT
+					return stmt.Find(int(constraint.GetLocation()), "FOREIGN", "REFERENCES")
+				}
+			}
+
+			return -1, false
+		},
+	},
+	{
+		Name:       "sequence",
+		Severity:   SeverityError,
+		Message:    "Aurora DSQL does not support sequences",
+		Suggestion: "use a UUID key with DEFAULT gen_random_uuid() instead",
+		Match: func(stmt *Statement) (int, bool) {
+			if stmt.Node.GetCreateSeqStmt() != nil || stmt.Node.GetAlterSeqStmt() != nil {
+				return 0, true
+			}
+
+			return stmt.Call("nextval")
+		},
+	},
+	{
+		Name:       "serial",
+		Severity:   SeverityError,
+		Message:    "Aurora DSQL does not support the SERIAL types because they create a sequence",
+		Suggestion: "use a UUID column with DEFAULT gen_random_uuid() instead",
+		Match: func(stmt *Statement) (int, bool) {
+			for _, column := range stmt.Columns() {
+				// The SERIAL types are not qualified with a schema
+				if names := column.GetTypeName().GetNames(); len(names) == 1 && slices.Contains(serials, names[0].GetString_().GetSval()) {
+					return int(column.GetTypeName().GetLocation()), true
+				}
+			}
+
+			return -1, false
+		},
+	},
+	{
+		Name:       "trigger",
+		Severity:   SeverityError,
+		Message:    "Aurora DSQL does not support triggers",
+		Suggestion: "move the logic of the trigger to the application",
+		Match: func(stmt *Statement) (int, bool) {
+			return 0, stmt.Node.GetCreateTrigStmt() != nil || stmt.Node.GetCreateEventTrigStmt() != nil
+		},
+	},
+	{
+		Name:       "truncate",
+		Severity:   SeverityError,
+		Message:    "Aurora DSQL does not support TRUNCATE",
+		Suggestion: "use DELETE FROM instead",
+		Match: func(stmt *Statement) (int, bool) {
+			return 0, stmt.Node.GetTruncateStmt() != nil
+		},
+	},
+	{
+		Name:       "temporary-table",
+		Severity:   SeverityError,
+		Message:    "Aurora DSQL does not support temporary tables",
+		Suggestion: "use a regular table and drop it when it is no longer needed",
+		Match: func(stmt *Statement) (int, bool) {
+			relation := stmt.Node.GetCreateStmt().GetRelation()
+			if relation == nil {
+				relation = stmt.Node.GetCreateTableAsStmt().GetInto().GetRel()
+			}
+
+			if relation.GetRelpersistence() != "t" {
+				return -1, false
+			}
+
+			return stmt.Find(0, "TEMP", "TEMPORARY")
+		},
+	},
+	{
+		Name:       "extension",
+		Severity:   SeverityError,
+		Message:    "Aurora DSQL does not support extensions",
+		Suggestion: "remove the statement and use the built-in functions instead",
+		Match: func(stmt *Statement) (int, bool) {
+			return 0, stmt.Node.GetCreateExtensionStmt() != nil
+		},
+	},
+}
+
+// serials are the names of the SERIAL types.
+var serials = []string{"smallserial", "serial", "bigserial", "serial2", "serial4", "serial8"}
+
+// transactions reports the explicit transactions that Aurora DSQL rejects:
+// a transaction runs at most one DDL statement and cannot mix DDL and DML
+// statements. A DO block runs its statements in one transaction.
+func transactions(file *File) []*Finding {
+	var (
+		collection []*Finding
+		current    *transaction
+	)
+
+	for _, stmt := range file.statements() {
+		switch kind := stmt.Node.GetTransactionStmt().GetKind(); {
+		case kind == pg.TransactionStmtKind_TRANS_STMT_BEGIN, kind == pg.TransactionStmtKind_TRANS_STMT_START:
+			current = &transaction{file: file, suggestion: "remove BEGIN and COMMIT, aurora executes each statement in its own transaction"}
+		case kind == pg.TransactionStmtKind_TRANS_STMT_COMMIT, kind == pg.TransactionStmtKind_TRANS_STMT_ROLLBACK:
+			current = nil
+		case current != nil:
+			collection = append(collection, current.add(stmt)...)
+		case stmt.Node.GetDoStmt() != nil:
+			block := &transaction{file: file, suggestion: "move the statements out of the DO block, aurora executes each statement in its own transaction"}
+			for _, child := range stmt.Children {
+				collection = append(collection, block.add(child)...)
+			}
+		}
+	}
+
+	return collection
+}
+
+// transaction counts the statements of a transaction.
+type transaction struct {
+	file       *File
+	suggestion string
+	ddl, dml   int
+}
+
+// add counts the statement, or the statements of its DO block, and reports
+// the statement when the transaction cannot run it.
+func (x *transaction) add(stmt *Statement) []*Finding {
+	var collection []*Finding
+
+	switch {
+	case len(stmt.Children) > 0:
+		for _, child := range stmt.Children {
+			collection = append(collection, x.add(child)...)
+		}
+	case stmt.IsDDL():
+		if x.ddl > 0 || x.dml > 0 {
+			collection = append(collection, x.finding(stmt, "Aurora DSQL does not support more than one DDL statement, or DDL and DML statements, in a transaction"))
+		}
+		x.ddl++
+	case stmt.IsDML():
+		if x.ddl > 0 {
+			collection = append(collection, x.finding(stmt, "Aurora DSQL does not support DDL and DML statements in the same transaction"))
+		}
+		x.dml++
+	}
+
+	return collection
+}
+
+// finding returns the finding of a statement that the transaction cannot run.
+func (x *transaction) finding(stmt *Statement, message string) *Finding {
+	return &Finding{
+		File:       x.file.Name,
+		Pos:        stmt.Pos,
+		Rule:       "transaction",
+		Severity:   SeverityError,
+		Message:    message,
+		Suggestion: x.suggestion,
+	}
+}
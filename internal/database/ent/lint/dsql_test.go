@@ -0,0 +1,94 @@
+package lint_test
+
+import (
+	"github.com/aws-contrib/aurora/internal/database/ent/lint"
+	"github.com/aws-contrib/aurora/internal/database/ent/parser"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DSQL", func() {
+	analyze := func(text string) []*lint.Finding {
+		statements, err := parser.Split(text)
+		Expect(err).NotTo(HaveOccurred())
+
+		file := &lint.File{Name: "20250101000000_schema.sql", Statements: statements}
+		return lint.Lint(file, lint.DSQL)
+	}
+
+	rules := func(findings []*lint.Finding) []string {
+		var collection []string
+		for _, finding := range findings {
+			collection = append(collection, finding.Rule)
+		}
+		return collection
+	}
+
+	It("accepts the supported statements", func() {
+		findings := analyze("CREATE TABLE IF NOT EXISTS users (id UUID PRIMARY KEY DEFAULT gen_random_uuid(), serial_number TEXT);\n" +
+			"CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_users_serial ON users (serial_number);\n" +
+			"INSERT INTO users (serial_number) VALUES ('REFERENCES nextval(x)') ON CONFLICT DO NOTHING;\n" +
+			"DROP TABLE IF EXISTS \"serial\";\n")
+		Expect(findings).To(BeEmpty())
+	})
+
+	DescribeTable("reports the unsupported statements",
+		func(text, rule string, line, column int) {
+			findings := analyze(text)
+			Expect(rules(findings)).To(Equal([]string{rule}))
+			Expect(findings[0].File).To(Equal("20250101000000_schema.sql"))
+			Expect(findings[0].Pos.Line).To(Equal(line))
+			Expect(findings[0].Pos.Column).To(Equal(column))
+			Expect(findings[0].Severity).To(Equal(lint.SeverityError))
+			Expect(findings[0].Suggestion).NotTo(BeEmpty())
+		},
+		Entry("foreign key", "CREATE TABLE orders (\n  id UUID PRIMARY KEY,\n  user_id UUID REFERENCES users (id)\n);", "foreign-key", 3, 16),
+		Entry("foreign key constraint", "ALTER TABLE orders ADD CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES users (id);", "foreign-key", 1, 43),
+		Entry("sequence", "CREATE SEQUENCE IF NOT EXISTS orders_seq;", "sequence", 1, 1),
+		Entry("nextval", "INSERT INTO orders (id) VALUES (nextval('orders_seq'));", "sequence", 1, 33),
+		Entry("serial", "CREATE TABLE orders (id SERIAL PRIMARY KEY);", "serial", 1, 25),
+		Entry("bigserial column", "ALTER TABLE orders ADD COLUMN number bigserial;", "serial", 1, 38),
+		Entry("trigger", "CREATE OR REPLACE TRIGGER audit AFTER INSERT ON orders FOR EACH ROW EXECUTE FUNCTION audit();", "trigger", 1, 1),
+		Entry("truncate", "TRUNCATE orders;", "truncate", 1, 1),
+		Entry("temporary table", "CREATE TEMPORARY TABLE staging (id UUID);", "temporary-table", 1, 8),
+		Entry("extension", "CREATE EXTENSION IF NOT EXISTS pgcrypto;", "extension", 1, 1),
+	)
+
+	It("ignores the comments", func() {
+		Expect(analyze("-- TRUNCATE orders;\nCREATE TABLE orders (id UUID /* REFERENCES users */);")).To(BeEmpty())
+	})
+
+	It("checks the statements of a DO block", func() {
+		findings := analyze("DO $$\nBEGIN\n  EXECUTE 'CREATE SEQUENCE orders_seq';\n  PERFORM nextval('orders_seq');\nEND $$;")
+		Expect(rules(findings)).To(Equal([]string{"sequence", "sequence"}))
+		Expect(findings[0].Pos.Line).To(Equal(3))
+		Expect(findings[0].Pos.Column).To(Equal(12))
+		Expect(findings[1].Pos.Line).To(Equal(4))
+		Expect(findings[1].Pos.Column).To(Equal(11))
+	})
+
+	Describe("transactions", func() {
+		It("reports more than one DDL statement in a transaction", func() {
+			findings := analyze("BEGIN;\nCREATE TABLE a (id UUID);\nCREATE TABLE b (id UUID);\nCOMMIT;")
+			Expect(rules(findings)).To(Equal([]string{"transaction"}))
+			Expect(findings[0].Pos.Line).To(Equal(3))
+		})
+
+		It("reports DDL and DML statements in a transaction", func() {
+			findings := analyze("START TRANSACTION;\nCREATE TABLE a (id UUID);\nINSERT INTO a VALUES (gen_random_uuid());\nCOMMIT;")
+			Expect(rules(findings)).To(Equal([]string{"transaction"}))
+			Expect(findings[0].Pos.Line).To(Equal(3))
+		})
+
+		It("reports more than one DDL statement in a DO block", func() {
+			findings := analyze("DO $$\nBEGIN\n  CREATE TABLE IF NOT EXISTS a (id UUID);\n  CREATE INDEX IF NOT EXISTS idx_a ON a (id);\nEND $$;")
+			Expect(rules(findings)).To(Equal([]string{"transaction"}))
+			Expect(findings[0].Pos.Line).To(Equal(4))
+		})
+
+		It("accepts a single DDL statement in a transaction", func() {
+			Expect(analyze("BEGIN;\nCREATE TABLE a (id UUID);\nCOMMIT;\nCREATE TABLE b (id UUID);")).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,108 @@
+package lint
+
+import (
+	"strings"
+
+	pg "github.com/pganalyze/pg_query_go/v6"
+)
+
+// Idempotency reports the statements that fail or change the data again
+// when they are executed twice. A migration is executed again from the
+// failed statement, so each statement must be safe to re-run.
+var Idempotency Analyzer = Catalogue{
+	{
+		Name:       "create-if-not-exists",
+		Severity:   SeverityError,
+		Message:    "CREATE without IF NOT EXISTS fails when the statement is executed again",
+		Suggestion: "add IF NOT EXISTS after the object type",
+		Match: func(stmt *Statement) (int, bool) {
+			switch node := stmt.Node; {
+			case node.GetCreateStmt() != nil:
+				return 0, !node.GetCreateStmt().GetIfNotExists()
+			case node.GetIndexStmt() != nil:
+				return 0, !node.GetIndexStmt().GetIfNotExists()
+			case node.GetCreateSchemaStmt() != nil:
+				return 0, !node.GetCreateSchemaStmt().GetIfNotExists()
+			case node.GetCreateTableAsStmt().GetObjtype() == pg.ObjectType_OBJECT_TABLE:
+				return 0, !node.GetCreateTableAsStmt().GetIfNotExists()
+			default:
+				return -1, false
+			}
+		},
+	},
+	{
+		Name:       "create-or-replace",
+		Severity:   SeverityError,
+		Message:    "CREATE VIEW fails when the statement is executed again",
+		Suggestion: "use CREATE OR REPLACE VIEW, or CREATE MATERIALIZED VIEW IF NOT EXISTS",
+		Match: func(stmt *Statement) (int, bool) {
+			switch node := stmt.Node; {
+			case node.GetViewStmt() != nil:
+				return 0, !node.GetViewStmt().GetReplace()
+			case node.GetCreateTableAsStmt().GetObjtype() == pg.ObjectType_OBJECT_MATVIEW:
+				return 0, !node.GetCreateTableAsStmt().GetIfNotExists()
+			default:
+				return -1, false
+			}
+		},
+	},
+	{
+		Name:       "drop-if-exists",
+		Severity:   SeverityError,
+		Message:    "DROP without IF EXISTS fails when the statement is executed again",
+		Suggestion: "add IF EXISTS after the object type",
+		Match: func(stmt *Statement) (int, bool) {
+			if !strings.HasPrefix(stmt.Kind(), "Drop") {
+				return -1, false
+			}
+
+			// The DROP statements with an IF EXISTS option have a missing_ok field
+			msg := stmt.message()
+			if field := msg.Descriptor().Fields().ByName("missing_ok"); field != nil {
+				return 0, !msg.Get(field).Bool()
+			}
+
+			return -1, false
+		},
+	},
+	{
+		Name:       "add-column-if-not-exists",
+		Severity:   SeverityError,
+		Message:    "ADD COLUMN without IF NOT EXISTS fails when the statement is executed again",
+		Suggestion: "use ADD COLUMN IF NOT EXISTS",
+		Match: func(stmt *Statement) (int, bool) {
+			for index, cmd := range stmt.Commands() {
+				if cmd.GetSubtype() == pg.AlterTableType_AT_AddColumn && !cmd.GetMissingOk() {
+					return stmt.command(index), true
+				}
+			}
+
+			return -1, false
+		},
+	},
+	{
+		Name:       "drop-column-if-exists",
+		Severity:   SeverityError,
+		Message:    "DROP COLUMN without IF EXISTS fails when the statement is executed again",
+		Suggestion: "use DROP COLUMN IF EXISTS",
+		Match: func(stmt *Statement) (int, bool) {
+			for index, cmd := range stmt.Commands() {
+				if cmd.GetSubtype() == pg.AlterTableType_AT_DropColumn && !cmd.GetMissingOk() {
+					return stmt.command(index), true
+				}
+			}
+
+			return -1, false
+		},
+	},
+	{
+		Name:       "insert-on-conflict",
+		Severity:   SeverityWarning,
+		Message:    "INSERT without ON CONFLICT inserts the rows again, or fails, when the statement is executed again",
+		Suggestion: "add ON CONFLICT DO NOTHING, or ON CONFLICT (...) DO UPDATE",
+		Match: func(stmt *Statement) (int, bool) {
+			insert := stmt.Node.GetInsertStmt()
+			return 0, insert != nil && insert.GetOnConflictClause() == nil
+		},
+	},
+}
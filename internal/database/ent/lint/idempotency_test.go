@@ -0,0 +1,64 @@
+package lint_test
+
+import (
+	"github.com/aws-contrib/aurora/internal/database/ent/lint"
+	"github.com/aws-contrib/aurora/internal/database/ent/parser"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Idempotency", func() {
+	analyze := func(text string) []*lint.Finding {
+		statements, err := parser.Split(text)
+		Expect(err).NotTo(HaveOccurred())
+
+		file := &lint.File{Name: "20250101000000_schema.sql", Statements: statements}
+		return lint.Lint(file, lint.Idempotency)
+	}
+
+	rules := func(findings []*lint.Finding) []string {
+		var collection []string
+		for _, finding := range findings {
+			collection = append(collection, finding.Rule)
+		}
+		return collection
+	}
+
+	It("accepts the idempotent statements", func() {
+		findings := analyze("CREATE SCHEMA IF NOT EXISTS app;\n" +
+			"CREATE TABLE IF NOT EXISTS app.users (id UUID PRIMARY KEY, name TEXT);\n" +
+			"CREATE UNIQUE INDEX CONCURRENTLY IF NOT EXISTS idx_users_name ON app.users (name);\n" +
+			"CREATE OR REPLACE VIEW app.names AS SELECT name FROM app.users;\n" +
+			"CREATE MATERIALIZED VIEW IF NOT EXISTS app.total AS SELECT count(*) FROM app.users;\n" +
+			"ALTER TABLE app.users ADD COLUMN IF NOT EXISTS email TEXT, DROP COLUMN IF EXISTS name;\n" +
+			"ALTER TABLE ONLY app.users ADD CONSTRAINT uq_email UNIQUE (email), ALTER COLUMN email DROP NOT NULL;\n" +
+			"INSERT INTO app.users (id) VALUES (gen_random_uuid()) ON CONFLICT DO NOTHING;\n" +
+			"DROP INDEX CONCURRENTLY IF EXISTS idx_users_name;\n" +
+			"DROP TABLE IF EXISTS app.users;\n")
+		Expect(findings).To(BeEmpty())
+	})
+
+	DescribeTable("reports the statements that are not idempotent",
+		func(text, rule string, severity lint.Severity, column int) {
+			findings := analyze(text)
+			Expect(rules(findings)).To(Equal([]string{rule}))
+			Expect(findings[0].Severity).To(Equal(severity))
+			Expect(findings[0].Pos.Column).To(Equal(column))
+			Expect(findings[0].Suggestion).NotTo(BeEmpty())
+		},
+		Entry("create table", "CREATE TABLE users (id UUID);", "create-if-not-exists", lint.SeverityError, 1),
+		Entry("create index", "CREATE INDEX ASYNC idx_users_name ON users (name);", "create-if-not-exists", lint.SeverityError, 1),
+		Entry("create schema", "CREATE SCHEMA app;", "create-if-not-exists", lint.SeverityError, 1),
+		Entry("create view", "CREATE VIEW names AS SELECT name FROM users;", "create-or-replace", lint.SeverityError, 1),
+		Entry("drop table", "DROP TABLE users;", "drop-if-exists", lint.SeverityError, 1),
+		Entry("drop index", "DROP INDEX CONCURRENTLY idx_users_name;", "drop-if-exists", lint.SeverityError, 1),
+		Entry("add column", "ALTER TABLE users ADD COLUMN IF NOT EXISTS email TEXT, ADD name TEXT;", "add-column-if-not-exists", lint.SeverityError, 56),
+		Entry("drop column", "ALTER TABLE app.users DROP COLUMN name;", "drop-column-if-exists", lint.SeverityError, 23),
+		Entry("insert", "INSERT INTO users (id) VALUES (gen_random_uuid());", "insert-on-conflict", lint.SeverityWarning, 1),
+	)
+
+	It("ignores the columns of a nested definition", func() {
+		Expect(analyze("ALTER TABLE users ADD COLUMN IF NOT EXISTS total INT GENERATED ALWAYS AS (a + b) STORED;")).To(BeEmpty())
+	})
+})
@@ -0,0 +1,212 @@
+// Package lint provides analyzers that check migration files before they are applied.
+//
+// The analyzers check the PostgreSQL parse tree of each statement, including
+// the statements that a DO block executes. A statement that cannot be parsed,
+// or that an EXECUTE builds at runtime, is reported by Syntax.
+package lint
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/aws-contrib/aurora/internal/database/ent/parser"
+)
+
+// Severity represents the severity of a finding.
+type Severity string
+
+const (
+	// SeverityError is a finding that makes the migration fail.
+	SeverityError Severity = "error"
+	// SeverityWarning is a finding that should be reviewed.
+	SeverityWarning Severity = "warning"
+)
+
+// Finding represents a problem found in a statement of a migration file.
+type Finding struct {
+	// File is the name of the migration file.
+	File string `json:"file"`
+	// Pos is the position of the problem in the migration file.
+	Pos parser.Position `json:"position"`
+	// Rule is the name of the rule that reported the problem.
+	Rule string `json:"rule"`
+	// Severity is the severity of the problem.
+	Severity Severity `json:"severity"`
+	// Message describes the problem.
+	Message string `json:"message"`
+	// Suggestion describes how to fix the problem.
+	Suggestion string `json:"suggestion"`
+}
+
+// String returns the finding in the file:line:column format.
+func (x *Finding) String() string {
+	return fmt.Sprintf("%s:%s: %s: %s (%s)", x.File, x.Pos, x.Severity, x.Message, x.Rule)
+}
+
+// File represents a migration file to analyze.
+type File struct {
+	// Name is the name of the migration file.
+	Name string
+	// Statements are the statements of the migration file.
+	Statements []*parser.Statement
+	// trees are the parsed statements, in the same order.
+	trees []*Statement
+}
+
+// statements returns the parsed statements of the file. They are parsed
+// once for all the analyzers.
+func (x *File) statements() []*Statement {
+	if len(x.trees) != len(x.Statements) {
+		x.trees = nil
+		for _, stmt := range x.Statements {
+			x.trees = append(x.trees, NewStatement(stmt))
+		}
+	}
+
+	return x.trees
+}
+
+// suppresses reports whether the statement of the finding has a Nolint
+// directive for its rule.
+func (x *File) suppresses(finding *Finding) bool {
+	for _, stmt := range x.Statements {
+		if finding.Pos.Offset < stmt.Pos.Offset || finding.Pos.Offset >= stmt.Pos.Offset+len(stmt.Text) {
+			continue
+		}
+
+		comments := slices.Clone(stmt.Comments)
+		for _, token := range stmt.Tokens {
+			if token.Kind == parser.TokenComment {
+				comments = append(comments, token)
+			}
+		}
+
+		args, ok := parser.Directive(comments, Nolint)
+		if !ok {
+			return false
+		}
+
+		if args == "" {
+			return true
+		}
+
+		for rule := range strings.SplitSeq(args, ",") {
+			if strings.TrimSpace(rule) == finding.Rule {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	return false
+}
+
+// Analyzer represents a set of rules that check a migration file.
+type Analyzer interface {
+	// Analyze returns the problems found in the file.
+	Analyze(file *File) []*Finding
+}
+
+var _ Analyzer = AnalyzerFunc(nil)
+
+// AnalyzerFunc is a function that implements Analyzer.
+type AnalyzerFunc func(file *File) []*Finding
+
+// Analyze runs the function.
+func (fn AnalyzerFunc) Analyze(file *File) []*Finding {
+	return fn(file)
+}
+
+// DefaultAnalyzers are the analyzers run by Lint when none is given.
+var DefaultAnalyzers = []Analyzer{
+	Syntax,
+	DSQL,
+	Idempotency,
+	Destructive,
+}
+
+// Nolint is the directive that suppresses the findings of a statement. It
+// is a line comment before or inside the statement, optionally followed by
+// the comma-separated names of the rules to suppress (e.g.
+// "-- aurora:nolint insert-on-conflict").
+const Nolint = "aurora:nolint"
+
+// Lint runs the analyzers on the file and returns their findings ordered by position.
+func Lint(file *File, analyzers ...Analyzer) []*Finding {
+	if len(analyzers) == 0 {
+		analyzers = DefaultAnalyzers
+	}
+
+	var collection []*Finding
+	for _, analyzer := range analyzers {
+		for _, finding := range analyzer.Analyze(file) {
+			if !file.suppresses(finding) {
+				collection = append(collection, finding)
+			}
+		}
+	}
+
+	slices.SortStableFunc(collection, func(x, y *Finding) int {
+		return cmp.Compare(x.Pos.Offset, y.Pos.Offset)
+	})
+
+	return collection
+}
+
+// HasErrors reports whether one of the findings is an error.
+func HasErrors(findings []*Finding) bool {
+	return slices.ContainsFunc(findings, func(finding *Finding) bool {
+		return finding.Severity == SeverityError
+	})
+}
+
+// Rule represents a check of a single statement.
+type Rule struct {
+	// Name is the name of the rule.
+	Name string
+	// Severity is the severity of the findings of the rule.
+	Severity Severity
+	// Message describes the problem.
+	Message string
+	// Suggestion describes how to fix the problem.
+	Suggestion string
+	// Match returns the offset in the text of the statement where the
+	// problem starts, and false if the statement does not have the problem.
+	Match func(stmt *Statement) (int, bool)
+}
+
+// Catalogue is an analyzer that checks each statement, and the statements
+// of its DO block, against a list of rules. The statements that cannot be
+// parsed are skipped.
+type Catalogue []*Rule
+
+// Analyze implements Analyzer.
+func (x Catalogue) Analyze(file *File) []*Finding {
+	var collection []*Finding
+
+	for _, stmt := range file.statements() {
+		stmt.Walk(func(stmt *Statement) {
+			if stmt.Node == nil {
+				return
+			}
+
+			for _, rule := range x {
+				if offset, ok := rule.Match(stmt); ok {
+					collection = append(collection, &Finding{
+						File:       file.Name,
+						Pos:        stmt.Position(offset),
+						Rule:       rule.Name,
+						Severity:   rule.Severity,
+						Message:    rule.Message,
+						Suggestion: rule.Suggestion,
+					})
+				}
+			}
+		})
+	}
+
+	return collection
+}
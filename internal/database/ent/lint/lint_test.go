@@ -0,0 +1,146 @@
+package lint_test
+
+import (
+	"github.com/aws-contrib/aurora/internal/database/ent/lint"
+	"github.com/aws-contrib/aurora/internal/database/ent/parser"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Lint", func() {
+	var file *lint.File
+
+	BeforeEach(func() {
+		statements, err := parser.Split("TRUNCATE a;\nCREATE EXTENSION b;")
+		Expect(err).NotTo(HaveOccurred())
+
+		file = &lint.File{Name: "20250101000000_schema.sql", Statements: statements}
+	})
+
+	It("runs the default analyzers", func() {
+		findings := lint.Lint(file)
+		Expect(findings).To(HaveLen(3))
+		Expect(findings[0].String()).To(Equal("20250101000000_schema.sql:1:1: error: Aurora DSQL does not support TRUNCATE (truncate)"))
+		Expect(lint.HasErrors(findings)).To(BeTrue())
+	})
+
+	It("orders the findings by position", func() {
+		analyzer := lint.AnalyzerFunc(func(file *lint.File) []*lint.Finding {
+			return []*lint.Finding{
+				{Pos: file.Statements[1].Pos, Severity: lint.SeverityWarning},
+				{Pos: file.Statements[0].Pos, Severity: lint.SeverityWarning},
+			}
+		})
+
+		findings := lint.Lint(file, analyzer)
+		Expect(findings[0].Pos.Line).To(Equal(1))
+		Expect(findings[1].Pos.Line).To(Equal(2))
+		Expect(lint.HasErrors(findings)).To(BeFalse())
+	})
+
+	Describe("Nolint", func() {
+		analyze := func(text string) []*lint.Finding {
+			statements, err := parser.Split(text)
+			Expect(err).NotTo(HaveOccurred())
+
+			return lint.Lint(&lint.File{Name: "20250101000000_schema.sql", Statements: statements})
+		}
+
+		It("suppresses the findings of the next statement", func() {
+			findings := analyze("-- aurora:nolint\nTRUNCATE a;\nTRUNCATE b;")
+			Expect(findings).To(HaveLen(2))
+			Expect(findings[0].Pos.Line).To(Equal(3))
+			Expect(findings[1].Pos.Line).To(Equal(3))
+		})
+
+		It("suppresses the findings of the statement with an inline directive", func() {
+			Expect(analyze("INSERT INTO a (id) -- aurora:nolint\nVALUES (1);")).To(BeEmpty())
+		})
+
+		It("suppresses the findings of the given rules", func() {
+			findings := analyze("-- aurora:nolint insert-on-conflict, sequence\nINSERT INTO a (id) VALUES (nextval('a'));\n" +
+				"-- aurora:nolint sequence\nINSERT INTO a (id) VALUES (1);")
+			Expect(findings).To(HaveLen(1))
+			Expect(findings[0].Rule).To(Equal("insert-on-conflict"))
+			Expect(findings[0].Pos.Line).To(Equal(4))
+		})
+	})
+})
+
+var _ = Describe("Statement", func() {
+	parse := func(text string) *lint.Statement {
+		statements, err := parser.Split(text)
+		Expect(err).NotTo(HaveOccurred())
+		return lint.NewStatement(statements[0])
+	}
+
+	It("parses the statement", func() {
+		stmt := parse("CREATE /* x */ TABLE -- y\n a (id INT);")
+		Expect(stmt.Err).NotTo(HaveOccurred())
+		Expect(stmt.Kind()).To(Equal("CreateStmt"))
+		Expect(stmt.IsDDL()).To(BeTrue())
+		Expect(stmt.Tokens[len(stmt.Tokens)-1].Text).To(Equal(")"))
+	})
+
+	It("parses the asynchronous index of Aurora DSQL", func() {
+		stmt := parse("CREATE UNIQUE INDEX ASYNC IF NOT EXISTS a ON b (c);")
+		Expect(stmt.Err).NotTo(HaveOccurred())
+		Expect(stmt.Node.GetIndexStmt().GetIfNotExists()).To(BeTrue())
+	})
+
+	It("reports the syntax error", func() {
+		stmt := parse("CREATE TABEL a (id INT);")
+		Expect(stmt.Err).To(MatchError(`syntax error at or near "TABEL"`))
+		Expect(stmt.Node).To(BeNil())
+	})
+
+	DescribeTable("IsDML",
+		func(text string, dml bool) {
+			Expect(parse(text).IsDML()).To(Equal(dml))
+		},
+		Entry("insert", "INSERT INTO a VALUES (1);", true),
+		Entry("data-modifying common table expression", "WITH x AS (DELETE FROM a RETURNING id) SELECT * FROM x;", true),
+		Entry("select", "SELECT * FROM a;", false),
+		Entry("create table", "CREATE TABLE a (id INT);", false),
+	)
+
+	It("returns the tokens of the ALTER TABLE commands", func() {
+		stmt := parse("ALTER TABLE ONLY app.users ADD COLUMN a NUMERIC(10, 2), DROP b;")
+		Expect(stmt.Commands()).To(HaveLen(2))
+		Expect(stmt.Command(0)).To(HaveLen(9))
+		Expect(stmt.Command(1)[0].Pos.Column).To(Equal(57))
+		Expect(stmt.Command(2)).To(BeNil())
+	})
+
+	It("finds a function call", func() {
+		offset, ok := parse("SELECT a, nextval('a');").Call("nextval")
+		Expect(ok).To(BeTrue())
+		Expect(offset).To(Equal(10))
+
+		_, ok = parse("SELECT nextval FROM a;").Call("nextval")
+		Expect(ok).To(BeFalse())
+	})
+
+	Describe("DO block", func() {
+		It("parses the statements of the block", func() {
+			stmt := parse("DO $$\nBEGIN\n  CREATE TABLE a (id INT);\n  EXECUTE 'DROP TABLE b';\n  EXECUTE format('DROP TABLE %I', 'c');\nEND $$;")
+			Expect(stmt.Children).To(HaveLen(3))
+
+			Expect(stmt.Children[0].Kind()).To(Equal("CreateStmt"))
+			Expect(stmt.Children[0].Pos).To(Equal(parser.Position{Offset: 14, Line: 3, Column: 3}))
+
+			Expect(stmt.Children[1].Kind()).To(Equal("DropStmt"))
+			Expect(stmt.Children[1].Pos).To(Equal(parser.Position{Offset: 50, Line: 4, Column: 12}))
+
+			Expect(stmt.Children[2].Dynamic).To(BeTrue())
+			Expect(stmt.Children[2].Pos.Line).To(Equal(5))
+		})
+
+		It("does not parse the other languages", func() {
+			stmt := parse("DO LANGUAGE plpython3u $$ plpy.execute('DROP TABLE a') $$;")
+			Expect(stmt.Children).To(HaveLen(1))
+			Expect(stmt.Children[0].Dynamic).To(BeTrue())
+		})
+	})
+})
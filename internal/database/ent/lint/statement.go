@@ -0,0 +1,592 @@
+package lint
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"maps"
+	"slices"
+	"strings"
+
+	pg "github.com/pganalyze/pg_query_go/v6"
+	pgquery "github.com/wasilibs/go-pgquery"
+	pgparser "github.com/wasilibs/go-pgquery/parser"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/aws-contrib/aurora/internal/database/ent/parser"
+)
+
+// Statement represents a statement of a migration file and its PostgreSQL
+// parse tree. The tree is built by libpg_query, compiled to WebAssembly so
+// that the binary does not require cgo.
+type Statement struct {
+	// Text is the text of the statement.
+	Text string
+	// Pos is the position where the statement starts in the file.
+	Pos parser.Position
+	// Node is the parse tree of the statement, or nil when the statement
+	// cannot be parsed.
+	Node *pg.Node
+	// Err is the syntax error of the statement.
+	Err error
+	// Dynamic reports whether the statement is built at runtime by an
+	// EXECUTE of a DO block. The text is the expression that builds it.
+	Dynamic bool
+	// Children are the statements executed by the DO block of the statement.
+	Children []*Statement
+	// Tokens are the tokens of the statement without comments and
+	// semicolon. They locate the keywords that the tree has no position for.
+	Tokens []*parser.Token
+	// cursor is the offset of the syntax error in the text.
+	cursor int
+}
+
+// NewStatement parses the statement of a migration file.
+func NewStatement(stmt *parser.Statement) *Statement {
+	return parse(stmt.Text, stmt.Pos)
+}
+
+// parse parses the text of a statement that starts at the given position.
+func parse(text string, pos parser.Position) *Statement {
+	x := &Statement{Text: text, Pos: pos}
+
+	tokens, err := parser.Tokenize(text)
+	if err != nil {
+		x.Err = err
+		return x
+	}
+
+	for _, token := range tokens {
+		if token.Kind != parser.TokenComment && token.Kind != parser.TokenSemicolon {
+			x.Tokens = append(x.Tokens, token)
+		}
+	}
+
+	tree, err := pgquery.Parse(x.source())
+	if err != nil {
+		x.Err = err
+
+		var perr *pgparser.Error
+		if errors.As(err, &perr) && perr.Cursorpos > 0 {
+			x.cursor = offset(text, perr.Cursorpos-1)
+		}
+
+		return x
+	}
+
+	if stmts := tree.GetStmts(); len(stmts) > 0 {
+		x.Node = stmts[0].GetStmt()
+	}
+
+	if do := x.Node.GetDoStmt(); do != nil {
+		x.Children = x.block(do)
+	}
+
+	return x
+}
+
+// source returns the text given to the parser. The ASYNC option of
+// CREATE INDEX is specific to Aurora DSQL, and is replaced by spaces to keep
+// the locations of the tree.
+func (x *Statement) source() string {
+	index := 1
+	if x.match(index, "UNIQUE") {
+		index++
+	}
+
+	if !x.match(0, "CREATE") || !x.match(index, "INDEX", "ASYNC") {
+		return x.Text
+	}
+
+	token := x.Tokens[index+1]
+	return x.Text[:token.Pos.Offset] + strings.Repeat(" ", len(token.Text)) + x.Text[token.Pos.Offset+len(token.Text):]
+}
+
+// block returns the statements executed by a DO block. The body is parsed
+// as the body of a PL/pgSQL function. The statements of the other
+// languages cannot be analyzed, and are returned as one dynamic statement.
+func (x *Statement) block(do *pg.DoStmt) []*Statement {
+	var body, language string
+
+	for _, arg := range do.GetArgs() {
+		switch elem := arg.GetDefElem(); elem.GetDefname() {
+		case "as":
+			body = elem.GetArg().GetString_().GetSval()
+		case "language":
+			language = elem.GetArg().GetString_().GetSval()
+		}
+	}
+
+	// The body is the only string of the statement
+	start := 0
+	for _, token := range x.Tokens {
+		if token.Kind == parser.TokenString {
+			start = token.Pos.Offset
+			if index := strings.Index(token.Text, body); index >= 0 {
+				start += index
+			}
+		}
+	}
+
+	pos := x.Position(start)
+	if language != "" && !strings.EqualFold(language, "plpgsql") {
+		return []*Statement{{Text: body, Pos: pos, Dynamic: true}}
+	}
+
+	tag := "$aurora$"
+	for strings.Contains(body, tag) {
+		tag = "$aurora" + tag[1:]
+	}
+
+	text, err := pgquery.ParsePlPgSqlToJSON("CREATE FUNCTION aurora() RETURNS void LANGUAGE plpgsql AS " + tag + body + tag)
+	if err != nil {
+		return []*Statement{{Text: body, Pos: pos, Err: err}}
+	}
+
+	var tree any
+	if err := json.Unmarshal([]byte(text), &tree); err != nil {
+		return []*Statement{{Text: body, Pos: pos, Err: err}}
+	}
+
+	var collection []*Statement
+	for _, query := range queries(tree, 1) {
+		collection = append(collection, query.statement(body, pos))
+	}
+
+	slices.SortStableFunc(collection, func(a, b *Statement) int {
+		return cmp.Compare(a.Pos.Offset, b.Pos.Offset)
+	})
+
+	return collection
+}
+
+// Walk calls the function for the statement and the statements of its DO block.
+func (x *Statement) Walk(fn func(stmt *Statement)) {
+	fn(x)
+
+	for _, child := range x.Children {
+		child.Walk(fn)
+	}
+}
+
+// Kind returns the name of the node of the statement (e.g. CreateStmt), or
+// an empty string when the statement cannot be parsed.
+func (x *Statement) Kind() string {
+	if msg := x.message(); msg != nil {
+		return string(msg.Descriptor().Name())
+	}
+
+	return ""
+}
+
+// message returns the node of the statement, or nil when the statement
+// cannot be parsed.
+func (x *Statement) message() protoreflect.Message {
+	if x.Node == nil {
+		return nil
+	}
+
+	msg := x.Node.ProtoReflect()
+	if field := msg.WhichOneof(nodes); field != nil {
+		return msg.Get(field).Message()
+	}
+
+	return nil
+}
+
+// nodes is the descriptor of the oneof field of pg.Node.
+var nodes = (&pg.Node{}).ProtoReflect().Descriptor().Oneofs().ByName("node")
+
+// IsDDL reports whether the statement changes the schema.
+func (x *Statement) IsDDL() bool {
+	switch kind := x.Kind(); kind {
+	case "IndexStmt", "ViewStmt", "DefineStmt", "CommentStmt", "GrantStmt", "GrantRoleStmt",
+		"TruncateStmt", "RenameStmt", "CompositeTypeStmt", "RuleStmt", "SecLabelStmt":
+		return true
+	default:
+		return strings.HasPrefix(kind, "Create") || strings.HasPrefix(kind, "Alter") || strings.HasPrefix(kind, "Drop")
+	}
+}
+
+// IsDML reports whether the statement changes the data.
+func (x *Statement) IsDML() bool {
+	switch x.Kind() {
+	case "InsertStmt", "UpdateStmt", "DeleteStmt", "MergeStmt", "CopyStmt":
+		return true
+	case "SelectStmt":
+		// A common table expression can change the data
+		for _, cte := range x.Node.GetSelectStmt().GetWithClause().GetCtes() {
+			switch query := cte.GetCommonTableExpr().GetCtequery(); {
+			case query.GetInsertStmt() != nil, query.GetUpdateStmt() != nil, query.GetDeleteStmt() != nil, query.GetMergeStmt() != nil:
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Commands returns the commands of an ALTER TABLE statement.
+func (x *Statement) Commands() []*pg.AlterTableCmd {
+	var collection []*pg.AlterTableCmd
+
+	for _, cmd := range x.Node.GetAlterTableStmt().GetCmds() {
+		collection = append(collection, cmd.GetAlterTableCmd())
+	}
+
+	return collection
+}
+
+// command returns the offset of the command at the given index of an ALTER
+// TABLE statement.
+func (x *Statement) command(index int) int {
+	if tokens := x.Command(index); len(tokens) > 0 {
+		return tokens[0].Pos.Offset
+	}
+
+	return 0
+}
+
+// Columns returns the columns defined by a CREATE TABLE statement, or added
+// by an ALTER TABLE statement.
+func (x *Statement) Columns() []*pg.ColumnDef {
+	var collection []*pg.ColumnDef
+
+	for _, elt := range x.Node.GetCreateStmt().GetTableElts() {
+		if column := elt.GetColumnDef(); column != nil {
+			collection = append(collection, column)
+		}
+	}
+
+	for _, cmd := range x.Commands() {
+		if column := cmd.GetDef().GetColumnDef(); column != nil && cmd.GetSubtype() == pg.AlterTableType_AT_AddColumn {
+			collection = append(collection, column)
+		}
+	}
+
+	return collection
+}
+
+// Constraints returns the constraints of the columns and of the table
+// defined by a CREATE TABLE statement, or added by an ALTER TABLE statement.
+func (x *Statement) Constraints() []*pg.Constraint {
+	var collection []*pg.Constraint
+
+	for _, column := range x.Columns() {
+		for _, constraint := range column.GetConstraints() {
+			collection = append(collection, constraint.GetConstraint())
+		}
+	}
+
+	for _, elt := range x.Node.GetCreateStmt().GetTableElts() {
+		if constraint := elt.GetConstraint(); constraint != nil {
+			collection = append(collection, constraint)
+		}
+	}
+
+	for _, cmd := range x.Commands() {
+		if constraint := cmd.GetDef().GetConstraint(); constraint != nil && cmd.GetSubtype() == pg.AlterTableType_AT_AddConstraint {
+			collection = append(collection, constraint)
+		}
+	}
+
+	slices.SortFunc(collection, func(a, b *pg.Constraint) int {
+		return cmp.Compare(a.GetLocation(), b.GetLocation())
+	})
+
+	return collection
+}
+
+// Command returns the tokens of the command at the given index of an ALTER
+// TABLE statement. The commands follow the name of the table and are
+// separated by the commas outside of the parentheses.
+func (x *Statement) Command(index int) []*parser.Token {
+	relation := x.Node.GetAlterTableStmt().GetRelation()
+	if relation == nil {
+		return nil
+	}
+
+	start := slices.IndexFunc(x.Tokens, func(token *parser.Token) bool {
+		return token.Pos.Offset == int(relation.GetLocation())
+	})
+	if start < 0 {
+		return nil
+	}
+
+	// The name can be qualified with the schema
+	for start++; x.match(start, "."); {
+		start += 2
+	}
+
+	var (
+		collection [][]*parser.Token
+		command    []*parser.Token
+		depth      int
+	)
+
+	for _, token := range x.Tokens[start:] {
+		switch {
+		case token.Text == "(":
+			depth++
+		case token.Text == ")":
+			depth--
+		case token.Text == "," && depth == 0:
+			collection = append(collection, command)
+			command = nil
+			continue
+		}
+
+		command = append(command, token)
+	}
+
+	collection = append(collection, command)
+	if index < 0 || index >= len(collection) {
+		return nil
+	}
+
+	return collection[index]
+}
+
+// Call returns the location of the first call of the given function.
+func (x *Statement) Call(name string) (int, bool) {
+	location := -1
+
+	walk(x.Node.ProtoReflect(), func(msg protoreflect.Message) bool {
+		call, ok := msg.Interface().(*pg.FuncCall)
+		if !ok {
+			return location < 0
+		}
+
+		if names := call.GetFuncname(); len(names) > 0 && strings.EqualFold(names[len(names)-1].GetString_().GetSval(), name) {
+			location = int(call.GetLocation())
+		}
+
+		return location < 0
+	})
+
+	return location, location >= 0
+}
+
+// Find returns the offset of the first of the given keywords that starts at
+// or after the offset.
+func (x *Statement) Find(offset int, keywords ...string) (int, bool) {
+	for _, token := range x.Tokens {
+		if token.Pos.Offset < offset {
+			continue
+		}
+
+		for _, keyword := range keywords {
+			if token.Is(keyword) {
+				return token.Pos.Offset, true
+			}
+		}
+	}
+
+	return -1, false
+}
+
+// Position returns the position in the file of the offset in the text of
+// the statement. A negative offset is the start of the statement.
+func (x *Statement) Position(offset int) parser.Position {
+	pos := x.Pos
+	offset = min(max(offset, 0), len(x.Text))
+
+	for _, ch := range x.Text[:offset] {
+		if ch == '\n' {
+			pos.Line++
+			pos.Column = 1
+		} else {
+			pos.Column++
+		}
+	}
+
+	pos.Offset += offset
+	return pos
+}
+
+// match reports whether the keywords, or symbols, occur at the given index.
+func (x *Statement) match(index int, keywords ...string) bool {
+	if len(keywords) == 0 || index < 0 || index+len(keywords) > len(x.Tokens) {
+		return false
+	}
+
+	for offset, keyword := range keywords {
+		if token := x.Tokens[index+offset]; !token.Is(keyword) && token.Text != keyword {
+			return false
+		}
+	}
+
+	return true
+}
+
+// walk calls the function for the message and the messages it contains,
+// depth first, until the function returns false.
+func walk(msg protoreflect.Message, fn func(msg protoreflect.Message) bool) bool {
+	if !msg.IsValid() {
+		return true
+	}
+
+	if !fn(msg) {
+		return false
+	}
+
+	next := true
+	msg.Range(func(field protoreflect.FieldDescriptor, value protoreflect.Value) bool {
+		switch {
+		case field.Kind() != protoreflect.MessageKind:
+		case field.IsList():
+			for index := 0; index < value.List().Len() && next; index++ {
+				next = walk(value.List().Get(index).Message(), fn)
+			}
+		case field.IsMap():
+		default:
+			next = walk(value.Message(), fn)
+		}
+
+		return next
+	})
+
+	return next
+}
+
+// query represents a statement of a PL/pgSQL function body.
+type query struct {
+	// text is the text of the statement, or of the expression that builds it.
+	text string
+	// line is the line of the statement in the body, starting at 1.
+	line int
+	// dynamic reports whether the statement is built by an EXECUTE.
+	dynamic bool
+	// prefix is the length of the start of the text that is not in the
+	// body. A PERFORM is parsed as a SELECT.
+	prefix int
+}
+
+// queries returns the statements of the tree of a PL/pgSQL function.
+func queries(tree any, line int) []*query {
+	var collection []*query
+
+	switch node := tree.(type) {
+	case []any:
+		for _, item := range node {
+			collection = append(collection, queries(item, line)...)
+		}
+	case map[string]any:
+		if value, ok := node["lineno"].(float64); ok {
+			line = int(value)
+		}
+
+		for _, key := range slices.Sorted(maps.Keys(node)) {
+			switch value := node[key].(type) {
+			case map[string]any:
+				switch key {
+				case "PLpgSQL_stmt_dynexecute":
+					expr := expression(value["query"])
+					collection = append(collection, &query{text: expr, line: lineOf(value, line), dynamic: true})
+					continue
+				case "PLpgSQL_stmt_perform":
+					text := expression(value["expr"])
+					collection = append(collection, &query{text: text, line: lineOf(value, line), prefix: len("SELECT ")})
+					continue
+				case "PLpgSQL_expr":
+					// The parse mode 0 is a statement, the others are expressions
+					if mode, _ := value["parseMode"].(float64); mode == 0 {
+						text, _ := value["query"].(string)
+						collection = append(collection, &query{text: text, line: line})
+					}
+					continue
+				}
+
+				collection = append(collection, queries(value, line)...)
+			case []any:
+				collection = append(collection, queries(value, line)...)
+			}
+		}
+	}
+
+	return collection
+}
+
+// expression returns the text of the PL/pgSQL expression.
+func expression(value any) string {
+	node, _ := value.(map[string]any)
+	expr, _ := node["PLpgSQL_expr"].(map[string]any)
+	text, _ := expr["query"].(string)
+	return text
+}
+
+// lineOf returns the line of the PL/pgSQL statement, or the given line.
+func lineOf(node map[string]any, line int) int {
+	if value, ok := node["lineno"].(float64); ok {
+		return int(value)
+	}
+
+	return line
+}
+
+// statement parses the query of the body of a DO block that starts at the
+// given position. An EXECUTE of a string literal is the statement of the
+// literal.
+func (x *query) statement(body string, pos parser.Position) *Statement {
+	block := &Statement{Text: body, Pos: pos}
+
+	// The query is searched from the start of its line
+	start := 0
+	for line := 1; line < x.line; line++ {
+		index := strings.IndexByte(body[start:], '\n')
+		if index < 0 {
+			break
+		}
+		start += index + 1
+	}
+
+	if index := strings.Index(body[start:], x.text[x.prefix:]); index >= 0 {
+		start += index - x.prefix
+	}
+
+	if !x.dynamic {
+		return parse(x.text, block.Position(start))
+	}
+
+	if value, ok := literal(x.text); ok {
+		if index := strings.Index(x.text, value); index >= 0 {
+			start += index
+		}
+
+		return parse(value, block.Position(start))
+	}
+
+	return &Statement{Text: x.text, Pos: block.Position(start), Dynamic: true}
+}
+
+// literal returns the value of the expression when it is a string literal.
+func literal(expr string) (string, bool) {
+	tree, err := pgquery.Parse("SELECT " + expr)
+	if err != nil || len(tree.GetStmts()) != 1 {
+		return "", false
+	}
+
+	targets := tree.GetStmts()[0].GetStmt().GetSelectStmt().GetTargetList()
+	if len(targets) != 1 {
+		return "", false
+	}
+
+	value := targets[0].GetResTarget().GetVal().GetAConst().GetSval()
+	if value == nil {
+		return "", false
+	}
+
+	return value.GetSval(), true
+}
+
+// offset returns the byte offset of the character at the given index.
+func offset(text string, index int) int {
+	for offset := range text {
+		if index == 0 {
+			return offset
+		}
+		index--
+	}
+
+	return len(text)
+}
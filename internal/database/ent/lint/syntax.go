@@ -0,0 +1,35 @@
+package lint
+
+// Syntax reports the statements that the other analyzers cannot check: the
+// statements that PostgreSQL cannot parse, and the statements that an
+// EXECUTE of a DO block builds from an expression at runtime.
+var Syntax Analyzer = AnalyzerFunc(func(file *File) []*Finding {
+	var collection []*Finding
+
+	for _, stmt := range file.statements() {
+		stmt.Walk(func(stmt *Statement) {
+			switch {
+			case stmt.Err != nil:
+				collection = append(collection, &Finding{
+					File:       file.Name,
+					Pos:        stmt.Position(stmt.cursor),
+					Rule:       "syntax",
+					Severity:   SeverityError,
+					Message:    stmt.Err.Error(),
+					Suggestion: "fix the syntax of the statement",
+				})
+			case stmt.Dynamic:
+				collection = append(collection, &Finding{
+					File:       file.Name,
+					Pos:        stmt.Pos,
+					Rule:       "dynamic-statement",
+					Severity:   SeverityWarning,
+					Message:    "the statement is built at runtime and cannot be checked",
+					Suggestion: "execute the statement directly, or review the statements it can build",
+				})
+			}
+		})
+	}
+
+	return collection
+})
@@ -0,0 +1,49 @@
+package lint_test
+
+import (
+	"github.com/aws-contrib/aurora/internal/database/ent/lint"
+	"github.com/aws-contrib/aurora/internal/database/ent/parser"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Syntax", func() {
+	analyze := func(text string) []*lint.Finding {
+		statements, err := parser.Split(text)
+		Expect(err).NotTo(HaveOccurred())
+
+		file := &lint.File{Name: "20250101000000_schema.sql", Statements: statements}
+		return lint.Lint(file, lint.Syntax)
+	}
+
+	It("accepts the statements that can be checked", func() {
+		findings := analyze("CREATE INDEX ASYNC IF NOT EXISTS idx_users_name ON users (name);\n" +
+			"DO $$ BEGIN EXECUTE 'DROP TABLE IF EXISTS users'; END $$;\n")
+		Expect(findings).To(BeEmpty())
+	})
+
+	It("reports the syntax errors", func() {
+		findings := analyze("SELECT 1;\nCREATE TABEL users (id UUID);")
+		Expect(findings).To(HaveLen(1))
+		Expect(findings[0].Rule).To(Equal("syntax"))
+		Expect(findings[0].Severity).To(Equal(lint.SeverityError))
+		Expect(findings[0].Message).To(Equal(`syntax error at or near "TABEL"`))
+		Expect(findings[0].Pos).To(Equal(parser.Position{Offset: 17, Line: 2, Column: 8}))
+	})
+
+	It("reports the statements built at runtime", func() {
+		findings := analyze("DO $$\nBEGIN\n  EXECUTE format('DROP TABLE %I', 'users');\nEND $$;")
+		Expect(findings).To(HaveLen(1))
+		Expect(findings[0].Rule).To(Equal("dynamic-statement"))
+		Expect(findings[0].Severity).To(Equal(lint.SeverityWarning))
+		Expect(findings[0].Pos.Line).To(Equal(3))
+		Expect(findings[0].Pos.Column).To(Equal(11))
+	})
+
+	It("reports the DO blocks of the other languages", func() {
+		findings := analyze("DO LANGUAGE plpython3u $$ plpy.execute('DROP TABLE users') $$;")
+		Expect(findings).To(HaveLen(1))
+		Expect(findings[0].Rule).To(Equal("dynamic-statement"))
+	})
+})
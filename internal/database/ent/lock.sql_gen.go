@@ -10,12 +10,69 @@ import (
 	"time"
 )
 
+const alterTableLocksAddExpiresAt = `-- name: AlterTableLocksAddExpiresAt :exec
+ALTER TABLE aurora_schema_locks ADD COLUMN IF NOT EXISTS expires_at TIMESTAMP WITH TIME ZONE
+`
+
+// Adds the column 'expires_at' to the table 'aurora_schema_locks'
+func (q *Queries) AlterTableLocksAddExpiresAt(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, alterTableLocksAddExpiresAt)
+	return err
+}
+
+const alterTableLocksAddHostname = `-- name: AlterTableLocksAddHostname :exec
+ALTER TABLE aurora_schema_locks ADD COLUMN IF NOT EXISTS hostname TEXT
+`
+
+// Adds the column 'hostname' to the table 'aurora_schema_locks'
+func (q *Queries) AlterTableLocksAddHostname(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, alterTableLocksAddHostname)
+	return err
+}
+
+const alterTableLocksAddOwner = `-- name: AlterTableLocksAddOwner :exec
+ALTER TABLE aurora_schema_locks ADD COLUMN IF NOT EXISTS owner TEXT
+`
+
+// Adds the column 'owner' to the table 'aurora_schema_locks'
+func (q *Queries) AlterTableLocksAddOwner(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, alterTableLocksAddOwner)
+	return err
+}
+
+const alterTableLocksAddPid = `-- name: AlterTableLocksAddPid :exec
+ALTER TABLE aurora_schema_locks ADD COLUMN IF NOT EXISTS pid INT
+`
+
+// Adds the column 'pid' to the table 'aurora_schema_locks'
+func (q *Queries) AlterTableLocksAddPid(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, alterTableLocksAddPid)
+	return err
+}
+
+const alterTableLocksAddVersion = `-- name: AlterTableLocksAddVersion :exec
+ALTER TABLE aurora_schema_locks ADD COLUMN IF NOT EXISTS version TEXT
+`
+
+// Adds the column 'version' to the table 'aurora_schema_locks'
+func (q *Queries) AlterTableLocksAddVersion(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, alterTableLocksAddVersion)
+	return err
+}
+
 const createTableLocks = `-- name: CreateTableLocks :exec
 CREATE TABLE IF NOT EXISTS aurora_schema_locks (
     -- primary key column
     id TEXT PRIMARY KEY,
     -- execution timestamp column
-    created_at TIMESTAMP WITH TIME ZONE NOT NULL
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    -- owner columns
+    owner TEXT NULL,
+    hostname TEXT NULL,
+    pid INT NULL,
+    version TEXT NULL,
+    -- expiration timestamp column
+    expires_at TIMESTAMP WITH TIME ZONE NULL
 )
 `
 
@@ -28,7 +85,7 @@ func (q *Queries) CreateTableLocks(ctx context.Context) error {
 const deleteLock = `-- name: DeleteLock :one
 DELETE FROM aurora_schema_locks
 WHERE id = $1
-RETURNING id, created_at
+RETURNING id, created_at, owner, hostname, pid, version, expires_at
 `
 
 type DeleteLockParams struct {
@@ -39,7 +96,15 @@ type DeleteLockParams struct {
 func (q *Queries) DeleteLock(ctx context.Context, arg *DeleteLockParams) (*Lock, error) {
 	row := q.db.QueryRow(ctx, deleteLock, arg.ID)
 	var i Lock
-	err := row.Scan(&i.ID, &i.CreatedAt)
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.Owner,
+		&i.Hostname,
+		&i.Pid,
+		&i.Version,
+		&i.ExpiresAt,
+	)
 	return &i, err
 }
 
@@ -61,25 +126,163 @@ func (q *Queries) ExecDeleteLock(ctx context.Context, arg *ExecDeleteLockParams)
 const execInsertLock = `-- name: ExecInsertLock :exec
 INSERT INTO aurora_schema_locks (
     id,
-    created_at
+    created_at,
+    owner,
+    hostname,
+    pid,
+    version,
+    expires_at
 ) VALUES (
     $1,
-    $2
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7
 )
 `
 
 type ExecInsertLockParams struct {
-	ID        string    `db:"id" json:"id"`
-	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	ID        string     `db:"id" json:"id"`
+	CreatedAt time.Time  `db:"created_at" json:"created_at"`
+	Owner     *string    `db:"owner" json:"owner"`
+	Hostname  *string    `db:"hostname" json:"hostname"`
+	Pid       *int       `db:"pid" json:"pid"`
+	Version   *string    `db:"version" json:"version"`
+	ExpiresAt *time.Time `db:"expires_at" json:"expires_at"`
 }
 
 // Inserts a row into the table 'aurora_schema_locks' with option ':exec'
 func (q *Queries) ExecInsertLock(ctx context.Context, arg *ExecInsertLockParams) error {
-	_, err := q.db.Exec(ctx, execInsertLock, arg.ID, arg.CreatedAt)
+	_, err := q.db.Exec(ctx, execInsertLock,
+		arg.ID,
+		arg.CreatedAt,
+		arg.Owner,
+		arg.Hostname,
+		arg.Pid,
+		arg.Version,
+		arg.ExpiresAt,
+	)
 	return err
 }
 
-const getLock = `-- name: GetLock :one
+const execRefreshLock = `-- name: ExecRefreshLock :execrows
+UPDATE aurora_schema_locks
+SET
+    expires_at = $1
+WHERE
+    id = $2
+    AND owner = $3
+`
+
+type ExecRefreshLockParams struct {
+	ExpiresAt *time.Time `db:"expires_at" json:"expires_at"`
+	ID        string     `db:"id" json:"id"`
+	Owner     *string    `db:"owner" json:"owner"`
+}
+
+// Extends the expiration of a row owned by the given owner in the table 'aurora_schema_locks'
+func (q *Queries) ExecRefreshLock(ctx context.Context, arg *ExecRefreshLockParams) (int64, error) {
+	result, err := q.db.Exec(ctx, execRefreshLock, arg.ExpiresAt, arg.ID, arg.Owner)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const execReleaseLock = `-- name: ExecReleaseLock :exec
+DELETE FROM aurora_schema_locks
+WHERE
+    id = $1
+    AND owner = $2
+`
+
+type ExecReleaseLockParams struct {
+	ID    string  `db:"id" json:"id"`
+	Owner *string `db:"owner" json:"owner"`
+}
+
+// Deletes a row owned by the given owner from the table 'aurora_schema_locks'
+func (q *Queries) ExecReleaseLock(ctx context.Context, arg *ExecReleaseLockParams) error {
+	_, err := q.db.Exec(ctx, execReleaseLock, arg.ID, arg.Owner)
+	return err
+}
+
+const execReleaseObservedLock = `-- name: ExecReleaseObservedLock :execrows
+DELETE FROM aurora_schema_locks
+WHERE
+    id = $1
+    AND created_at = $2
+`
+
+type ExecReleaseObservedLockParams struct {
+	ID                string    `db:"id" json:"id"`
+	ObservedCreatedAt time.Time `db:"observed_created_at" json:"observed_created_at"`
+}
+
+// Deletes a row from the table 'aurora_schema_locks' if it was not taken over since it was read.
+// A new holder sets a new creation timestamp, which every version of the table has.
+func (q *Queries) ExecReleaseObservedLock(ctx context.Context, arg *ExecReleaseObservedLockParams) (int64, error) {
+	result, err := q.db.Exec(ctx, execReleaseObservedLock, arg.ID, arg.ObservedCreatedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const execTakeoverLock = `-- name: ExecTakeoverLock :execrows
+UPDATE aurora_schema_locks
+SET
+    created_at = $1,
+    owner = $2,
+    hostname = $3,
+    pid = $4,
+    version = $5,
+    expires_at = $6
+WHERE
+    id = $7
+    AND created_at = $8
+    AND owner IS NOT DISTINCT FROM $9
+    AND expires_at IS NOT DISTINCT FROM $10
+    AND expires_at < now()
+`
+
+type ExecTakeoverLockParams struct {
+	CreatedAt         time.Time  `db:"created_at" json:"created_at"`
+	Owner             *string    `db:"owner" json:"owner"`
+	Hostname          *string    `db:"hostname" json:"hostname"`
+	Pid               *int       `db:"pid" json:"pid"`
+	Version           *string    `db:"version" json:"version"`
+	ExpiresAt         *time.Time `db:"expires_at" json:"expires_at"`
+	ID                string     `db:"id" json:"id"`
+	ObservedCreatedAt time.Time  `db:"observed_created_at" json:"observed_created_at"`
+	ObservedOwner     *string    `db:"observed_owner" json:"observed_owner"`
+	ObservedExpiresAt *time.Time `db:"observed_expires_at" json:"observed_expires_at"`
+}
+
+// Takes over a row from the table 'aurora_schema_locks' if it is still held by the observed owner
+// and has expired according to the database clock. A row without expiration never expires.
+func (q *Queries) ExecTakeoverLock(ctx context.Context, arg *ExecTakeoverLockParams) (int64, error) {
+	result, err := q.db.Exec(ctx, execTakeoverLock,
+		arg.CreatedAt,
+		arg.Owner,
+		arg.Hostname,
+		arg.Pid,
+		arg.Version,
+		arg.ExpiresAt,
+		arg.ID,
+		arg.ObservedCreatedAt,
+		arg.ObservedOwner,
+		arg.ObservedExpiresAt,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const getLegacyLock = `-- name: GetLegacyLock :one
 SELECT
     id,
     created_at
@@ -89,6 +292,39 @@ WHERE
     id = $1
 `
 
+type GetLegacyLockParams struct {
+	ID string `db:"id" json:"id"`
+}
+
+type GetLegacyLockRow struct {
+	ID        string    `db:"id" json:"id"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+// Retrieves a row from the table 'aurora_schema_locks' with the columns of its first version,
+// when the table was not upgraded yet.
+func (q *Queries) GetLegacyLock(ctx context.Context, arg *GetLegacyLockParams) (*GetLegacyLockRow, error) {
+	row := q.db.QueryRow(ctx, getLegacyLock, arg.ID)
+	var i GetLegacyLockRow
+	err := row.Scan(&i.ID, &i.CreatedAt)
+	return &i, err
+}
+
+const getLock = `-- name: GetLock :one
+SELECT
+    id,
+    created_at,
+    owner,
+    hostname,
+    pid,
+    version,
+    expires_at
+FROM
+    aurora_schema_locks
+WHERE
+    id = $1
+`
+
 type GetLockParams struct {
 	ID string `db:"id" json:"id"`
 }
@@ -97,30 +333,69 @@ type GetLockParams struct {
 func (q *Queries) GetLock(ctx context.Context, arg *GetLockParams) (*Lock, error) {
 	row := q.db.QueryRow(ctx, getLock, arg.ID)
 	var i Lock
-	err := row.Scan(&i.ID, &i.CreatedAt)
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.Owner,
+		&i.Hostname,
+		&i.Pid,
+		&i.Version,
+		&i.ExpiresAt,
+	)
 	return &i, err
 }
 
 const insertLock = `-- name: InsertLock :one
 INSERT INTO aurora_schema_locks (
     id,
-    created_at
+    created_at,
+    owner,
+    hostname,
+    pid,
+    version,
+    expires_at
 ) VALUES (
     $1,
-    $2
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7
 )
-RETURNING id, created_at
+RETURNING id, created_at, owner, hostname, pid, version, expires_at
 `
 
 type InsertLockParams struct {
-	ID        string    `db:"id" json:"id"`
-	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	ID        string     `db:"id" json:"id"`
+	CreatedAt time.Time  `db:"created_at" json:"created_at"`
+	Owner     *string    `db:"owner" json:"owner"`
+	Hostname  *string    `db:"hostname" json:"hostname"`
+	Pid       *int       `db:"pid" json:"pid"`
+	Version   *string    `db:"version" json:"version"`
+	ExpiresAt *time.Time `db:"expires_at" json:"expires_at"`
 }
 
 // Inserts a row into the table 'aurora_schema_locks' with option ':one'
 func (q *Queries) InsertLock(ctx context.Context, arg *InsertLockParams) (*Lock, error) {
-	row := q.db.QueryRow(ctx, insertLock, arg.ID, arg.CreatedAt)
+	row := q.db.QueryRow(ctx, insertLock,
+		arg.ID,
+		arg.CreatedAt,
+		arg.Owner,
+		arg.Hostname,
+		arg.Pid,
+		arg.Version,
+		arg.ExpiresAt,
+	)
 	var i Lock
-	err := row.Scan(&i.ID, &i.CreatedAt)
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.Owner,
+		&i.Hostname,
+		&i.Pid,
+		&i.Version,
+		&i.ExpiresAt,
+	)
 	return &i, err
 }
@@ -27,6 +27,34 @@ type ExecInsertLockParamsConverter interface {
 	SetFromLock(target *ExecInsertLockParams, source *Lock)
 }
 
+// goverter:converter
+// goverter:skipCopySameType yes
+// goverter:output:file models_conv_gen.go
+// goverter:output:package github.com/aws-contrib/aurora/internal/database/ent
+type ExecRefreshLockParamsConverter interface {
+	// goverter:update target
+	SetFromLock(target *ExecRefreshLockParams, source *Lock)
+}
+
+// goverter:converter
+// goverter:skipCopySameType yes
+// goverter:output:file models_conv_gen.go
+// goverter:output:package github.com/aws-contrib/aurora/internal/database/ent
+type ExecTakeoverLockParamsConverter interface {
+	// goverter:update target
+	// goverter:ignore ObservedCreatedAt ObservedOwner ObservedExpiresAt
+	SetFromLock(target *ExecTakeoverLockParams, source *Lock)
+}
+
+// goverter:converter
+// goverter:skipCopySameType yes
+// goverter:output:file models_conv_gen.go
+// goverter:output:package github.com/aws-contrib/aurora/internal/database/ent
+type ExecReleaseLockParamsConverter interface {
+	// goverter:update target
+	SetFromLock(target *ExecReleaseLockParams, source *Lock)
+}
+
 // goverter:converter
 // goverter:skipCopySameType yes
 // goverter:output:file models_conv_gen.go
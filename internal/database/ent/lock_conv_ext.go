@@ -20,6 +20,24 @@ func (x *ExecInsertLockParams) SetLock(entity *Lock) {
 	converter.SetFromLock(x, entity)
 }
 
+// SetLock sets the params from the entity.
+func (x *ExecRefreshLockParams) SetLock(entity *Lock) {
+	converter := &ExecRefreshLockParamsConverterImpl{}
+	converter.SetFromLock(x, entity)
+}
+
+// SetLock sets the params from the entity.
+func (x *ExecTakeoverLockParams) SetLock(entity *Lock) {
+	converter := &ExecTakeoverLockParamsConverterImpl{}
+	converter.SetFromLock(x, entity)
+}
+
+// SetLock sets the params from the entity.
+func (x *ExecReleaseLockParams) SetLock(entity *Lock) {
+	converter := &ExecReleaseLockParamsConverterImpl{}
+	converter.SetFromLock(x, entity)
+}
+
 // SetLock sets the params from the entity.
 func (x *DeleteLockParams) SetLock(entity *Lock) {
 	converter := &DeleteLockParamsConverterImpl{}
@@ -114,3 +114,66 @@ var _ = Describe("ExecDeleteRevisionParams", func() {
 		})
 	})
 })
+
+var _ = Describe("ExecRefreshLockParams", func() {
+	var params ent.ExecRefreshLockParams
+
+	BeforeEach(func() {
+		params = ent.ExecRefreshLockParams{}
+	})
+
+	Describe("SetLock", func() {
+		var entity *ent.Lock
+
+		BeforeEach(func() {
+			entity = NewFakeLock()
+		})
+
+		It("sets the entity", func() {
+			params.SetLock(entity)
+			Expect(params).NotTo(BeZero())
+		})
+	})
+})
+
+var _ = Describe("ExecTakeoverLockParams", func() {
+	var params ent.ExecTakeoverLockParams
+
+	BeforeEach(func() {
+		params = ent.ExecTakeoverLockParams{}
+	})
+
+	Describe("SetLock", func() {
+		var entity *ent.Lock
+
+		BeforeEach(func() {
+			entity = NewFakeLock()
+		})
+
+		It("sets the entity", func() {
+			params.SetLock(entity)
+			Expect(params).NotTo(BeZero())
+		})
+	})
+})
+
+var _ = Describe("ExecReleaseLockParams", func() {
+	var params ent.ExecReleaseLockParams
+
+	BeforeEach(func() {
+		params = ent.ExecReleaseLockParams{}
+	})
+
+	Describe("SetLock", func() {
+		var entity *ent.Lock
+
+		BeforeEach(func() {
+			entity = NewFakeLock()
+		})
+
+		It("sets the entity", func() {
+			params.SetLock(entity)
+			Expect(params).NotTo(BeZero())
+		})
+	})
+})
@@ -3,151 +3,558 @@
 package ent
 
 import (
+	"cmp"
 	"context"
+	"errors"
 	"fmt"
 	"io/fs"
+	"os"
+	"os/user"
+	"path/filepath"
 	"regexp"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/aws-contrib/aurora/internal/database/ent/lint"
+	"github.com/aws-contrib/aurora/internal/database/ent/parser"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 )
 
 var (
-	commentRegexp     = regexp.MustCompile(`(?m)^\s*--.*$`)
 	createIndexRegexp = regexp.MustCompile(`(?i)CREATE\s+(UNIQUE\s+)?INDEX(\s+(?:CONCURRENTLY|ASYNC))?`)
+	asyncIndexRegexp  = regexp.MustCompile(`(?i)CREATE\s+(UNIQUE\s+)?INDEX\s+ASYNC`)
+	descriptionRegexp = regexp.MustCompile(`[^a-z0-9]+`)
 )
 
 //counterfeiter:generate -o ./fake . FileSystem
 
-// FileSystem represents a filesystem that supports globbing and reading files.
+// FileSystem represents a filesystem that supports globbing, reading and writing files.
 type FileSystem interface {
 	fs.FS
 	fs.GlobFS
 	fs.ReadFileFS
+	WriteFileFS
 }
 
+// WriteFileFS is the interface implemented by a file system that supports writing files.
+type WriteFileFS interface {
+	fs.FS
+	// WriteFile writes data to the named file, creating it if necessary.
+	WriteFile(name string, data []byte, perm fs.FileMode) error
+}
+
+var _ FileSystem = DirFS("")
+
+// DirFS is a FileSystem for the tree of files rooted at the given directory.
+type DirFS string
+
+// Open implements fs.FS.
+func (x DirFS) Open(name string) (fs.File, error) {
+	return os.DirFS(string(x)).Open(name)
+}
+
+// Glob implements fs.GlobFS.
+func (x DirFS) Glob(pattern string) ([]string, error) {
+	return fs.Glob(os.DirFS(string(x)), pattern)
+}
+
+// ReadFile implements fs.ReadFileFS.
+func (x DirFS) ReadFile(name string) ([]byte, error) {
+	return fs.ReadFile(os.DirFS(string(x)), name)
+}
+
+// WriteFile implements WriteFileFS.
+func (x DirFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	if !fs.ValidPath(name) {
+		return &fs.PathError{Op: "write", Path: name, Err: fs.ErrInvalid}
+	}
+
+	return os.WriteFile(filepath.Join(string(x), filepath.FromSlash(name)), data, perm)
+}
+
+const (
+	// RevisionSchemeTimestamp generates revision IDs from the current UTC time (e.g. 20060102150405).
+	RevisionSchemeTimestamp = "timestamp"
+	// RevisionSchemeSequential generates revision IDs by incrementing the last revision ID (e.g. 000001).
+	RevisionSchemeSequential = "sequential"
+)
+
 // MigrationLock is a UUID used to identify the migration lock in the database.
 var MigrationLock = uuid.NewMD5(uuid.NameSpaceOID, []byte("aurora_schema_migrations"))
 
+// DefaultLockTTL is how long a lock is held without a heartbeat before it expires.
+const DefaultLockTTL = time.Minute
+
 // MigrationRepository represents a repository for managing revisions.
 type MigrationRepository struct {
 	// Gateway represents the database gateway.
 	Gateway Gateway
 	// FileSystem is the filesystem where the revision files are located.
 	FileSystem fs.FS
+	// Retry is the policy for retrying optimistic concurrency conflicts of
+	// the statements and of the lock and revision writes. Nil disables retries.
+	Retry *RetryPolicy
+	// Version is the version of the tool, recorded in the audit trail.
+	Version string
+	// Dialect is the SQL dialect of the database. Empty means DialectDSQL.
+	Dialect Dialect
+	// EmulateJobs records a completed job in sys.jobs for each index that
+	// PostgreSQL builds, as Aurora DSQL would. It requires DialectPostgres.
+	EmulateJobs bool
+	// lease is the migration lock held by the repository.
+	lease *lease
+}
+
+// lease represents a migration lock that is kept alive by a heartbeat.
+type lease struct {
+	lock   *Lock
+	done   chan struct{}
+	cancel context.CancelFunc
+	// lost is canceled with ErrLockLost when the lock is taken over or
+	// could not be refreshed before it expired.
+	lost context.Context
+	lose context.CancelCauseFunc
+}
+
+// InitMigrationsParams represents the parameters for initializing the migration tables.
+type InitMigrationsParams struct{}
+
+// InitMigrations creates the tables that record the migrations, and adds
+// the columns of the newer versions to the existing ones. Each statement is
+// retried on a concurrency conflict, as another process may run it at the
+// same time. Only the commands that write the revisions call it.
+func (x *MigrationRepository) InitMigrations(ctx context.Context, _ *InitMigrationsParams) error {
+	steps := []func(context.Context) error{
+		x.Gateway.CreateTableLocks,
+		x.Gateway.AlterTableLocksAddOwner,
+		x.Gateway.AlterTableLocksAddHostname,
+		x.Gateway.AlterTableLocksAddPid,
+		x.Gateway.AlterTableLocksAddVersion,
+		x.Gateway.AlterTableLocksAddExpiresAt,
+		x.Gateway.CreateTableRevisions,
+		x.Gateway.AlterTableRevisionsAddRevertCount,
+		x.Gateway.AlterTableRevisionsAddRevertedAt,
+		x.Gateway.AlterTableRevisionsAddHash,
+		x.Gateway.AlterTableRevisionsAddPartialHashes,
+		x.Gateway.CreateTableRevisionEvents,
+	}
+
+	// Aurora DSQL provides sys.jobs, PostgreSQL only has it when it is emulated
+	if x.EmulateJobs {
+		steps = append(steps, x.Gateway.CreateSchemaSys, x.Gateway.CreateTableJobs)
+	}
+
+	for _, step := range steps {
+		if err := x.Retry.Do(ctx, func() error { return step(ctx) }); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 // LockMigrationParams represents the parameters for locking a revision.
 type LockMigrationParams struct {
 	// Timeout is the maximum time to wait for the lock.
 	Timeout time.Duration
+	// TTL is how long the lock is held without a heartbeat. Defaults to DefaultLockTTL.
+	TTL time.Duration
+	// Version is the version of the process that holds the lock.
+	Version string
 }
 
-// LockMigration locks a revision for exclusive access.
+// LockMigration locks a revision for exclusive access. The lock records its
+// owner and expires unless the owner refreshes it, so a lock left behind by
+// a killed process is taken over once it has expired.
 func (x *MigrationRepository) LockMigration(ctx context.Context, params *LockMigrationParams) error {
-	start := time.Now()
+	if params.TTL < 0 {
+		return fmt.Errorf("lock ttl must be positive, got %s", params.TTL)
+	}
+
+	ttl := cmp.Or(params.TTL, DefaultLockTTL)
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		return err
+	}
+
+	var (
+		pid   = os.Getpid()
+		owner = uuid.New().String()
+		start = time.Now()
+	)
 
 	for {
-		// create the revision
+		now := time.Now().UTC()
+		expiresAt := now.Add(ttl)
+
+		lock := &Lock{}
+		lock.ID = MigrationLock.String()
+		lock.CreatedAt = now
+		lock.Owner = &owner
+		lock.Hostname = &hostname
+		lock.Pid = &pid
+		lock.Version = &params.Version
+		lock.ExpiresAt = &expiresAt
+
 		args := &ExecInsertLockParams{}
-		args.ID = MigrationLock.String()
-		args.CreatedAt = time.Now().UTC()
+		args.SetLock(lock)
 
 		err := x.Gateway.ExecInsertLock(ctx, args)
-		// Waiting for the lock to be acquired
 		switch {
 		case err == nil:
+			x.heartbeat(ctx, lock, ttl)
 			return nil
-		case IsErrorCode(err, ErrCodeUniqueViolation):
-			time.Sleep(1 * time.Second)
-		case time.Since(start) > params.Timeout:
-			return fmt.Errorf("timeout while waiting for lock")
+		// DSQL reports a concurrent insert as a conflict of the optimistic concurrency control
+		case IsErrorCode(err, ErrCodeUniqueViolation), IsErrorCode(err, ErrCodeSerializationFailure):
+			ok, err := x.takeoverLock(ctx, lock)
+			switch {
+			case err != nil:
+				return err
+			case ok:
+				x.heartbeat(ctx, lock, ttl)
+				return nil
+			}
 		default:
 			return err
 		}
+
+		if time.Since(start) > params.Timeout {
+			return fmt.Errorf("timeout while waiting for lock")
+		}
+
+		// Waiting for the lock to be released or to expire
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Second):
+		}
 	}
 }
 
-// UnlockMigration unlocks the revision after exclusive access.
+// UnlockMigration unlocks the revision after exclusive access. It returns
+// ErrLockLost if another process took the lock over in the meantime.
 func (x *MigrationRepository) UnlockMigration(ctx context.Context) error {
-	args := &ExecDeleteLockParams{}
+	lease := x.lease
+	// Without lease we release the lock whoever holds it
+	if lease == nil {
+		args := &ExecDeleteLockParams{}
+		args.ID = MigrationLock.String()
+
+		return x.Retry.Do(ctx, func() error {
+			return x.Gateway.ExecDeleteLock(ctx, args)
+		})
+	}
+
+	// stop the heartbeat
+	lease.cancel()
+	<-lease.done
+	x.lease = nil
+
+	args := &ExecReleaseLockParams{}
+	args.SetLock(lease.lock)
+	// Only the lock of the owner is released
+	err := x.Retry.Do(ctx, func() error {
+		return x.Gateway.ExecReleaseLock(ctx, args)
+	})
+	if err != nil {
+		return err
+	}
+
+	return context.Cause(lease.lost)
+}
+
+// ReleaseMigrationLockParams represents the parameters for releasing a migration lock held by another process.
+type ReleaseMigrationLockParams struct {
+	// Lock is the lock returned by GetMigrationLock.
+	Lock *Lock
+}
+
+// ReleaseMigrationLock releases the given lock on behalf of its holder, such
+// as a stopped process. It returns ErrLockChanged if the lock was released or
+// taken over since it was read, so a lock that was not inspected is never
+// released.
+func (x *MigrationRepository) ReleaseMigrationLock(ctx context.Context, params *ReleaseMigrationLockParams) error {
+	args := &ExecReleaseObservedLockParams{}
+	args.ID = params.Lock.ID
+	args.ObservedCreatedAt = params.Lock.CreatedAt
+
+	var count int64
+	err := x.Retry.Do(ctx, func() (err error) {
+		count, err = x.Gateway.ExecReleaseObservedLock(ctx, args)
+		return err
+	})
+	switch {
+	case err != nil:
+		return err
+	case count == 0:
+		return ErrLockChanged
+	default:
+		return nil
+	}
+}
+
+// GetMigrationLockParams represents the parameters for inspecting the migration lock.
+type GetMigrationLockParams struct{}
+
+// GetMigrationLock returns the migration lock or ErrNoRows if it is not held.
+func (x *MigrationRepository) GetMigrationLock(ctx context.Context, _ *GetMigrationLockParams) (*Lock, error) {
+	args := &GetLockParams{}
 	args.ID = MigrationLock.String()
 
-	return x.Gateway.ExecDeleteLock(ctx, args)
+	lock, err := x.Gateway.GetLock(ctx, args)
+	switch {
+	case IsErrorCode(err, ErrCodeUndefinedTable):
+		// No migration was applied to the database yet
+		return nil, ErrNoRows
+	case IsErrorCode(err, ErrCodeUndefinedColumn):
+		// The table was created by an older version, only the commands
+		// that write upgrade it
+		return x.getLegacyLock(ctx, args.ID)
+	default:
+		return lock, err
+	}
+}
+
+// getLegacyLock returns the lock from the table of an older version. Such a
+// lock has no owner and never expires.
+func (x *MigrationRepository) getLegacyLock(ctx context.Context, id string) (*Lock, error) {
+	args := &GetLegacyLockParams{}
+	args.ID = id
+
+	row, err := x.Gateway.GetLegacyLock(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+
+	lock := &Lock{}
+	lock.ID = row.ID
+	lock.CreatedAt = row.CreatedAt
+
+	return lock, nil
+}
+
+// takeoverLock replaces the current lock with the given one if the current
+// lock has expired according to the database clock. The update succeeds only
+// if the current lock did not change since it was read, so only one of the
+// waiters takes it over.
+func (x *MigrationRepository) takeoverLock(ctx context.Context, lock *Lock) (bool, error) {
+	params := &GetLockParams{}
+	params.SetLock(lock)
+
+	current, err := x.Gateway.GetLock(ctx, params)
+	switch {
+	case err == pgx.ErrNoRows:
+		// The lock was released, we should try to insert it again
+		return false, nil
+	case err != nil:
+		return false, err
+	case current.ExpiresAt == nil:
+		// A lock of an older version never expires, it is held by a process
+		// that does not refresh it. It must be released with unlock.
+		return false, nil
+	}
+
+	args := &ExecTakeoverLockParams{}
+	args.SetLock(lock)
+	args.ObservedCreatedAt = current.CreatedAt
+	args.ObservedOwner = current.Owner
+	args.ObservedExpiresAt = current.ExpiresAt
+
+	count, err := x.Gateway.ExecTakeoverLock(ctx, args)
+	switch {
+	case IsErrorCode(err, ErrCodeSerializationFailure):
+		// Another waiter took the lock over concurrently
+		return false, nil
+	case err != nil:
+		return false, err
+	default:
+		return count == 1, nil
+	}
+}
+
+// heartbeat refreshes the expiration of the lock until it is unlocked. The
+// lease is lost when the lock is taken over, or when it was not refreshed
+// before it expired.
+func (x *MigrationRepository) heartbeat(ctx context.Context, lock *Lock, ttl time.Duration) {
+	// The lock is held until it is unlocked, not until the context is done
+	ctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	lost, lose := context.WithCancelCause(context.Background())
+
+	x.lease = &lease{
+		lock:   lock,
+		done:   make(chan struct{}),
+		cancel: cancel,
+		lost:   lost,
+		lose:   lose,
+	}
+
+	go func(lease *lease) {
+		defer close(lease.done)
+
+		ticker := time.NewTicker(ttl / 3)
+		defer ticker.Stop()
+		// The lock expires ttl after its last refresh
+		expiry := time.NewTimer(time.Until(lock.CreatedAt.Add(ttl)))
+		defer expiry.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-expiry.C:
+				lease.lose(ErrLockLost)
+				return
+			case <-ticker.C:
+			}
+
+			refreshedAt := time.Now().UTC()
+			expiresAt := refreshedAt.Add(ttl)
+
+			args := &ExecRefreshLockParams{}
+			args.SetLock(lease.lock)
+			args.ExpiresAt = &expiresAt
+
+			count, err := x.Gateway.ExecRefreshLock(ctx, args)
+			switch {
+			case err != nil:
+				// A failed refresh is retried on the next tick. The lock
+				// does not expire before ttl, so there are a few attempts.
+				continue
+			case count == 0:
+				lease.lose(ErrLockLost)
+				return
+			}
+
+			expiry.Reset(time.Until(expiresAt))
+		}
+	}(x.lease)
+}
+
+// guard returns a context that is canceled with ErrLockLost when the lease
+// of the migration lock is lost, so no statement is executed while another
+// process may hold the lock.
+func (x *MigrationRepository) guard(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancelCause(ctx)
+
+	lease := x.lease
+	if lease == nil {
+		return ctx, func() { cancel(nil) }
+	}
+
+	// The function runs in its own goroutine, even if the lease is already lost
+	if err := context.Cause(lease.lost); err != nil {
+		cancel(err)
+	}
+
+	stop := context.AfterFunc(lease.lost, func() {
+		cancel(context.Cause(lease.lost))
+	})
+
+	return ctx, func() {
+		stop()
+		cancel(nil)
+	}
+}
+
+// DestructiveChangeError occurs when a migration has destructive changes
+// that are not allowed.
+type DestructiveChangeError struct {
+	// Name is the name of the migration file.
+	Name string
+	// Findings are the destructive changes of the migration.
+	Findings []*lint.Finding
+}
+
+// Error implements error.
+func (x *DestructiveChangeError) Error() string {
+	return fmt.Sprintf("revision %s has %d destructive changes that are not allowed (first: %s)", x.Name, len(x.Findings), x.Findings[0])
 }
 
 // ApplyMigrationParams represents the parameters for executing a revision.
 type ApplyMigrationParams struct {
 	// Migration contains the parameters for executing a migration.
 	Migration *Migration
+	// JobTimeout is the maximum time to wait for an asynchronous job. Zero means no timeout.
+	JobTimeout time.Duration
+	// Parallel submits consecutive index statements together and waits for
+	// their asynchronous jobs at once. Migrations can opt in with the
+	// '-- +parallel' directive at the top of the file.
+	Parallel bool
+	// AllowDestructive applies the statements that delete data or can lose
+	// existing data. Migrations can opt in with the '-- +destructive'
+	// directive at the top of the file.
+	AllowDestructive bool
 }
 
-// ApplyMigration executes a revision.
+// ApplyMigration executes a revision. It returns a *DestructiveChangeError,
+// without executing any statement, if the revision has destructive changes
+// that are not allowed.
 func (x *MigrationRepository) ApplyMigration(ctx context.Context, params *ApplyMigrationParams) error {
-	repository := &JobRepository{
-		Gateway: x.Gateway,
+	if !params.AllowDestructive && !params.Migration.Destructive {
+		if findings := params.Migration.GetDestructiveChanges(); len(findings) > 0 {
+			return &DestructiveChangeError{Name: params.Migration.Revision.GetName(), Findings: findings}
+		}
 	}
 
+	ctx, cancel := x.guard(ctx)
+	defer cancel()
+
 	args := &UpsertRevisionParams{}
 	args.SetRevision(params.Migration.Revision)
 	// prepare the revision
-	revision, err := x.Gateway.UpsertRevision(ctx, args)
+	var revision *Revision
+	err := x.Retry.Do(ctx, func() (err error) {
+		revision, err = x.Gateway.UpsertRevision(ctx, args)
+		return err
+	})
 	if err != nil {
 		return err
 	}
 
-	start := time.Now()
-	// Apply the statements one by one
-	for index, query := range params.Migration.Statements {
-		if index+1 <= revision.Count {
-			continue
+	var (
+		start      = time.Now()
+		statements = params.Migration.Statements
+		parallel   = params.Parallel || params.Migration.Parallel
+	)
+
+	// Apply the statements one by one, or group by group in parallel mode
+	for index := revision.Count; index < len(statements); {
+		group := statements[index : index+1]
+		if parallel {
+			group = statements[index : index+groupIndexes(statements[index:])]
 		}
 
-		query = commentRegexp.ReplaceAllString(query, "")
-		query = createIndexRegexp.ReplaceAllString(query, "CREATE ${1}INDEX ASYNC")
-		query = strings.TrimSpace(query)
+		// The lock may have been taken over since the last group
+		if err := context.Cause(ctx); err != nil {
+			return err
+		}
 
-		if len(query) > 0 {
-			// execute the revision
-			row := x.Gateway.Database().QueryRow(ctx, query)
-			// Some queries returns a job id because they are asynchronous
-			var jid string
-			err := row.Scan(&jid)
+		events, err := x.execGroup(ctx, revision, RevisionEventApply, index, revision.GetName(), group, params.JobTimeout)
+		if err != nil {
+			return err
+		}
 
-			switch {
-			case err == pgx.ErrNoRows:
-				revision.Count = index + 1
-			case err != nil:
-				msg := err.Error()
-				// set the revision error
-				revision.Error = &msg
-				revision.ErrorStmt = &query
-			default:
-				args := &WaitJobParams{}
-				args.JobID = jid
-				// Wait for the job to complete
-				job, err := repository.WaitJob(ctx, args)
-				switch {
-				case err == pgx.ErrNoRows:
-					revision.Count = index + 1
-				case err != nil:
-					msg := err.Error()
-					// set the revision error
-					revision.Error = &msg
-					revision.ErrorStmt = &query
-				case job.Status == "failed":
-					// set the revision error
-					revision.Error = job.Details
-					revision.ErrorStmt = &query
-				default:
-					revision.Count = index + 1
-				}
-			}
+		// The results of a group interrupted by the loss of the lock are not recorded
+		if err := context.Cause(ctx); err != nil {
+			return err
+		}
+
+		if msg, query := failure(events); msg != "" {
+			// set the revision error
+			revision.Error = &msg
+			revision.ErrorStmt = &query
 		} else {
-			revision.Count = index + 1
+			index += len(group)
+			// The count advances once the whole group has succeeded
+			revision.Count = index
+			// record the checksums of the executed statements
+			revision.PartialHashes = checksums(statements[:index], revision.PartialHashes)
+			revision.Hash = &params.Migration.Hash
 		}
 
 		revision.ExecutedAt = time.Now().UTC()
@@ -161,17 +568,24 @@ func (x *MigrationRepository) ApplyMigration(ctx context.Context, params *ApplyM
 
 		if args.Error == nil {
 			args.UpdateMask = append(args.UpdateMask, "count")
+			args.UpdateMask = append(args.UpdateMask, "hash")
+			args.UpdateMask = append(args.UpdateMask, "partial_hashes")
 		} else {
 			args.UpdateMask = append(args.UpdateMask, "error")
 			args.UpdateMask = append(args.UpdateMask, "error_stmt")
 		}
 
-		if err := x.Gateway.ExecUpdateRevision(ctx, args); err != nil {
+		if err := x.updateRevision(ctx, args); err != nil {
 			return err
 		}
 
 		// Update the migration parameters
 		params.Migration.Revision = revision
+		// The progress is written first, the audit trail follows it
+		if err := x.record(ctx, events); err != nil {
+			return err
+		}
+
 		// Stop processing if there is an error
 		if args.Error != nil {
 			return nil
@@ -181,45 +595,968 @@ func (x *MigrationRepository) ApplyMigration(ctx context.Context, params *ApplyM
 	return nil
 }
 
-// ListMigrationsParams represents the parameters for listing migrations.
-type ListMigrationsParams struct{}
+// execGroup submits the statements of the group and then waits for all
+// their asynchronous jobs. Each attempted statement is recorded in the audit
+// trail before it is submitted, with its index shifted by the given offset.
+// It returns the events of the attempted statements, whose outcome the
+// caller records with record.
+func (x *MigrationRepository) execGroup(ctx context.Context, revision *Revision, action string, offset int, name string, group []*parser.Statement, timeout time.Duration) ([]*RevisionEvent, error) {
+	var (
+		events []*RevisionEvent
+		failed error
+	)
 
-// ListMigrations lists all revisions in the repository.
-func (x *MigrationRepository) ListMigrations(ctx context.Context, _ *ListMigrationsParams) (collection []*Migration, _ error) {
-	matches, err := fs.Glob(x.FileSystem, "*.sql")
+	finish := func(event *RevisionEvent, stmt *parser.Statement, err error) {
+		finishedAt := time.Now().UTC()
+		event.FinishedAt = &finishedAt
+		event.Outcome = RevisionEventSucceeded
+
+		if err != nil {
+			msg := errorf(name, stmt, err.Error())
+			event.Outcome = RevisionEventFailed
+			event.Error = &msg
+		}
+	}
+
+	for index, stmt := range group {
+		query := x.prepare(stmt)
+		if len(query) == 0 {
+			continue
+		}
+
+		position := offset + index
+
+		event := &RevisionEvent{}
+		event.StatementIndex = &position
+		event.Statement = &query
+		event.StartedAt = time.Now().UTC()
+		// The statement is not executed if its attempt cannot be recorded
+		if failed = x.start(ctx, revision, action, event); failed != nil {
+			break
+		}
+
+		events = append(events, event)
+
+		jid, err := x.submit(ctx, query)
+		if err == nil && jid == nil && x.emulates(stmt) {
+			// record the job that Aurora DSQL would have created
+			jid, err = x.emulate(ctx)
+		}
+
+		if err != nil {
+			finish(event, stmt, err)
+			// The jobs already submitted must still be waited for
+			break
+		}
+
+		if event.JobID = jid; jid == nil {
+			finish(event, stmt, nil)
+		}
+	}
+
+	for _, event := range events {
+		if event.FinishedAt != nil {
+			continue
+		}
+
+		err := x.wait(ctx, *event.JobID, timeout)
+		finish(event, group[*event.StatementIndex-offset], err)
+	}
+
+	return events, failed
+}
+
+// failure returns the errors of the failed events, one per line, and the
+// statement of the first failed event.
+func failure(events []*RevisionEvent) (msg, query string) {
+	var failures []string
+
+	for _, event := range events {
+		if event.Outcome != RevisionEventFailed {
+			continue
+		}
+
+		if len(failures) == 0 {
+			query = *event.Statement
+		}
+
+		failures = append(failures, *event.Error)
+	}
+
+	return strings.Join(failures, "\n"), query
+}
+
+// start writes the event of an action that starts to the audit trail, so
+// an action interrupted by a stopped process stays recorded as running.
+func (x *MigrationRepository) start(ctx context.Context, revision *Revision, action string, event *RevisionEvent) error {
+	event.ID = uuid.New().String()
+	event.CreatedAt = time.Now().UTC()
+	event.RevisionID = revision.ID
+	event.Action = action
+	event.Outcome = RevisionEventRunning
+	event.Operator = operator()
+	event.Version = &x.Version
+
+	args := &ExecInsertRevisionEventParams{}
+	args.SetRevisionEvent(event)
+
+	return x.Retry.Do(ctx, func() error {
+		return x.Gateway.ExecInsertRevisionEvent(ctx, args)
+	})
+}
+
+// record writes the outcome of the finished events to the audit trail.
+func (x *MigrationRepository) record(ctx context.Context, events []*RevisionEvent) error {
+	for _, event := range events {
+		args := &ExecUpdateRevisionEventParams{}
+		args.SetRevisionEvent(event)
+
+		err := x.Retry.Do(ctx, func() error {
+			return x.Gateway.ExecUpdateRevisionEvent(ctx, args)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// BaselineMigrationParams represents the parameters for baselining a revision.
+type BaselineMigrationParams struct {
+	// Migration contains the parameters for baselining a migration.
+	Migration *Migration
+}
+
+// BaselineMigration records the revision as fully applied without executing
+// its statements. It is used to adopt a database whose schema was created
+// by hand or by another tool.
+func (x *MigrationRepository) BaselineMigration(ctx context.Context, params *BaselineMigrationParams) error {
+	revision, err := baseline(params.Migration)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	for _, path := range sort.StringSlice(matches) {
-		// read the revision content
-		data, err := fs.ReadFile(x.FileSystem, path)
+	err = x.Retry.Do(ctx, func() error {
+		// The row of a reverted revision is kept in the table
+		if params.Migration.Revision.RevertedAt != nil {
+			args := &ExecUpdateRevisionParams{}
+			args.SetRevision(revision)
+			// prepare the mask
+			args.UpdateMask = append(args.UpdateMask, "count")
+			args.UpdateMask = append(args.UpdateMask, "total")
+			args.UpdateMask = append(args.UpdateMask, "executed_at")
+			args.UpdateMask = append(args.UpdateMask, "execution_time")
+			args.UpdateMask = append(args.UpdateMask, "hash")
+			args.UpdateMask = append(args.UpdateMask, "partial_hashes")
+			return x.Gateway.ExecUpdateRevision(ctx, args)
+		}
+
+		args := &ExecInsertRevisionParams{}
+		args.SetRevision(revision)
+		return x.Gateway.ExecInsertRevision(ctx, args)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Update the migration parameters
+	params.Migration.Revision = revision
+	return nil
+}
+
+// PlanMigrationParams represents the parameters for planning a revision.
+type PlanMigrationParams struct {
+	// Migration contains the parameters for planning a migration.
+	Migration *Migration
+	// Baseline plans the revision to be recorded as applied without
+	// executing its statements.
+	Baseline bool
+	// AllowDestructive plans the destructive statements as allowed.
+	AllowDestructive bool
+}
+
+// PlanMigration returns the statements that ApplyMigration would execute
+// for the revision, without executing them.
+func (x *MigrationRepository) PlanMigration(ctx context.Context, params *PlanMigrationParams) (*MigrationPlan, error) {
+	plan := &MigrationPlan{}
+	plan.Revision = params.Migration.Revision
+
+	if params.Baseline {
+		revision, err := baseline(params.Migration)
 		if err != nil {
 			return nil, err
 		}
 
-		migration := &Migration{}
-		migration.Statements = strings.SplitAfter(string(data), ";")
-		migration.Revision = &Revision{}
-		migration.Revision.SetName(path)
-		migration.Revision.Total = len(migration.Statements)
+		plan.Revision = revision
+		plan.Baseline = true
+		return plan, nil
+	}
 
-		params := &GetRevisionParams{}
-		params.SetRevision(migration.Revision)
+	plan.AllowDestructive = params.AllowDestructive || params.Migration.Destructive
+	// classify the statements left to apply
+	findings := params.Migration.GetDestructiveChanges()
 
-		// load the revision
-		revision, err := x.Gateway.GetRevision(ctx, params)
-		switch {
-		case err == pgx.ErrNoRows:
-		// We are good to go because the revision does not exist
-		case err != nil:
-			return nil, err
-		default:
-			migration.Revision = revision
+	for _, stmt := range params.Migration.Statements[min(plan.Revision.Count, len(params.Migration.Statements)):] {
+		query := x.prepare(stmt)
+		if len(query) == 0 {
+			continue
 		}
 
-		collection = append(collection, migration)
+		item := &MigrationPlanStatement{
+			Pos:   stmt.Pos,
+			Query: query,
+			Async: isCreateIndex(stmt) && (x.Dialect != DialectPostgres || x.EmulateJobs),
+		}
+
+		for _, finding := range findings {
+			if finding.Pos.Offset >= stmt.Pos.Offset && finding.Pos.Offset < stmt.Pos.Offset+len(stmt.Text) {
+				item.Destructive = append(item.Destructive, finding)
+			}
+		}
+
+		plan.Statements = append(plan.Statements, item)
 	}
 
-	return collection, nil
+	return plan, nil
+}
+
+// RevertMigrationParams represents the parameters for reverting a revision.
+type RevertMigrationParams struct {
+	// Migration contains the parameters for reverting a migration.
+	Migration *Migration
+	// JobTimeout is the maximum time to wait for an asynchronous job. Zero means no timeout.
+	JobTimeout time.Duration
+}
+
+// RevertMigration executes the down statements of an applied revision. The
+// revision is pending again once all down statements have been executed.
+func (x *MigrationRepository) RevertMigration(ctx context.Context, params *RevertMigrationParams) error {
+	revision := params.Migration.Revision
+
+	if len(params.Migration.Down) == 0 {
+		return fmt.Errorf("revision %s: %w", revision.ID, ErrNoDownScript)
+	}
+
+	if revision.ExecutedAt.IsZero() {
+		return fmt.Errorf("revision %s is not applied", revision.ID)
+	}
+
+	var count int
+	if revision.RevertCount != nil {
+		count = *revision.RevertCount
+	}
+
+	ctx, cancel := x.guard(ctx)
+	defer cancel()
+
+	start := time.Now()
+	// Revert the statements one by one
+	for index := range params.Migration.Down {
+		if index+1 <= count {
+			continue
+		}
+
+		args := &ExecUpdateRevisionParams{}
+		// prepare the mask
+		args.UpdateMask = append(args.UpdateMask, "execution_time")
+
+		group := params.Migration.Down[index : index+1]
+
+		// The lock may have been taken over since the last statement
+		if err := context.Cause(ctx); err != nil {
+			return err
+		}
+
+		events, err := x.execGroup(ctx, revision, RevisionEventRevert, index, params.Migration.DownName, group, params.JobTimeout)
+		if err != nil {
+			return err
+		}
+
+		// The result of a statement interrupted by the loss of the lock is not recorded
+		if err := context.Cause(ctx); err != nil {
+			return err
+		}
+
+		if msg, query := failure(events); msg != "" {
+			// set the revision error
+			revision.Error = &msg
+			revision.ErrorStmt = &query
+		} else {
+			count = index + 1
+		}
+
+		if count == index+1 {
+			revision.RevertCount = &count
+			args.UpdateMask = append(args.UpdateMask, "revert_count")
+		} else {
+			args.UpdateMask = append(args.UpdateMask, "error")
+			args.UpdateMask = append(args.UpdateMask, "error_stmt")
+		}
+
+		revision.ExecutionTime = time.Since(start)
+		args.SetRevision(revision)
+
+		if err := x.updateRevision(ctx, args); err != nil {
+			return err
+		}
+
+		// Update the migration parameters
+		params.Migration.Revision = revision
+		// The progress is written first, the audit trail follows it
+		if err := x.record(ctx, events); err != nil {
+			return err
+		}
+
+		// Stop processing if there is an error
+		if count != index+1 {
+			return nil
+		}
+	}
+
+	revertedAt := time.Now().UTC()
+	// The revision becomes pending again
+	revision.Count = 0
+	revision.RevertCount = nil
+	revision.RevertedAt = &revertedAt
+	revision.Error = nil
+	revision.ErrorStmt = nil
+	revision.ExecutedAt = time.Time{}
+	revision.ExecutionTime = time.Since(start)
+	revision.Hash = nil
+	revision.PartialHashes = nil
+
+	args := &ExecUpdateRevisionParams{}
+	args.SetRevision(revision)
+	// prepare the mask
+	args.UpdateMask = append(args.UpdateMask, "count")
+	args.UpdateMask = append(args.UpdateMask, "revert_count")
+	args.UpdateMask = append(args.UpdateMask, "reverted_at")
+	args.UpdateMask = append(args.UpdateMask, "error")
+	args.UpdateMask = append(args.UpdateMask, "error_stmt")
+	args.UpdateMask = append(args.UpdateMask, "executed_at")
+	args.UpdateMask = append(args.UpdateMask, "execution_time")
+	args.UpdateMask = append(args.UpdateMask, "hash")
+	args.UpdateMask = append(args.UpdateMask, "partial_hashes")
+
+	if err := x.updateRevision(ctx, args); err != nil {
+		return err
+	}
+
+	// Update the migration parameters
+	params.Migration.Revision = revision
+	return nil
+}
+
+// ResolveAction represents the action that resolves a failed revision.
+type ResolveAction string
+
+const (
+	// ResolveRetry clears the error, so the failed statement is executed again.
+	ResolveRetry ResolveAction = "retry"
+	// ResolveSkipStatement clears the error and counts the failed statement
+	// as executed, so the execution resumes after it.
+	ResolveSkipStatement ResolveAction = "skip-statement"
+	// ResolveReset deletes the revision, so all its statements are executed again.
+	ResolveReset ResolveAction = "reset"
+)
+
+// ResolveMigrationParams represents the parameters for resolving a revision.
+type ResolveMigrationParams struct {
+	// Migration contains the parameters for resolving a migration.
+	Migration *Migration
+	// Action is the action that resolves the revision.
+	Action ResolveAction
+}
+
+// ResolveMigration repairs a failed revision with the given action. Each
+// action is recorded in the aurora_schema_revision_events table.
+func (x *MigrationRepository) ResolveMigration(ctx context.Context, params *ResolveMigrationParams) error {
+	revision := params.Migration.Revision
+
+	if revision.ExecutedAt.IsZero() && revision.RevertedAt == nil {
+		return fmt.Errorf("revision %s is not applied", revision.ID)
+	}
+
+	switch params.Action {
+	case ResolveRetry, ResolveSkipStatement:
+		if revision.Error == nil {
+			return fmt.Errorf("revision %s has no error to resolve", revision.ID)
+		}
+
+		if params.Action == ResolveSkipStatement && revision.Count >= len(params.Migration.Statements) {
+			return fmt.Errorf("revision %s has no statement left to skip", revision.ID)
+		}
+	case ResolveReset:
+	default:
+		return fmt.Errorf("unknown resolve action %q", params.Action)
+	}
+
+	index := revision.Count
+
+	event := &RevisionEvent{}
+	event.StatementIndex = &index
+	event.Statement = revision.ErrorStmt
+	event.Error = revision.Error
+	event.StartedAt = time.Now().UTC()
+	// record the action in the audit trail
+	if err := x.start(ctx, revision, string(params.Action), event); err != nil {
+		return err
+	}
+
+	switch params.Action {
+	case ResolveRetry, ResolveSkipStatement:
+		args := &ExecUpdateRevisionParams{}
+		// prepare the mask
+		args.UpdateMask = append(args.UpdateMask, "error")
+		args.UpdateMask = append(args.UpdateMask, "error_stmt")
+
+		if params.Action == ResolveSkipStatement {
+			statements := params.Migration.Statements
+
+			revision.Count++
+			revision.PartialHashes = checksums(statements[:revision.Count], revision.PartialHashes)
+			revision.Hash = &params.Migration.Hash
+
+			args.UpdateMask = append(args.UpdateMask, "count")
+			args.UpdateMask = append(args.UpdateMask, "hash")
+			args.UpdateMask = append(args.UpdateMask, "partial_hashes")
+		}
+
+		revision.Error = nil
+		revision.ErrorStmt = nil
+		args.SetRevision(revision)
+
+		if err := x.updateRevision(ctx, args); err != nil {
+			return err
+		}
+	case ResolveReset:
+		args := &ExecDeleteRevisionParams{}
+		args.SetRevision(revision)
+
+		err := x.Retry.Do(ctx, func() error {
+			return x.Gateway.ExecDeleteRevision(ctx, args)
+		})
+		if err != nil {
+			return err
+		}
+
+		// The revision is pending again
+		revision = &Revision{}
+		revision.ID = params.Migration.Revision.ID
+		revision.Description = params.Migration.Revision.Description
+		revision.Total = len(params.Migration.Statements)
+	}
+
+	// Update the migration parameters
+	params.Migration.Revision = revision
+
+	finishedAt := time.Now().UTC()
+	event.FinishedAt = &finishedAt
+	event.Outcome = RevisionEventSucceeded
+	// record the outcome of the action
+	return x.record(ctx, []*RevisionEvent{event})
+}
+
+// ListMigrationEventsParams represents the parameters for listing the audit trail.
+type ListMigrationEventsParams struct {
+	// Version limits the events to the given revision. Empty lists all events.
+	Version string
+}
+
+// ListMigrationEvents returns the audit trail of the revisions, oldest first.
+func (x *MigrationRepository) ListMigrationEvents(ctx context.Context, params *ListMigrationEventsParams) ([]*RevisionEvent, error) {
+	args := &ListRevisionEventsParams{}
+	if params.Version != "" {
+		args.RevisionID = &params.Version
+	}
+
+	events, err := x.Gateway.ListRevisionEvents(ctx, args)
+	if IsErrorCode(err, ErrCodeUndefinedTable) {
+		// No migration was applied to the database yet
+		return nil, nil
+	}
+
+	return events, err
+}
+
+// CreateMigrationParams represents the parameters for creating a migration file.
+type CreateMigrationParams struct {
+	// Description is the description of the migration.
+	Description string
+	// Scheme is the scheme used to generate the revision ID.
+	Scheme string
+}
+
+// CreateMigration creates an empty migration file. The revision ID must sort
+// after all applied revisions, so the new file is not skipped by apply.
+func (x *MigrationRepository) CreateMigration(ctx context.Context, params *CreateMigrationParams) (*Migration, error) {
+	writer, ok := x.FileSystem.(WriteFileFS)
+	if !ok {
+		return nil, fmt.Errorf("the migration directory is read-only")
+	}
+
+	migrations, err := x.ListMigrations(ctx, &ListMigrationsParams{})
+	if err != nil {
+		return nil, err
+	}
+
+	revision := &Revision{}
+	revision.Description = strings.Trim(descriptionRegexp.ReplaceAllString(strings.ToLower(params.Description), "_"), "_")
+
+	if revision.Description == "" {
+		return nil, fmt.Errorf("the migration description %q is invalid", params.Description)
+	}
+
+	switch params.Scheme {
+	case RevisionSchemeTimestamp, "":
+		revision.ID = time.Now().UTC().Format("20060102150405")
+	case RevisionSchemeSequential:
+		var (
+			last  int
+			width = 6
+		)
+
+		for _, migration := range migrations {
+			id, err := strconv.Atoi(migration.Revision.ID)
+			if err != nil {
+				return nil, fmt.Errorf("revision %s is not sequential", migration.Revision.ID)
+			}
+
+			last = max(last, id)
+			width = max(width, len(migration.Revision.ID))
+		}
+
+		revision.ID = fmt.Sprintf("%0*d", width, last+1)
+	default:
+		return nil, fmt.Errorf("the revision scheme %q is not supported", params.Scheme)
+	}
+
+	for _, migration := range migrations {
+		switch {
+		case migration.Revision.ID == revision.ID:
+			return nil, fmt.Errorf("revision %s already exists", revision.ID)
+		case migration.Revision.ExecutedAt.IsZero():
+			continue
+		case migration.Revision.ID > revision.ID:
+			return nil, fmt.Errorf("revision %s sorts before the applied revision %s", revision.ID, migration.Revision.ID)
+		}
+	}
+
+	if err := writer.WriteFile(revision.GetName(), nil, 0o644); err != nil {
+		return nil, err
+	}
+
+	// Keep the integrity file in sync with the new file
+	switch _, err := fs.ReadFile(x.FileSystem, HashFileName); {
+	case errors.Is(err, fs.ErrNotExist):
+	// We are good to go because the directory has no integrity file
+	case err != nil:
+		return nil, err
+	default:
+		if _, err := x.HashMigrations(ctx, &HashMigrationsParams{}); err != nil {
+			return nil, err
+		}
+	}
+
+	migration := &Migration{}
+	migration.Revision = revision
+	// done!
+	return migration, nil
+}
+
+// HashMigrationsParams represents the parameters for hashing the migration directory.
+type HashMigrationsParams struct{}
+
+// HashMigrations writes the integrity file of the migration directory.
+func (x *MigrationRepository) HashMigrations(ctx context.Context, _ *HashMigrationsParams) (HashFile, error) {
+	writer, ok := x.FileSystem.(WriteFileFS)
+	if !ok {
+		return nil, fmt.Errorf("the migration directory is read-only")
+	}
+
+	file, err := NewHashFile(x.FileSystem)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := file.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := writer.WriteFile(HashFileName, data, 0o644); err != nil {
+		return nil, err
+	}
+
+	return file, nil
+}
+
+// VerifyMigrationsParams represents the parameters for verifying the migration directory.
+type VerifyMigrationsParams struct{}
+
+// VerifyMigrations verifies that the migration directory matches its
+// integrity file. Directories without integrity file are not verified.
+func (x *MigrationRepository) VerifyMigrations(ctx context.Context, _ *VerifyMigrationsParams) error {
+	data, err := fs.ReadFile(x.FileSystem, HashFileName)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return nil
+	case err != nil:
+		return err
+	}
+
+	var expected HashFile
+	if err := expected.UnmarshalText(data); err != nil {
+		return err
+	}
+
+	actual, err := NewHashFile(x.FileSystem)
+	if err != nil {
+		return err
+	}
+
+	if err := expected.Compare(actual); err != nil {
+		return fmt.Errorf("%s: %w", HashFileName, err)
+	}
+
+	return nil
+}
+
+// LintMigrationsParams represents the parameters for linting the migration files.
+type LintMigrationsParams struct {
+	// Analyzers are the analyzers to run. Empty runs the default analyzers.
+	Analyzers []lint.Analyzer
+}
+
+// LintMigrations checks the migration files, including the down files,
+// without connecting to the database.
+func (x *MigrationRepository) LintMigrations(ctx context.Context, params *LintMigrationsParams) (collection []*lint.Finding, _ error) {
+	matches, err := fs.Glob(x.FileSystem, "*.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, path := range sort.StringSlice(matches) {
+		statements, err := x.readStatements(path)
+		if err != nil {
+			return nil, err
+		}
+
+		file := &lint.File{}
+		file.Name = path
+		file.Statements = statements
+
+		collection = append(collection, lint.Lint(file, params.Analyzers...)...)
+	}
+
+	return collection, nil
+}
+
+// ListMigrationsParams represents the parameters for listing migrations.
+type ListMigrationsParams struct{}
+
+// ListMigrations lists all revisions in the repository.
+func (x *MigrationRepository) ListMigrations(ctx context.Context, _ *ListMigrationsParams) (collection []*Migration, _ error) {
+	matches, err := fs.Glob(x.FileSystem, "*.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, path := range sort.StringSlice(matches) {
+		// The down files are loaded with their revision file
+		if strings.HasSuffix(path, ".down.sql") {
+			continue
+		}
+
+		data, err := fs.ReadFile(x.FileSystem, path)
+		if err != nil {
+			return nil, err
+		}
+
+		statements, err := split(path, data)
+		if err != nil {
+			return nil, err
+		}
+
+		migration := &Migration{}
+		migration.Hash = Checksum(data)
+		migration.Statements = statements
+		// The directives at the top of the file apply to the whole migration
+		migration.Parallel = len(statements) > 0 && statements[0].HasDirective("+parallel")
+		migration.Destructive = len(statements) > 0 && statements[0].HasDirective("+destructive")
+		// The statements after the '-- +down' directive revert the revision
+		for index, stmt := range statements {
+			if stmt.HasDirective("+down") {
+				migration.Statements = statements[:index]
+				migration.Down = statements[index:]
+				migration.DownName = path
+				break
+			}
+		}
+
+		if name := strings.TrimSuffix(path, ".sql") + ".down.sql"; slices.Contains(matches, name) {
+			if migration.Down != nil {
+				return nil, fmt.Errorf("%s: the down statements are defined in both %s and %s", path, path, name)
+			}
+
+			if migration.Down, err = x.readStatements(name); err != nil {
+				return nil, err
+			}
+
+			migration.DownName = name
+		}
+
+		migration.Revision = &Revision{}
+		migration.Revision.SetName(path)
+		migration.Revision.Total = len(migration.Statements)
+
+		params := &GetRevisionParams{}
+		params.SetRevision(migration.Revision)
+
+		// load the revision
+		revision, err := x.Gateway.GetRevision(ctx, params)
+		if IsErrorCode(err, ErrCodeUndefinedColumn) {
+			// The table was created by an older version, only the commands
+			// that write upgrade it
+			revision, err = x.getLegacyRevision(ctx, params.ID)
+		}
+
+		switch {
+		case err == pgx.ErrNoRows:
+		// We are good to go because the revision does not exist
+		case IsErrorCode(err, ErrCodeUndefinedTable):
+		// No migration was applied to the database yet
+		case err != nil:
+			return nil, err
+		default:
+			migration.Revision = revision
+		}
+
+		collection = append(collection, migration)
+	}
+
+	return collection, nil
+}
+
+// getLegacyRevision returns the revision from the table of an older version.
+// Such a revision has no checksum and was never reverted.
+func (x *MigrationRepository) getLegacyRevision(ctx context.Context, id string) (*Revision, error) {
+	args := &GetLegacyRevisionParams{}
+	args.ID = id
+
+	row, err := x.Gateway.GetLegacyRevision(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+
+	revision := &Revision{}
+	revision.ID = row.ID
+	revision.Description = row.Description
+	revision.Total = row.Total
+	revision.Count = row.Count
+	revision.Error = row.Error
+	revision.ErrorStmt = row.ErrorStmt
+	revision.ExecutedAt = row.ExecutedAt
+	revision.ExecutionTime = row.ExecutionTime
+
+	return revision, nil
+}
+
+// readStatements reads and splits the statements of a migration file.
+func (x *MigrationRepository) readStatements(path string) ([]*parser.Statement, error) {
+	// read the revision content
+	data, err := fs.ReadFile(x.FileSystem, path)
+	if err != nil {
+		return nil, err
+	}
+
+	return split(path, data)
+}
+
+// updateRevision updates the revision and retries the conflicts.
+func (x *MigrationRepository) updateRevision(ctx context.Context, args *ExecUpdateRevisionParams) error {
+	return x.Retry.Do(ctx, func() error {
+		return x.Gateway.ExecUpdateRevision(ctx, args)
+	})
+}
+
+// split splits the content of a migration file into statements.
+func split(path string, data []byte) ([]*parser.Statement, error) {
+	statements, err := parser.Split(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", path, err)
+	}
+
+	return statements, nil
+}
+
+// submit executes a query and returns the ID of the asynchronous job it
+// creates, or nil if the query is synchronous.
+func (x *MigrationRepository) submit(ctx context.Context, query string) (*string, error) {
+	var jid *string
+	err := x.Retry.Do(ctx, func() error {
+		row := x.Gateway.Database().QueryRow(ctx, query)
+		// Some queries returns a job id because they are asynchronous
+		var id string
+		switch err := row.Scan(&id); {
+		case err == pgx.ErrNoRows:
+			jid = nil
+		case err != nil:
+			return err
+		default:
+			jid = &id
+		}
+
+		return nil
+	})
+
+	return jid, err
+}
+
+// wait waits for the asynchronous job to complete and returns its failure, if any.
+func (x *MigrationRepository) wait(ctx context.Context, jid string, timeout time.Duration) error {
+	repository := &JobRepository{
+		Gateway: x.Gateway,
+	}
+
+	args := &WaitJobParams{}
+	args.JobID = jid
+	args.Timeout = timeout
+	// Wait for the job to complete
+	job, err := repository.WaitJob(ctx, args)
+	switch {
+	case err == pgx.ErrNoRows:
+		return nil
+	case err != nil:
+		return err
+	case job.Status == "failed" && job.Details != nil:
+		return errors.New(*job.Details)
+	case job.Status == "failed":
+		return fmt.Errorf("job %s failed", job.JobID)
+	default:
+		return nil
+	}
+}
+
+// emulates reports whether a job is emulated for the statement.
+func (x *MigrationRepository) emulates(stmt *parser.Statement) bool {
+	return x.EmulateJobs && x.Dialect == DialectPostgres && isCreateIndex(stmt)
+}
+
+// emulate records a completed job in sys.jobs and returns its id.
+func (x *MigrationRepository) emulate(ctx context.Context) (*string, error) {
+	args := &ExecInsertJobParams{}
+	args.JobID = uuid.New().String()
+	args.Status = "completed"
+
+	err := x.Retry.Do(ctx, func() error {
+		return x.Gateway.ExecInsertJob(ctx, args)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &args.JobID, nil
+}
+
+// groupIndexes returns the number of consecutive index statements at the
+// beginning of the given statements, at least one.
+func groupIndexes(statements []*parser.Statement) int {
+	for index, stmt := range statements {
+		if !isCreateIndex(stmt) {
+			return max(index, 1)
+		}
+	}
+
+	return len(statements)
+}
+
+// isCreateIndex reports whether the statement creates an index.
+func isCreateIndex(stmt *parser.Statement) bool {
+	var keywords []*parser.Token
+
+	for _, token := range stmt.Tokens {
+		if token.Kind == parser.TokenComment {
+			continue
+		}
+
+		if keywords = append(keywords, token); len(keywords) == 3 {
+			break
+		}
+	}
+
+	switch {
+	case len(keywords) < 2 || !keywords[0].Is("CREATE"):
+		return false
+	case keywords[1].Is("INDEX"):
+		return true
+	default:
+		return len(keywords) == 3 && keywords[1].Is("UNIQUE") && keywords[2].Is("INDEX")
+	}
+}
+
+// baseline returns the revision of the migration with all statements counted.
+func baseline(migration *Migration) (*Revision, error) {
+	if !migration.Revision.ExecutedAt.IsZero() {
+		return nil, fmt.Errorf("revision %s is already applied", migration.Revision.ID)
+	}
+
+	revision := &Revision{}
+	revision.ID = migration.Revision.ID
+	revision.Description = migration.Revision.Description
+	revision.Total = len(migration.Statements)
+	revision.Count = len(migration.Statements)
+	revision.ExecutedAt = time.Now().UTC()
+	revision.Hash = &migration.Hash
+	revision.PartialHashes = checksums(migration.Statements, nil)
+	revision.RevertedAt = migration.Revision.RevertedAt
+	return revision, nil
+}
+
+// operator returns the identity of the user running the tool.
+func operator() *string {
+	name := os.Getenv("USER")
+	if current, err := user.Current(); err == nil {
+		name = current.Username
+	}
+
+	if hostname, err := os.Hostname(); err == nil {
+		name = name + "@" + hostname
+	}
+
+	return &name
+}
+
+// checksums returns the checksums of the given statements. The recorded
+// checksums are kept, so a drift of executed statements remains visible.
+func checksums(statements []*parser.Statement, recorded Hashes) Hashes {
+	collection := make(Hashes, 0, len(statements))
+	collection = append(collection, recorded[:min(len(recorded), len(statements))]...)
+
+	for _, stmt := range statements[len(collection):] {
+		collection = append(collection, Checksum([]byte(stmt.Text)))
+	}
+
+	return collection
+}
+
+// prepare returns the query of the statement ready to be executed. Aurora
+// DSQL builds the indexes with ASYNC, and PostgreSQL with CONCURRENTLY.
+func (x *MigrationRepository) prepare(stmt *parser.Statement) string {
+	query := stmt.StripComments()
+
+	switch x.Dialect {
+	case DialectPostgres:
+		query = asyncIndexRegexp.ReplaceAllString(query, "CREATE ${1}INDEX CONCURRENTLY")
+	default:
+		query = createIndexRegexp.ReplaceAllString(query, "CREATE ${1}INDEX ASYNC")
+	}
+
+	return strings.TrimSpace(query)
+}
+
+// errorf returns the error message prefixed with the file position of the statement.
+func errorf(name string, stmt *parser.Statement, msg string) string {
+	return fmt.Sprintf("%s:%s: %s", name, stmt.Pos, msg)
 }
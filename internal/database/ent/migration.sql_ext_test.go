@@ -1,9 +1,15 @@
 package ent_test
 
 import (
+	"errors"
 	"fmt"
+	iofs "io/fs"
+	"time"
 
 	"github.com/aws-contrib/aurora/internal/database/ent"
+	"github.com/aws-contrib/aurora/internal/database/ent/lint"
+	"github.com/aws-contrib/aurora/internal/database/ent/parser"
+	"github.com/jackc/pgx/v5"
 
 	. "github.com/aws-contrib/aurora/internal/database/ent/fake"
 	. "github.com/onsi/ginkgo/v2"
@@ -20,100 +26,1593 @@ var _ = Describe("MigrationRepository", func() {
 		}
 	})
 
+	Describe("InitMigrations", func() {
+		It("creates the migration tables", func(ctx SpecContext) {
+			Expect(repository.InitMigrations(ctx, &ent.InitMigrationsParams{})).To(Succeed())
+
+			gateway := repository.Gateway.(*FakeGateway)
+			Expect(gateway.CreateTableLocksCallCount()).To(Equal(1))
+			Expect(gateway.AlterTableLocksAddExpiresAtCallCount()).To(Equal(1))
+			Expect(gateway.CreateTableRevisionsCallCount()).To(Equal(1))
+			Expect(gateway.AlterTableRevisionsAddPartialHashesCallCount()).To(Equal(1))
+			Expect(gateway.CreateTableRevisionEventsCallCount()).To(Equal(1))
+			Expect(gateway.CreateTableJobsCallCount()).To(Equal(0))
+		})
+
+		When("the jobs are emulated", func() {
+			BeforeEach(func() {
+				repository.Dialect = ent.DialectPostgres
+				repository.EmulateJobs = true
+			})
+
+			It("creates the jobs table", func(ctx SpecContext) {
+				Expect(repository.InitMigrations(ctx, &ent.InitMigrationsParams{})).To(Succeed())
+
+				gateway := repository.Gateway.(*FakeGateway)
+				Expect(gateway.CreateSchemaSysCallCount()).To(Equal(1))
+				Expect(gateway.CreateTableJobsCallCount()).To(Equal(1))
+			})
+		})
+
+		When("a statement conflicts with another process", func() {
+			BeforeEach(func() {
+				repository.Retry = &ent.RetryPolicy{Limit: 1, Backoff: &ent.Backoff{}}
+
+				gateway := repository.Gateway.(*FakeGateway)
+				gateway.CreateTableRevisionsReturnsOnCall(0, &ent.Error{Code: ent.ErrCodeSerializationFailure})
+			})
+
+			It("retries the statement", func(ctx SpecContext) {
+				Expect(repository.InitMigrations(ctx, &ent.InitMigrationsParams{})).To(Succeed())
+
+				gateway := repository.Gateway.(*FakeGateway)
+				Expect(gateway.CreateTableLocksCallCount()).To(Equal(1))
+				Expect(gateway.CreateTableRevisionsCallCount()).To(Equal(2))
+			})
+		})
+
+		When("a statement fails", func() {
+			BeforeEach(func() {
+				gateway := repository.Gateway.(*FakeGateway)
+				gateway.CreateTableRevisionsReturns(fmt.Errorf("oh no"))
+			})
+
+			It("returns an error", func(ctx SpecContext) {
+				Expect(repository.InitMigrations(ctx, &ent.InitMigrationsParams{})).To(MatchError("oh no"))
+
+				gateway := repository.Gateway.(*FakeGateway)
+				Expect(gateway.CreateTableRevisionEventsCallCount()).To(Equal(0))
+			})
+		})
+	})
+
+	Describe("LockMigration", func() {
+		var params *ent.LockMigrationParams
+
+		BeforeEach(func() {
+			params = &ent.LockMigrationParams{}
+			params.Timeout = time.Second
+			params.TTL = 30 * time.Millisecond
+			params.Version = "v1.0.0"
+		})
+
+		AfterEach(func(ctx SpecContext) {
+			_ = repository.UnlockMigration(ctx)
+		})
+
+		It("locks the migration", func(ctx SpecContext) {
+			Expect(repository.LockMigration(ctx, params)).To(Succeed())
+
+			gateway := repository.Gateway.(*FakeGateway)
+			Expect(gateway.ExecInsertLockCallCount()).To(Equal(1))
+
+			_, args := gateway.ExecInsertLockArgsForCall(0)
+			Expect(args.ID).To(Equal(ent.MigrationLock.String()))
+			Expect(args.Owner).NotTo(BeNil())
+			Expect(args.Hostname).NotTo(BeNil())
+			Expect(args.Pid).NotTo(BeNil())
+			Expect(args.Version).To(HaveValue(Equal("v1.0.0")))
+			Expect(args.ExpiresAt).To(HaveValue(BeTemporally("~", args.CreatedAt.Add(params.TTL))))
+		})
+
+		It("refreshes the lock", func(ctx SpecContext) {
+			Expect(repository.LockMigration(ctx, params)).To(Succeed())
+
+			gateway := repository.Gateway.(*FakeGateway)
+			Eventually(gateway.ExecRefreshLockCallCount).Should(BeNumerically(">", 0))
+		})
+
+		When("the lock is held by another process", func() {
+			BeforeEach(func() {
+				gateway := repository.Gateway.(*FakeGateway)
+				gateway.ExecInsertLockReturns(&ent.Error{Code: ent.ErrCodeUniqueViolation})
+			})
+
+			When("the lock has expired", func() {
+				BeforeEach(func() {
+					expiresAt := time.Now().Add(-time.Minute)
+
+					entity := NewFakeLock()
+					entity.ExpiresAt = &expiresAt
+
+					gateway := repository.Gateway.(*FakeGateway)
+					gateway.GetLockReturns(entity, nil)
+				})
+
+				It("takes the lock over", func(ctx SpecContext) {
+					Expect(repository.LockMigration(ctx, params)).To(Succeed())
+
+					gateway := repository.Gateway.(*FakeGateway)
+					Expect(gateway.ExecTakeoverLockCallCount()).To(Equal(1))
+
+					_, args := gateway.ExecTakeoverLockArgsForCall(0)
+					Expect(args.ObservedExpiresAt).NotTo(BeNil())
+					Expect(args.Owner).NotTo(Equal(args.ObservedOwner))
+				})
+
+				When("another process takes the lock over first", func() {
+					BeforeEach(func() {
+						params.Timeout = 0
+
+						gateway := repository.Gateway.(*FakeGateway)
+						gateway.ExecTakeoverLockReturns(0, &ent.Error{Code: ent.ErrCodeSerializationFailure})
+					})
+
+					It("returns an error", func(ctx SpecContext) {
+						Expect(repository.LockMigration(ctx, params)).To(MatchError("timeout while waiting for lock"))
+					})
+				})
+			})
+
+			When("the lock has no expiration", func() {
+				BeforeEach(func() {
+					params.Timeout = 0
+
+					entity := NewFakeLock()
+					entity.ExpiresAt = nil
+					entity.CreatedAt = time.Now().Add(-time.Hour)
+
+					gateway := repository.Gateway.(*FakeGateway)
+					gateway.GetLockReturns(entity, nil)
+				})
+
+				It("does not take the lock over", func(ctx SpecContext) {
+					Expect(repository.LockMigration(ctx, params)).To(MatchError("timeout while waiting for lock"))
+
+					gateway := repository.Gateway.(*FakeGateway)
+					Expect(gateway.ExecTakeoverLockCallCount()).To(BeZero())
+				})
+			})
+
+			When("the lock has not expired", func() {
+				BeforeEach(func() {
+					params.Timeout = 0
+
+					gateway := repository.Gateway.(*FakeGateway)
+					gateway.ExecTakeoverLockReturns(0, nil)
+				})
+
+				It("returns an error", func(ctx SpecContext) {
+					Expect(repository.LockMigration(ctx, params)).To(MatchError("timeout while waiting for lock"))
+				})
+			})
+		})
+
+		When("the ttl is negative", func() {
+			BeforeEach(func() {
+				params.TTL = -time.Second
+			})
+
+			It("returns an error", func(ctx SpecContext) {
+				Expect(repository.LockMigration(ctx, params)).To(MatchError("lock ttl must be positive, got -1s"))
+
+				gateway := repository.Gateway.(*FakeGateway)
+				Expect(gateway.ExecInsertLockCallCount()).To(BeZero())
+			})
+		})
+
+		When("the gateway fails", func() {
+			BeforeEach(func() {
+				gateway := repository.Gateway.(*FakeGateway)
+				gateway.ExecInsertLockReturns(fmt.Errorf("oh no"))
+			})
+
+			It("returns an error", func(ctx SpecContext) {
+				Expect(repository.LockMigration(ctx, params)).To(MatchError("oh no"))
+			})
+		})
+	})
+
+	Describe("UnlockMigration", func() {
+		It("unlocks the migration", func(ctx SpecContext) {
+			Expect(repository.UnlockMigration(ctx)).To(Succeed())
+
+			gateway := repository.Gateway.(*FakeGateway)
+			Expect(gateway.ExecDeleteLockCallCount()).To(Equal(1))
+		})
+
+		When("the lock is held", func() {
+			BeforeEach(func(ctx SpecContext) {
+				params := &ent.LockMigrationParams{}
+				params.TTL = 30 * time.Millisecond
+				Expect(repository.LockMigration(ctx, params)).To(Succeed())
+			})
+
+			It("releases the lock of the owner", func(ctx SpecContext) {
+				Expect(repository.UnlockMigration(ctx)).To(Succeed())
+
+				gateway := repository.Gateway.(*FakeGateway)
+				Expect(gateway.ExecDeleteLockCallCount()).To(BeZero())
+				Expect(gateway.ExecReleaseLockCallCount()).To(Equal(1))
+
+				_, args := gateway.ExecReleaseLockArgsForCall(0)
+				Expect(args.Owner).NotTo(BeNil())
+			})
+
+			When("the lock was taken over", func() {
+				BeforeEach(func() {
+					gateway := repository.Gateway.(*FakeGateway)
+					gateway.ExecRefreshLockReturns(0, nil)
+					Eventually(gateway.ExecRefreshLockCallCount).Should(BeNumerically(">", 0))
+				})
+
+				It("returns an error", func(ctx SpecContext) {
+					Expect(repository.UnlockMigration(ctx)).To(MatchError(ent.ErrLockLost))
+				})
+			})
+
+			When("the lock could not be refreshed before it expired", func() {
+				BeforeEach(func() {
+					gateway := repository.Gateway.(*FakeGateway)
+					gateway.ExecRefreshLockReturns(0, fmt.Errorf("oh no"))
+					time.Sleep(60 * time.Millisecond)
+				})
+
+				It("returns an error", func(ctx SpecContext) {
+					Expect(repository.UnlockMigration(ctx)).To(MatchError(ent.ErrLockLost))
+				})
+			})
+		})
+	})
+
+	Describe("ReleaseMigrationLock", func() {
+		var params *ent.ReleaseMigrationLockParams
+
+		BeforeEach(func() {
+			params = &ent.ReleaseMigrationLockParams{}
+			params.Lock = &ent.Lock{}
+			params.Lock.ID = ent.MigrationLock.String()
+			params.Lock.CreatedAt = time.Now().UTC()
+
+			gateway := repository.Gateway.(*FakeGateway)
+			gateway.ExecReleaseObservedLockReturns(1, nil)
+		})
+
+		It("releases the lock that was read", func(ctx SpecContext) {
+			Expect(repository.ReleaseMigrationLock(ctx, params)).To(Succeed())
+
+			gateway := repository.Gateway.(*FakeGateway)
+			Expect(gateway.ExecDeleteLockCallCount()).To(BeZero())
+			Expect(gateway.ExecReleaseObservedLockCallCount()).To(Equal(1))
+
+			_, args := gateway.ExecReleaseObservedLockArgsForCall(0)
+			Expect(args.ID).To(Equal(ent.MigrationLock.String()))
+			Expect(args.ObservedCreatedAt).To(Equal(params.Lock.CreatedAt))
+		})
+
+		When("the lock changed", func() {
+			BeforeEach(func() {
+				gateway := repository.Gateway.(*FakeGateway)
+				gateway.ExecReleaseObservedLockReturns(0, nil)
+			})
+
+			It("returns an error", func(ctx SpecContext) {
+				Expect(repository.ReleaseMigrationLock(ctx, params)).To(MatchError(ent.ErrLockChanged))
+			})
+		})
+
+		When("the gateway fails", func() {
+			BeforeEach(func() {
+				gateway := repository.Gateway.(*FakeGateway)
+				gateway.ExecReleaseObservedLockReturns(0, fmt.Errorf("oh no"))
+			})
+
+			It("returns an error", func(ctx SpecContext) {
+				Expect(repository.ReleaseMigrationLock(ctx, params)).To(MatchError("oh no"))
+			})
+		})
+	})
+
+	Describe("GetMigrationLock", func() {
+		It("returns the lock", func(ctx SpecContext) {
+			lock, err := repository.GetMigrationLock(ctx, &ent.GetMigrationLockParams{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(lock).NotTo(BeNil())
+
+			gateway := repository.Gateway.(*FakeGateway)
+			_, args := gateway.GetLockArgsForCall(0)
+			Expect(args.ID).To(Equal(ent.MigrationLock.String()))
+		})
+
+		When("the lock is not held", func() {
+			BeforeEach(func() {
+				gateway := repository.Gateway.(*FakeGateway)
+				gateway.GetLockReturns(nil, ent.ErrNoRows)
+			})
+
+			It("returns an error", func(ctx SpecContext) {
+				_, err := repository.GetMigrationLock(ctx, &ent.GetMigrationLockParams{})
+				Expect(ent.IsErrorNotFound(err)).To(BeTrue())
+			})
+		})
+
+		When("the lock table does not exist", func() {
+			BeforeEach(func() {
+				gateway := repository.Gateway.(*FakeGateway)
+				gateway.GetLockReturns(nil, &ent.Error{Code: ent.ErrCodeUndefinedTable})
+			})
+
+			It("returns an error", func(ctx SpecContext) {
+				_, err := repository.GetMigrationLock(ctx, &ent.GetMigrationLockParams{})
+				Expect(ent.IsErrorNotFound(err)).To(BeTrue())
+			})
+		})
+
+		When("the lock table was created by an older version", func() {
+			var createdAt time.Time
+
+			BeforeEach(func() {
+				createdAt = time.Now().UTC()
+
+				gateway := repository.Gateway.(*FakeGateway)
+				gateway.GetLockReturns(nil, &ent.Error{Code: ent.ErrCodeUndefinedColumn})
+				gateway.GetLegacyLockReturns(&ent.GetLegacyLockRow{
+					ID:        ent.MigrationLock.String(),
+					CreatedAt: createdAt,
+				}, nil)
+			})
+
+			It("returns the lock", func(ctx SpecContext) {
+				lock, err := repository.GetMigrationLock(ctx, &ent.GetMigrationLockParams{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(lock.ID).To(Equal(ent.MigrationLock.String()))
+				Expect(lock.CreatedAt).To(Equal(createdAt))
+				Expect(lock.Owner).To(BeNil())
+				Expect(lock.ExpiresAt).To(BeNil())
+
+				gateway := repository.Gateway.(*FakeGateway)
+				_, args := gateway.GetLegacyLockArgsForCall(0)
+				Expect(args.ID).To(Equal(ent.MigrationLock.String()))
+			})
+
+			When("the lock is not held", func() {
+				BeforeEach(func() {
+					gateway := repository.Gateway.(*FakeGateway)
+					gateway.GetLegacyLockReturns(nil, ent.ErrNoRows)
+				})
+
+				It("returns an error", func(ctx SpecContext) {
+					_, err := repository.GetMigrationLock(ctx, &ent.GetMigrationLockParams{})
+					Expect(ent.IsErrorNotFound(err)).To(BeTrue())
+				})
+			})
+		})
+	})
+
 	Describe("ApplyMigration", func() {
 		var params *ent.ApplyMigrationParams
 
 		BeforeEach(func() {
-			params = &ent.ApplyMigrationParams{}
-			params.Migration = NewFakeMigration()
+			params = &ent.ApplyMigrationParams{}
+			params.Migration = NewFakeMigration()
+		})
+
+		It("applies a revision", func(ctx SpecContext) {
+			Expect(repository.ApplyMigration(ctx, params)).To(Succeed())
+		})
+
+		It("records the checksums", func(ctx SpecContext) {
+			Expect(repository.ApplyMigration(ctx, params)).To(Succeed())
+			Expect(params.Migration.Revision.Hash).To(HaveValue(Equal(params.Migration.Hash)))
+			Expect(params.Migration.Revision.PartialHashes).To(HaveLen(1))
+			Expect(params.Migration.HasDrift()).To(BeFalse())
+
+			gateway := repository.Gateway.(*FakeGateway)
+			_, args := gateway.ExecUpdateRevisionArgsForCall(0)
+			Expect(args.UpdateMask).To(ContainElements("hash", "partial_hashes"))
+		})
+
+		It("records the attempts", func(ctx SpecContext) {
+			repository.Version = "v1.0.0"
+			Expect(repository.ApplyMigration(ctx, params)).To(Succeed())
+
+			gateway := repository.Gateway.(*FakeGateway)
+			Expect(gateway.ExecInsertRevisionEventCallCount()).To(Equal(1))
+
+			_, args := gateway.ExecInsertRevisionEventArgsForCall(0)
+			Expect(args.RevisionID).To(Equal(params.Migration.Revision.ID))
+			Expect(args.Action).To(Equal(ent.RevisionEventApply))
+			Expect(args.Outcome).To(Equal(ent.RevisionEventRunning))
+			Expect(args.StatementIndex).To(HaveValue(BeZero()))
+			Expect(args.Statement).To(HaveValue(HavePrefix("CREATE TABLE IF NOT EXISTS example")))
+			Expect(args.Operator).NotTo(BeNil())
+			Expect(args.Version).To(HaveValue(Equal("v1.0.0")))
+			Expect(args.CreatedAt).NotTo(BeZero())
+			Expect(args.FinishedAt).To(BeNil())
+
+			Expect(gateway.ExecUpdateRevisionEventCallCount()).To(Equal(1))
+
+			_, outcome := gateway.ExecUpdateRevisionEventArgsForCall(0)
+			Expect(outcome.ID).To(Equal(args.ID))
+			Expect(outcome.Outcome).To(Equal(ent.RevisionEventSucceeded))
+			Expect(outcome.JobID).NotTo(BeNil())
+			Expect(outcome.FinishedAt).NotTo(BeNil())
+		})
+
+		When("the attempt cannot be recorded", func() {
+			BeforeEach(func() {
+				gateway := repository.Gateway.(*FakeGateway)
+				gateway.ExecInsertRevisionEventReturns(fmt.Errorf("oh no"))
+			})
+
+			It("does not execute the statement", func(ctx SpecContext) {
+				Expect(repository.ApplyMigration(ctx, params)).To(MatchError("oh no"))
+
+				gateway := repository.Gateway.(*FakeGateway)
+				Expect(gateway.ExecUpdateRevisionCallCount()).To(BeZero())
+				Expect(gateway.ExecUpdateRevisionEventCallCount()).To(BeZero())
+			})
+		})
+
+		When("the progress cannot be recorded", func() {
+			BeforeEach(func() {
+				gateway := repository.Gateway.(*FakeGateway)
+				gateway.ExecUpdateRevisionReturns(fmt.Errorf("oh no"))
+			})
+
+			It("leaves the attempt running", func(ctx SpecContext) {
+				Expect(repository.ApplyMigration(ctx, params)).To(MatchError("oh no"))
+
+				gateway := repository.Gateway.(*FakeGateway)
+				Expect(gateway.ExecInsertRevisionEventCallCount()).To(Equal(1))
+				Expect(gateway.ExecUpdateRevisionEventCallCount()).To(BeZero())
+			})
+		})
+
+		When("the lock was lost", func() {
+			BeforeEach(func(ctx SpecContext) {
+				lock := &ent.LockMigrationParams{}
+				lock.TTL = 30 * time.Millisecond
+				Expect(repository.LockMigration(ctx, lock)).To(Succeed())
+
+				gateway := repository.Gateway.(*FakeGateway)
+				gateway.ExecRefreshLockReturns(0, nil)
+				// The lock is taken over before it expires
+				time.Sleep(2 * lock.TTL)
+			})
+
+			AfterEach(func(ctx SpecContext) {
+				_ = repository.UnlockMigration(ctx)
+			})
+
+			It("returns an error", func(ctx SpecContext) {
+				Expect(repository.ApplyMigration(ctx, params)).To(MatchError(ent.ErrLockLost))
+
+				tx := repository.Gateway.(*FakeGateway).Database().(*FakeDBTX)
+				Expect(tx.QueryRowCallCount()).To(BeZero())
+
+				gateway := repository.Gateway.(*FakeGateway)
+				Expect(gateway.ExecUpdateRevisionCallCount()).To(BeZero())
+			})
+		})
+
+		When("the revision has destructive changes", func() {
+			BeforeEach(func() {
+				var err error
+				params.Migration.Statements, err = parser.Split("ALTER TABLE example DROP COLUMN IF EXISTS name;")
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("returns an error", func(ctx SpecContext) {
+				err := repository.ApplyMigration(ctx, params)
+
+				var destructive *ent.DestructiveChangeError
+				Expect(errors.As(err, &destructive)).To(BeTrue())
+				Expect(destructive.Name).To(Equal(params.Migration.Revision.GetName()))
+				Expect(destructive.Findings).To(HaveLen(1))
+				Expect(destructive.Findings[0].Rule).To(Equal("drop-column"))
+
+				gateway := repository.Gateway.(*FakeGateway)
+				Expect(gateway.Invocations()).To(BeEmpty())
+			})
+
+			When("the destructive changes are allowed", func() {
+				BeforeEach(func() {
+					params.AllowDestructive = true
+				})
+
+				It("applies a revision", func(ctx SpecContext) {
+					Expect(repository.ApplyMigration(ctx, params)).To(Succeed())
+					Expect(params.Migration.Revision.Count).To(Equal(1))
+				})
+			})
+
+			When("the statement has a nolint directive", func() {
+				BeforeEach(func() {
+					var err error
+					params.Migration.Statements, err = parser.Split("-- aurora:nolint\nDROP TABLE IF EXISTS users;")
+					Expect(err).NotTo(HaveOccurred())
+				})
+
+				It("returns an error", func(ctx SpecContext) {
+					err := repository.ApplyMigration(ctx, params)
+
+					var destructive *ent.DestructiveChangeError
+					Expect(errors.As(err, &destructive)).To(BeTrue())
+					Expect(destructive.Findings).To(HaveLen(1))
+					Expect(destructive.Findings[0].Rule).To(Equal("drop-table"))
+
+					gateway := repository.Gateway.(*FakeGateway)
+					Expect(gateway.Invocations()).To(BeEmpty())
+				})
+			})
+
+			When("the file has the destructive directive", func() {
+				BeforeEach(func() {
+					params.Migration.Destructive = true
+				})
+
+				It("applies a revision", func(ctx SpecContext) {
+					Expect(repository.ApplyMigration(ctx, params)).To(Succeed())
+					Expect(params.Migration.Revision.Count).To(Equal(1))
+				})
+			})
+		})
+
+		When("the dialect is postgres", func() {
+			BeforeEach(func() {
+				var err error
+				params.Migration.Statements, err = parser.Split("CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_name ON example (name);")
+				Expect(err).NotTo(HaveOccurred())
+
+				row := &FakeRow{}
+				row.ScanReturns(pgx.ErrNoRows)
+
+				tx := repository.Gateway.(*FakeGateway).Database().(*FakeDBTX)
+				tx.QueryRowReturns(row)
+
+				repository.Dialect = ent.DialectPostgres
+			})
+
+			It("does not wait for a job", func(ctx SpecContext) {
+				Expect(repository.ApplyMigration(ctx, params)).To(Succeed())
+				Expect(params.Migration.Revision.Error).To(BeNil())
+
+				tx := repository.Gateway.(*FakeGateway).Database().(*FakeDBTX)
+				_, query, _ := tx.QueryRowArgsForCall(0)
+				Expect(query).To(Equal("CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_name ON example (name);"))
+
+				gateway := repository.Gateway.(*FakeGateway)
+				Expect(gateway.ExecInsertJobCallCount()).To(BeZero())
+				Expect(gateway.GetJobCallCount()).To(BeZero())
+
+				_, args := gateway.ExecInsertRevisionEventArgsForCall(0)
+				Expect(args.JobID).To(BeNil())
+			})
+
+			When("the jobs are emulated", func() {
+				BeforeEach(func() {
+					repository.EmulateJobs = true
+				})
+
+				It("records a completed job", func(ctx SpecContext) {
+					Expect(repository.ApplyMigration(ctx, params)).To(Succeed())
+					Expect(params.Migration.Revision.Error).To(BeNil())
+
+					gateway := repository.Gateway.(*FakeGateway)
+					Expect(gateway.ExecInsertJobCallCount()).To(Equal(1))
+
+					_, job := gateway.ExecInsertJobArgsForCall(0)
+					Expect(job.Status).To(Equal("completed"))
+					Expect(gateway.GetJobCallCount()).To(Equal(1))
+
+					_, args := gateway.ExecUpdateRevisionEventArgsForCall(0)
+					Expect(args.JobID).To(HaveValue(Equal(job.JobID)))
+				})
+
+				When("the job cannot be recorded", func() {
+					BeforeEach(func() {
+						gateway := repository.Gateway.(*FakeGateway)
+						gateway.ExecInsertJobReturns(fmt.Errorf("oh no"))
+					})
+
+					It("records the error", func(ctx SpecContext) {
+						Expect(repository.ApplyMigration(ctx, params)).To(Succeed())
+						Expect(params.Migration.Revision.Error).To(HaveValue(HaveSuffix("oh no")))
+					})
+				})
+			})
+		})
+
+		When("the parallel mode is enabled", func() {
+			BeforeEach(func() {
+				var err error
+				params.Parallel = true
+				params.Migration.Statements, err = parser.Split("CREATE TABLE example (id INT PRIMARY KEY, name TEXT);\nCREATE INDEX CONCURRENTLY idx_name ON example (name);\nCREATE UNIQUE INDEX idx_id ON example (id, name);\n")
+				Expect(err).NotTo(HaveOccurred())
+				params.Migration.Revision.Total = 3
+			})
+
+			It("applies the index statements as a group", func(ctx SpecContext) {
+				Expect(repository.ApplyMigration(ctx, params)).To(Succeed())
+				Expect(params.Migration.Revision.Count).To(Equal(3))
+				Expect(params.Migration.Revision.PartialHashes).To(HaveLen(3))
+
+				gateway := repository.Gateway.(*FakeGateway)
+				Expect(gateway.ExecUpdateRevisionCallCount()).To(Equal(2))
+				Expect(gateway.GetJobCallCount()).To(Equal(3))
+
+				tx := gateway.Database().(*FakeDBTX)
+				Expect(tx.QueryRowCallCount()).To(Equal(3))
+
+				_, query, _ := tx.QueryRowArgsForCall(1)
+				Expect(query).To(Equal("CREATE INDEX ASYNC idx_name ON example (name);"))
+			})
+
+			When("the jobs fail", func() {
+				BeforeEach(func() {
+					err := "oh no"
+					entity := NewFakeJob()
+					entity.Status = "failed"
+					entity.Details = &err
+
+					gateway := repository.Gateway.(*FakeGateway)
+					gateway.GetJobReturnsOnCall(0, NewFakeJob(), nil)
+					gateway.GetJobReturns(entity, nil)
+				})
+
+				It("reports the failure of each job", func(ctx SpecContext) {
+					Expect(repository.ApplyMigration(ctx, params)).To(Succeed())
+					Expect(params.Migration.Revision.Count).To(Equal(1))
+
+					name := params.Migration.Revision.GetName()
+					Expect(params.Migration.Revision.Error).To(HaveValue(Equal(name + ":2:1: oh no\n" + name + ":3:1: oh no")))
+					Expect(params.Migration.Revision.ErrorStmt).To(HaveValue(HavePrefix("CREATE INDEX ASYNC idx_name")))
+				})
+
+				It("records the outcome of each attempt", func(ctx SpecContext) {
+					Expect(repository.ApplyMigration(ctx, params)).To(Succeed())
+
+					gateway := repository.Gateway.(*FakeGateway)
+					Expect(gateway.ExecInsertRevisionEventCallCount()).To(Equal(3))
+
+					_, args := gateway.ExecInsertRevisionEventArgsForCall(2)
+					Expect(args.StatementIndex).To(HaveValue(Equal(2)))
+
+					Expect(gateway.ExecUpdateRevisionEventCallCount()).To(Equal(3))
+
+					_, outcome := gateway.ExecUpdateRevisionEventArgsForCall(2)
+					Expect(outcome.ID).To(Equal(args.ID))
+					Expect(outcome.Outcome).To(Equal(ent.RevisionEventFailed))
+					Expect(outcome.Error).To(HaveValue(HaveSuffix(":3:1: oh no")))
+				})
+			})
+		})
+
+		ItReturnsError := func(msg string) {
+			It("returns an error", func(ctx SpecContext) {
+				Expect(repository.ApplyMigration(ctx, params)).To(MatchError(msg))
+			})
+		}
+
+		When("the gateway fails", func() {
+			When("the upsert revision fails", func() {
+				BeforeEach(func() {
+					gateway := repository.Gateway.(*FakeGateway)
+					gateway.UpsertRevisionReturns(nil, fmt.Errorf("oh no"))
+				})
+
+				ItReturnsError("oh no")
+			})
+
+			When("the execute revision fails", func() {
+				BeforeEach(func() {
+					row := &FakeRow{}
+					row.ScanReturns(fmt.Errorf("oh no"))
+
+					tx := repository.Gateway.(*FakeGateway).Database().(*FakeDBTX)
+					tx.QueryRowReturns(row)
+				})
+
+				It("does not return an error", func(ctx SpecContext) {
+					Expect(repository.ApplyMigration(ctx, params)).To(Succeed())
+					Expect(params.Migration.Revision.Error).NotTo(BeNil())
+					Expect(*params.Migration.Revision.Error).To(Equal(params.Migration.Revision.GetName() + ":1:1: oh no"))
+				})
+			})
+
+			When("the statement conflicts with another transaction", func() {
+				BeforeEach(func() {
+					row := &FakeRow{}
+					row.ScanReturns(&ent.Error{Code: ent.ErrCodeSerializationFailure, Message: "schema has been updated by another transaction (OC001)"})
+
+					tx := repository.Gateway.(*FakeGateway).Database().(*FakeDBTX)
+					tx.QueryRowReturns(row)
+					tx.QueryRowReturnsOnCall(2, &FakeRow{})
+
+					repository.Retry = &ent.RetryPolicy{Limit: 2, Backoff: &ent.Backoff{Initial: time.Millisecond}}
+				})
+
+				It("retries the statement", func(ctx SpecContext) {
+					Expect(repository.ApplyMigration(ctx, params)).To(Succeed())
+					Expect(params.Migration.Revision.Error).To(BeNil())
+
+					tx := repository.Gateway.(*FakeGateway).Database().(*FakeDBTX)
+					Expect(tx.QueryRowCallCount()).To(Equal(3))
+				})
+
+				When("the retry limit is reached", func() {
+					BeforeEach(func() {
+						repository.Retry.Limit = 1
+					})
+
+					It("records the retries on the revision", func(ctx SpecContext) {
+						Expect(repository.ApplyMigration(ctx, params)).To(Succeed())
+						Expect(params.Migration.Revision.Error).To(HaveValue(HaveSuffix("(OC001) (SQLSTATE 40001) (retried once)")))
+					})
+				})
+			})
+
+			When("the update revision conflicts with another transaction", func() {
+				BeforeEach(func() {
+					gateway := repository.Gateway.(*FakeGateway)
+					gateway.ExecUpdateRevisionReturnsOnCall(0, &ent.Error{Code: ent.ErrCodeSerializationFailure})
+
+					repository.Retry = &ent.RetryPolicy{Limit: 1, Backoff: &ent.Backoff{Initial: time.Millisecond}}
+				})
+
+				It("retries the update", func(ctx SpecContext) {
+					Expect(repository.ApplyMigration(ctx, params)).To(Succeed())
+
+					gateway := repository.Gateway.(*FakeGateway)
+					Expect(gateway.ExecUpdateRevisionCallCount()).To(Equal(2))
+				})
+			})
+
+			When("the update revision fails", func() {
+				BeforeEach(func() {
+					gateway := repository.Gateway.(*FakeGateway)
+					gateway.ExecUpdateRevisionReturns(fmt.Errorf("oh no"))
+				})
+
+				ItReturnsError("oh no")
+			})
+
+			When("the job is not found", func() {
+				BeforeEach(func() {
+					gateway := repository.Gateway.(*FakeGateway)
+					gateway.GetJobReturns(nil, ent.ErrNoRows)
+				})
+
+				It("applies a revision", func(ctx SpecContext) {
+					Expect(repository.ApplyMigration(ctx, params)).To(Succeed())
+					Expect(params.Migration.Revision.Error).To(BeNil())
+				})
+			})
+
+			When("the job waiting fails", func() {
+				BeforeEach(func() {
+					gateway := repository.Gateway.(*FakeGateway)
+					gateway.GetJobReturns(nil, fmt.Errorf("oh no"))
+				})
+
+				It("does not return an error", func(ctx SpecContext) {
+					Expect(repository.ApplyMigration(ctx, params)).To(Succeed())
+					Expect(params.Migration.Revision.Error).NotTo(BeNil())
+					Expect(*params.Migration.Revision.Error).To(Equal(params.Migration.Revision.GetName() + ":1:1: oh no"))
+				})
+			})
+
+			When("the job does not complete within the timeout", func() {
+				BeforeEach(func() {
+					entity := NewFakeJob()
+					entity.Status = "processing"
+
+					gateway := repository.Gateway.(*FakeGateway)
+					gateway.GetJobReturns(entity, nil)
+
+					params.JobTimeout = 10 * time.Millisecond
+				})
+
+				It("records the timeout on the revision", func(ctx SpecContext) {
+					Expect(repository.ApplyMigration(ctx, params)).To(Succeed())
+					Expect(params.Migration.Revision.Error).NotTo(BeNil())
+					Expect(*params.Migration.Revision.Error).To(HaveSuffix("did not complete within 10ms (last status: processing)"))
+				})
+			})
+
+			When("the job fails", func() {
+				BeforeEach(func() {
+					err := "oh no"
+					entity := NewFakeJob()
+					entity.Status = "failed"
+					entity.Details = &err
+
+					gateway := repository.Gateway.(*FakeGateway)
+					gateway.GetJobReturns(entity, nil)
+				})
+
+				It("does not return an error", func(ctx SpecContext) {
+					Expect(repository.ApplyMigration(ctx, params)).To(Succeed())
+					Expect(params.Migration.Revision.Error).NotTo(BeNil())
+					Expect(*params.Migration.Revision.Error).To(Equal(params.Migration.Revision.GetName() + ":1:1: oh no"))
+				})
+			})
+		})
+	})
+
+	Describe("BaselineMigration", func() {
+		var params *ent.BaselineMigrationParams
+
+		BeforeEach(func() {
+			params = &ent.BaselineMigrationParams{}
+			params.Migration = NewFakeMigration()
+			params.Migration.Revision.ExecutedAt = time.Time{}
+		})
+
+		It("records the revision as applied", func(ctx SpecContext) {
+			Expect(repository.BaselineMigration(ctx, params)).To(Succeed())
+			Expect(params.Migration.Revision.Count).To(Equal(1))
+			Expect(params.Migration.Revision.Total).To(Equal(1))
+			Expect(params.Migration.Revision.ExecutedAt).NotTo(BeZero())
+			Expect(*params.Migration.Revision.Hash).To(Equal(params.Migration.Hash))
+			Expect(params.Migration.Revision.PartialHashes).To(HaveLen(1))
+
+			gateway := repository.Gateway.(*FakeGateway)
+			Expect(gateway.ExecInsertRevisionCallCount()).To(Equal(1))
+
+			_, args := gateway.ExecInsertRevisionArgsForCall(0)
+			Expect(args.Count).To(Equal(1))
+			Expect(args.Total).To(Equal(1))
+
+			db := gateway.Database().(*FakeDBTX)
+			Expect(db.ExecCallCount()).To(BeZero())
+			Expect(db.QueryRowCallCount()).To(BeZero())
+		})
+
+		When("the revision was reverted", func() {
+			BeforeEach(func() {
+				revertedAt := time.Now()
+				params.Migration.Revision.RevertedAt = &revertedAt
+			})
+
+			It("updates the revision", func(ctx SpecContext) {
+				Expect(repository.BaselineMigration(ctx, params)).To(Succeed())
+
+				gateway := repository.Gateway.(*FakeGateway)
+				Expect(gateway.ExecInsertRevisionCallCount()).To(BeZero())
+				Expect(gateway.ExecUpdateRevisionCallCount()).To(Equal(1))
+
+				_, args := gateway.ExecUpdateRevisionArgsForCall(0)
+				Expect(args.Count).To(Equal(1))
+				Expect(args.UpdateMask).To(ContainElements("count", "total", "executed_at", "hash", "partial_hashes"))
+			})
+		})
+
+		When("the revision is already applied", func() {
+			BeforeEach(func() {
+				params.Migration.Revision.ExecutedAt = time.Now()
+			})
+
+			It("returns an error", func(ctx SpecContext) {
+				Expect(repository.BaselineMigration(ctx, params)).To(MatchError(ContainSubstring("is already applied")))
+
+				gateway := repository.Gateway.(*FakeGateway)
+				Expect(gateway.ExecInsertRevisionCallCount()).To(BeZero())
+			})
+		})
+
+		When("the gateway fails", func() {
+			BeforeEach(func() {
+				gateway := repository.Gateway.(*FakeGateway)
+				gateway.ExecInsertRevisionReturns(fmt.Errorf("oh no"))
+			})
+
+			It("returns an error", func(ctx SpecContext) {
+				Expect(repository.BaselineMigration(ctx, params)).To(MatchError("oh no"))
+				Expect(params.Migration.Revision.Count).To(BeZero())
+			})
+		})
+	})
+
+	Describe("PlanMigration", func() {
+		var params *ent.PlanMigrationParams
+
+		BeforeEach(func() {
+			var err error
+
+			params = &ent.PlanMigrationParams{}
+			params.Migration = NewFakeMigration()
+			params.Migration.Statements, err = parser.Split("CREATE TABLE example (id INT PRIMARY KEY, name TEXT);\n-- by name\nCREATE INDEX CONCURRENTLY idx_name /* name */ ON example (name);\n")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("plans the statements", func(ctx SpecContext) {
+			plan, err := repository.PlanMigration(ctx, params)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(plan.Revision).To(Equal(params.Migration.Revision))
+			Expect(plan.Statements).To(HaveLen(2))
+			Expect(plan.Statements[0].Async).To(BeFalse())
+			Expect(plan.Statements[1].Pos.Line).To(Equal(3))
+			Expect(plan.Statements[1].Query).To(Equal("CREATE INDEX ASYNC idx_name   ON example (name);"))
+			Expect(plan.Statements[1].Async).To(BeTrue())
+
+			gateway := repository.Gateway.(*FakeGateway)
+			Expect(gateway.Invocations()).To(BeEmpty())
+		})
+
+		When("the revision is partially applied", func() {
+			BeforeEach(func() {
+				params.Migration.Revision.Count = 1
+			})
+
+			It("plans the statements left", func(ctx SpecContext) {
+				plan, err := repository.PlanMigration(ctx, params)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(plan.Statements).To(HaveLen(1))
+				Expect(plan.Statements[0].Async).To(BeTrue())
+			})
+		})
+
+		When("the dialect is postgres", func() {
+			BeforeEach(func() {
+				var err error
+				params.Migration.Statements, err = parser.Split("CREATE INDEX CONCURRENTLY idx_name ON example (name);\nCREATE UNIQUE INDEX ASYNC idx_id ON example (id);\n")
+				Expect(err).NotTo(HaveOccurred())
+
+				repository.Dialect = ent.DialectPostgres
+			})
+
+			It("builds the indexes concurrently", func(ctx SpecContext) {
+				plan, err := repository.PlanMigration(ctx, params)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(plan.Statements).To(HaveLen(2))
+				Expect(plan.Statements[0].Query).To(Equal("CREATE INDEX CONCURRENTLY idx_name ON example (name);"))
+				Expect(plan.Statements[0].Async).To(BeFalse())
+				Expect(plan.Statements[1].Query).To(Equal("CREATE UNIQUE INDEX CONCURRENTLY idx_id ON example (id);"))
+			})
+
+			When("the jobs are emulated", func() {
+				BeforeEach(func() {
+					repository.EmulateJobs = true
+				})
+
+				It("marks the asynchronous jobs", func(ctx SpecContext) {
+					plan, err := repository.PlanMigration(ctx, params)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(plan.Statements[0].Async).To(BeTrue())
+				})
+			})
+		})
+
+		When("the revision has destructive changes", func() {
+			BeforeEach(func() {
+				var err error
+				params.Migration.Statements, err = parser.Split("CREATE TABLE IF NOT EXISTS example (id INT);\nDROP TABLE IF EXISTS legacy;\n")
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("classifies the statements", func(ctx SpecContext) {
+				plan, err := repository.PlanMigration(ctx, params)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(plan.AllowDestructive).To(BeFalse())
+				Expect(plan.Statements).To(HaveLen(2))
+				Expect(plan.Statements[0].Destructive).To(BeEmpty())
+				Expect(plan.Statements[1].Destructive).To(HaveLen(1))
+				Expect(plan.Statements[1].Destructive[0].Rule).To(Equal("drop-table"))
+			})
+
+			When("the file has the destructive directive", func() {
+				BeforeEach(func() {
+					params.Migration.Destructive = true
+				})
+
+				It("allows the destructive changes", func(ctx SpecContext) {
+					plan, err := repository.PlanMigration(ctx, params)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(plan.AllowDestructive).To(BeTrue())
+				})
+			})
+		})
+
+		When("the revision is baselined", func() {
+			BeforeEach(func() {
+				params.Baseline = true
+				params.Migration.Revision.ExecutedAt = time.Time{}
+			})
+
+			It("plans the revision without statements", func(ctx SpecContext) {
+				plan, err := repository.PlanMigration(ctx, params)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(plan.Baseline).To(BeTrue())
+				Expect(plan.Statements).To(BeEmpty())
+				Expect(plan.Revision.Count).To(Equal(2))
+				Expect(params.Migration.Revision.Count).To(BeZero())
+
+				gateway := repository.Gateway.(*FakeGateway)
+				Expect(gateway.Invocations()).To(BeEmpty())
+			})
+		})
+	})
+
+	Describe("RevertMigration", func() {
+		var params *ent.RevertMigrationParams
+
+		BeforeEach(func() {
+			params = &ent.RevertMigrationParams{}
+			params.Migration = NewFakeMigration()
+			params.Migration.Revision.Count = 1
+			params.Migration.Revision.Total = 1
+			params.Migration.Down, _ = parser.Split("DROP TABLE IF EXISTS example;")
+			params.Migration.DownName = params.Migration.Revision.ID + "_schema.down.sql"
+		})
+
+		It("reverts a revision", func(ctx SpecContext) {
+			Expect(repository.RevertMigration(ctx, params)).To(Succeed())
+			Expect(params.Migration.Revision.Count).To(BeZero())
+			Expect(params.Migration.Revision.ExecutedAt).To(BeZero())
+			Expect(params.Migration.Revision.RevertedAt).NotTo(BeNil())
+
+			gateway := repository.Gateway.(*FakeGateway)
+			Expect(gateway.ExecUpdateRevisionCallCount()).To(Equal(2))
+		})
+
+		When("the lock was lost", func() {
+			BeforeEach(func(ctx SpecContext) {
+				lock := &ent.LockMigrationParams{}
+				lock.TTL = 30 * time.Millisecond
+				Expect(repository.LockMigration(ctx, lock)).To(Succeed())
+
+				gateway := repository.Gateway.(*FakeGateway)
+				gateway.ExecRefreshLockReturns(0, nil)
+				// The lock is taken over before it expires
+				time.Sleep(2 * lock.TTL)
+			})
+
+			AfterEach(func(ctx SpecContext) {
+				_ = repository.UnlockMigration(ctx)
+			})
+
+			It("returns an error", func(ctx SpecContext) {
+				Expect(repository.RevertMigration(ctx, params)).To(MatchError(ent.ErrLockLost))
+
+				tx := repository.Gateway.(*FakeGateway).Database().(*FakeDBTX)
+				Expect(tx.QueryRowCallCount()).To(BeZero())
+			})
+		})
+
+		When("the revision is partially reverted", func() {
+			BeforeEach(func() {
+				count := 1
+				params.Migration.Revision.RevertCount = &count
+			})
+
+			It("resumes the revert", func(ctx SpecContext) {
+				Expect(repository.RevertMigration(ctx, params)).To(Succeed())
+
+				tx := repository.Gateway.(*FakeGateway).Database().(*FakeDBTX)
+				Expect(tx.QueryRowCallCount()).To(BeZero())
+			})
+		})
+
+		When("the down script is missing", func() {
+			BeforeEach(func() {
+				params.Migration.Down = nil
+			})
+
+			It("returns an error", func(ctx SpecContext) {
+				Expect(repository.RevertMigration(ctx, params)).To(MatchError(ent.ErrNoDownScript))
+			})
+		})
+
+		When("the revision is not applied", func() {
+			BeforeEach(func() {
+				params.Migration.Revision.ExecutedAt = time.Time{}
+			})
+
+			It("returns an error", func(ctx SpecContext) {
+				Expect(repository.RevertMigration(ctx, params)).To(MatchError(ContainSubstring("is not applied")))
+			})
+		})
+
+		When("the execute revision fails", func() {
+			BeforeEach(func() {
+				row := &FakeRow{}
+				row.ScanReturns(fmt.Errorf("oh no"))
+
+				tx := repository.Gateway.(*FakeGateway).Database().(*FakeDBTX)
+				tx.QueryRowReturns(row)
+			})
+
+			It("does not return an error", func(ctx SpecContext) {
+				Expect(repository.RevertMigration(ctx, params)).To(Succeed())
+				Expect(params.Migration.Revision.Error).NotTo(BeNil())
+				Expect(*params.Migration.Revision.Error).To(Equal(params.Migration.DownName + ":1:1: oh no"))
+				Expect(params.Migration.Revision.ExecutedAt).NotTo(BeZero())
+			})
+		})
+
+		When("the update revision fails", func() {
+			BeforeEach(func() {
+				gateway := repository.Gateway.(*FakeGateway)
+				gateway.ExecUpdateRevisionReturns(fmt.Errorf("oh no"))
+			})
+
+			It("returns an error", func(ctx SpecContext) {
+				Expect(repository.RevertMigration(ctx, params)).To(MatchError("oh no"))
+			})
+		})
+	})
+
+	Describe("ResolveMigration", func() {
+		var params *ent.ResolveMigrationParams
+
+		BeforeEach(func() {
+			msg := "oh no"
+
+			repository.Version = "v1.0.0"
+
+			params = &ent.ResolveMigrationParams{}
+			params.Migration = NewFakeMigration()
+			params.Migration.Revision.Total = 1
+			params.Migration.Revision.Error = &msg
+			params.Migration.Revision.ErrorStmt = &params.Migration.Statements[0].Text
+		})
+
+		When("the action is retry", func() {
+			BeforeEach(func() {
+				params.Action = ent.ResolveRetry
+			})
+
+			It("clears the error", func(ctx SpecContext) {
+				Expect(repository.ResolveMigration(ctx, params)).To(Succeed())
+				Expect(params.Migration.Revision.Error).To(BeNil())
+				Expect(params.Migration.Revision.Count).To(BeZero())
+
+				gateway := repository.Gateway.(*FakeGateway)
+				Expect(gateway.ExecUpdateRevisionCallCount()).To(Equal(1))
+
+				_, args := gateway.ExecUpdateRevisionArgsForCall(0)
+				Expect(args.UpdateMask).To(ConsistOf("error", "error_stmt"))
+				Expect(args.Error).To(BeNil())
+			})
+
+			It("records the action", func(ctx SpecContext) {
+				Expect(repository.ResolveMigration(ctx, params)).To(Succeed())
+				Expect(params.Migration.Revision.ErrorStmt).To(BeNil())
+
+				gateway := repository.Gateway.(*FakeGateway)
+				Expect(gateway.ExecInsertRevisionEventCallCount()).To(Equal(1))
+
+				_, args := gateway.ExecInsertRevisionEventArgsForCall(0)
+				Expect(args.RevisionID).To(Equal(params.Migration.Revision.ID))
+				Expect(args.Action).To(Equal("retry"))
+				Expect(args.Outcome).To(Equal(ent.RevisionEventRunning))
+				Expect(args.Error).To(HaveValue(Equal("oh no")))
+				Expect(args.Statement).To(HaveValue(Equal(params.Migration.Statements[0].Text)))
+				Expect(args.Operator).NotTo(BeNil())
+				Expect(args.Version).To(HaveValue(Equal("v1.0.0")))
+
+				Expect(gateway.ExecUpdateRevisionEventCallCount()).To(Equal(1))
+
+				_, outcome := gateway.ExecUpdateRevisionEventArgsForCall(0)
+				Expect(outcome.ID).To(Equal(args.ID))
+				Expect(outcome.Outcome).To(Equal(ent.RevisionEventSucceeded))
+				Expect(outcome.Error).To(HaveValue(Equal("oh no")))
+				Expect(outcome.FinishedAt).NotTo(BeNil())
+			})
+		})
+
+		When("the action is skip-statement", func() {
+			BeforeEach(func() {
+				params.Action = ent.ResolveSkipStatement
+			})
+
+			It("counts the failed statement as executed", func(ctx SpecContext) {
+				Expect(repository.ResolveMigration(ctx, params)).To(Succeed())
+				Expect(params.Migration.Revision.Error).To(BeNil())
+				Expect(params.Migration.Revision.Count).To(Equal(1))
+				Expect(params.Migration.Revision.PartialHashes).To(HaveLen(1))
+				Expect(params.Migration.HasDrift()).To(BeFalse())
+
+				gateway := repository.Gateway.(*FakeGateway)
+				_, args := gateway.ExecUpdateRevisionArgsForCall(0)
+				Expect(args.UpdateMask).To(ConsistOf("error", "error_stmt", "count", "hash", "partial_hashes"))
+				Expect(args.Count).To(Equal(1))
+			})
+
+			When("there is no statement left", func() {
+				BeforeEach(func() {
+					params.Migration.Revision.Count = 1
+				})
+
+				It("returns an error", func(ctx SpecContext) {
+					Expect(repository.ResolveMigration(ctx, params)).To(MatchError(ContainSubstring("no statement left to skip")))
+
+					gateway := repository.Gateway.(*FakeGateway)
+					Expect(gateway.ExecInsertRevisionEventCallCount()).To(BeZero())
+				})
+			})
+		})
+
+		When("the action is reset", func() {
+			BeforeEach(func() {
+				params.Action = ent.ResolveReset
+			})
+
+			It("deletes the revision", func(ctx SpecContext) {
+				Expect(repository.ResolveMigration(ctx, params)).To(Succeed())
+				Expect(params.Migration.Revision.ExecutedAt).To(BeZero())
+				Expect(params.Migration.Revision.Error).To(BeNil())
+				Expect(params.Migration.Revision.Total).To(Equal(1))
+
+				gateway := repository.Gateway.(*FakeGateway)
+				Expect(gateway.ExecDeleteRevisionCallCount()).To(Equal(1))
+				Expect(gateway.ExecInsertRevisionEventCallCount()).To(Equal(1))
+
+				_, args := gateway.ExecInsertRevisionEventArgsForCall(0)
+				Expect(args.Action).To(Equal("reset"))
+			})
+		})
+
+		When("the revision has no error", func() {
+			BeforeEach(func() {
+				params.Action = ent.ResolveRetry
+				params.Migration.Revision.Error = nil
+			})
+
+			It("returns an error", func(ctx SpecContext) {
+				Expect(repository.ResolveMigration(ctx, params)).To(MatchError(ContainSubstring("has no error to resolve")))
+			})
+		})
+
+		When("the revision is not applied", func() {
+			BeforeEach(func() {
+				params.Action = ent.ResolveReset
+				params.Migration.Revision.ExecutedAt = time.Time{}
+			})
+
+			It("returns an error", func(ctx SpecContext) {
+				Expect(repository.ResolveMigration(ctx, params)).To(MatchError(ContainSubstring("is not applied")))
+			})
+		})
+
+		When("the action is unknown", func() {
+			BeforeEach(func() {
+				params.Action = "unknown"
+			})
+
+			It("returns an error", func(ctx SpecContext) {
+				Expect(repository.ResolveMigration(ctx, params)).To(MatchError(ContainSubstring("unknown resolve action")))
+			})
+		})
+
+		When("the gateway fails", func() {
+			BeforeEach(func() {
+				params.Action = ent.ResolveRetry
+
+				gateway := repository.Gateway.(*FakeGateway)
+				gateway.ExecUpdateRevisionReturns(fmt.Errorf("oh no"))
+			})
+
+			It("returns an error", func(ctx SpecContext) {
+				Expect(repository.ResolveMigration(ctx, params)).To(MatchError("oh no"))
+
+				gateway := repository.Gateway.(*FakeGateway)
+				Expect(gateway.ExecInsertRevisionEventCallCount()).To(Equal(1))
+				Expect(gateway.ExecUpdateRevisionEventCallCount()).To(BeZero())
+			})
+		})
+	})
+
+	Describe("ListMigrationEvents", func() {
+		var params *ent.ListMigrationEventsParams
+
+		BeforeEach(func() {
+			params = &ent.ListMigrationEventsParams{}
+		})
+
+		It("lists all events", func(ctx SpecContext) {
+			events, err := repository.ListMigrationEvents(ctx, params)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(events).To(HaveLen(1))
+
+			gateway := repository.Gateway.(*FakeGateway)
+			_, args := gateway.ListRevisionEventsArgsForCall(0)
+			Expect(args.RevisionID).To(BeNil())
+		})
+
+		When("the version is set", func() {
+			BeforeEach(func() {
+				params.Version = "20250101000000"
+			})
+
+			It("lists the events of the version", func(ctx SpecContext) {
+				_, err := repository.ListMigrationEvents(ctx, params)
+				Expect(err).NotTo(HaveOccurred())
+
+				gateway := repository.Gateway.(*FakeGateway)
+				_, args := gateway.ListRevisionEventsArgsForCall(0)
+				Expect(args.RevisionID).To(HaveValue(Equal("20250101000000")))
+			})
+		})
+
+		When("the event table does not exist", func() {
+			BeforeEach(func() {
+				gateway := repository.Gateway.(*FakeGateway)
+				gateway.ListRevisionEventsReturns(nil, &ent.Error{Code: ent.ErrCodeUndefinedTable})
+			})
+
+			It("returns no events", func(ctx SpecContext) {
+				events, err := repository.ListMigrationEvents(ctx, params)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(events).To(BeEmpty())
+			})
+		})
+	})
+
+	Describe("CreateMigration", func() {
+		var params *ent.CreateMigrationParams
+
+		BeforeEach(func() {
+			params = &ent.CreateMigrationParams{}
+			params.Description = "Add Users-Table"
+
+			fs := repository.FileSystem.(*FakeFileSystem)
+			fs.GlobReturns([]string{"000001_init.sql"}, nil)
+			fs.ReadFileStub = func(name string) ([]byte, error) {
+				if name == ent.HashFileName {
+					return nil, iofs.ErrNotExist
+				}
+				return []byte("CREATE TABLE IF NOT EXISTS example (id INT);"), nil
+			}
+
+			gateway := repository.Gateway.(*FakeGateway)
+			gateway.GetRevisionReturns(nil, ent.ErrNoRows)
 		})
 
-		It("applies a revision", func(ctx SpecContext) {
-			Expect(repository.ApplyMigration(ctx, params)).To(Succeed())
+		It("creates a migration", func(ctx SpecContext) {
+			migration, err := repository.CreateMigration(ctx, params)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(migration.Revision.ID).To(MatchRegexp(`^\d{14}$`))
+			Expect(migration.Revision.Description).To(Equal("add_users_table"))
+
+			fs := repository.FileSystem.(*FakeFileSystem)
+			Expect(fs.WriteFileCallCount()).To(Equal(1))
+
+			name, data, _ := fs.WriteFileArgsForCall(0)
+			Expect(name).To(Equal(migration.Revision.GetName()))
+			Expect(data).To(BeEmpty())
+		})
+
+		When("the scheme is sequential", func() {
+			BeforeEach(func() {
+				params.Scheme = ent.RevisionSchemeSequential
+			})
+
+			It("creates a migration", func(ctx SpecContext) {
+				migration, err := repository.CreateMigration(ctx, params)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(migration.Revision.GetName()).To(Equal("000002_add_users_table.sql"))
+			})
 		})
 
 		ItReturnsError := func(msg string) {
 			It("returns an error", func(ctx SpecContext) {
-				Expect(repository.ApplyMigration(ctx, params)).To(MatchError(msg))
+				migration, err := repository.CreateMigration(ctx, params)
+				Expect(err).To(MatchError(msg))
+				Expect(migration).To(BeNil())
 			})
 		}
 
-		When("the gateway fails", func() {
-			When("the upsert revision fails", func() {
-				BeforeEach(func() {
-					gateway := repository.Gateway.(*FakeGateway)
-					gateway.UpsertRevisionReturns(nil, fmt.Errorf("oh no"))
-				})
+		When("the scheme is not supported", func() {
+			BeforeEach(func() {
+				params.Scheme = "uuid"
+			})
 
-				ItReturnsError("oh no")
+			ItReturnsError(`the revision scheme "uuid" is not supported`)
+		})
+
+		When("the description is invalid", func() {
+			BeforeEach(func() {
+				params.Description = "--"
 			})
 
-			When("the execute revision fails", func() {
-				BeforeEach(func() {
-					row := &FakeRow{}
-					row.ScanReturns(fmt.Errorf("oh no"))
+			ItReturnsError(`the migration description "--" is invalid`)
+		})
 
-					tx := repository.Gateway.(*FakeGateway).Database().(*FakeDBTX)
-					tx.QueryRowReturns(row)
-				})
+		When("the directory has an integrity file", func() {
+			BeforeEach(func() {
+				fs := repository.FileSystem.(*FakeFileSystem)
+				fs.ReadFileStub = nil
+			})
 
-				It("does not return an error", func(ctx SpecContext) {
-					Expect(repository.ApplyMigration(ctx, params)).To(Succeed())
-					Expect(params.Migration.Revision.Error).NotTo(BeNil())
-					Expect(*params.Migration.Revision.Error).To(Equal("oh no"))
-				})
+			It("updates the integrity file", func(ctx SpecContext) {
+				_, err := repository.CreateMigration(ctx, params)
+				Expect(err).NotTo(HaveOccurred())
+
+				fs := repository.FileSystem.(*FakeFileSystem)
+				Expect(fs.WriteFileCallCount()).To(Equal(2))
+
+				name, _, _ := fs.WriteFileArgsForCall(1)
+				Expect(name).To(Equal(ent.HashFileName))
 			})
+		})
 
-			When("the update revision fails", func() {
-				BeforeEach(func() {
-					gateway := repository.Gateway.(*FakeGateway)
-					gateway.ExecUpdateRevisionReturns(fmt.Errorf("oh no"))
-				})
+		When("the revision sorts before an applied revision", func() {
+			BeforeEach(func() {
+				fs := repository.FileSystem.(*FakeFileSystem)
+				fs.GlobReturns([]string{"99990101000000_init.sql"}, nil)
 
-				ItReturnsError("oh no")
+				entity := NewFakeRevision()
+				entity.ID = "99990101000000"
+
+				gateway := repository.Gateway.(*FakeGateway)
+				gateway.GetRevisionReturns(entity, nil)
 			})
 
-			When("the job is not found", func() {
-				BeforeEach(func() {
-					gateway := repository.Gateway.(*FakeGateway)
-					gateway.GetJobReturns(nil, ent.ErrNoRows)
-				})
+			It("returns an error", func(ctx SpecContext) {
+				migration, err := repository.CreateMigration(ctx, params)
+				Expect(err).To(MatchError(ContainSubstring("sorts before the applied revision 99990101000000")))
+				Expect(migration).To(BeNil())
+			})
+		})
 
-				It("applies a revision", func(ctx SpecContext) {
-					Expect(repository.ApplyMigration(ctx, params)).To(Succeed())
-					Expect(params.Migration.Revision.Error).To(BeNil())
-				})
+		When("the file system fails", func() {
+			BeforeEach(func() {
+				fs := repository.FileSystem.(*FakeFileSystem)
+				fs.WriteFileReturns(fmt.Errorf("oh no"))
 			})
 
-			When("the job waiting fails", func() {
-				BeforeEach(func() {
-					gateway := repository.Gateway.(*FakeGateway)
-					gateway.GetJobReturns(nil, fmt.Errorf("oh no"))
-				})
+			ItReturnsError("oh no")
+		})
+	})
 
-				It("does not return an error", func(ctx SpecContext) {
-					Expect(repository.ApplyMigration(ctx, params)).To(Succeed())
-					Expect(params.Migration.Revision.Error).NotTo(BeNil())
-					Expect(*params.Migration.Revision.Error).To(Equal("oh no"))
-				})
+	Describe("HashMigrations", func() {
+		var params *ent.HashMigrationsParams
+
+		BeforeEach(func() {
+			params = &ent.HashMigrationsParams{}
+		})
+
+		It("writes the integrity file", func(ctx SpecContext) {
+			file, err := repository.HashMigrations(ctx, params)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(file).To(HaveLen(1))
+
+			fs := repository.FileSystem.(*FakeFileSystem)
+			Expect(fs.WriteFileCallCount()).To(Equal(1))
+
+			name, data, _ := fs.WriteFileArgsForCall(0)
+			Expect(name).To(Equal(ent.HashFileName))
+			Expect(string(data)).To(ContainSubstring("aurora_schema_table_test.sql h1:"))
+		})
+
+		When("the file system fails", func() {
+			BeforeEach(func() {
+				fs := repository.FileSystem.(*FakeFileSystem)
+				fs.WriteFileReturns(fmt.Errorf("oh no"))
 			})
 
-			When("the job fails", func() {
-				BeforeEach(func() {
-					err := "oh no"
-					entity := NewFakeJob()
-					entity.Status = "failed"
-					entity.Details = &err
+			It("returns an error", func(ctx SpecContext) {
+				file, err := repository.HashMigrations(ctx, params)
+				Expect(err).To(MatchError("oh no"))
+				Expect(file).To(BeNil())
+			})
+		})
+	})
 
-					gateway := repository.Gateway.(*FakeGateway)
-					gateway.GetJobReturns(entity, nil)
-				})
+	Describe("VerifyMigrations", func() {
+		var params *ent.VerifyMigrationsParams
 
-				It("does not return an error", func(ctx SpecContext) {
-					Expect(repository.ApplyMigration(ctx, params)).To(Succeed())
-					Expect(params.Migration.Revision.Error).NotTo(BeNil())
-					Expect(*params.Migration.Revision.Error).To(Equal("oh no"))
-				})
+		BeforeEach(func() {
+			params = &ent.VerifyMigrationsParams{}
+
+			file, err := ent.NewHashFile(repository.FileSystem)
+			Expect(err).NotTo(HaveOccurred())
+
+			data, err := file.MarshalText()
+			Expect(err).NotTo(HaveOccurred())
+
+			fs := repository.FileSystem.(*FakeFileSystem)
+			fs.ReadFileStub = func(name string) ([]byte, error) {
+				if name == ent.HashFileName {
+					return data, nil
+				}
+				return []byte("CREATE TABLE IF NOT EXISTS example (id SERIAL PRIMARY KEY, name VARCHAR(255));"), nil
+			}
+		})
+
+		It("verifies the migration directory", func(ctx SpecContext) {
+			Expect(repository.VerifyMigrations(ctx, params)).To(Succeed())
+		})
+
+		When("the integrity file does not exist", func() {
+			BeforeEach(func() {
+				fs := repository.FileSystem.(*FakeFileSystem)
+				fs.ReadFileStub = func(name string) ([]byte, error) {
+					return nil, iofs.ErrNotExist
+				}
+			})
+
+			It("does not return an error", func(ctx SpecContext) {
+				Expect(repository.VerifyMigrations(ctx, params)).To(Succeed())
+			})
+		})
+
+		When("a migration file was changed", func() {
+			BeforeEach(func() {
+				stub := repository.FileSystem.(*FakeFileSystem).ReadFileStub
+
+				fs := repository.FileSystem.(*FakeFileSystem)
+				fs.ReadFileStub = func(name string) ([]byte, error) {
+					if name == ent.HashFileName {
+						return stub(name)
+					}
+					return []byte("DROP TABLE example;"), nil
+				}
+			})
+
+			It("returns an error", func(ctx SpecContext) {
+				err := repository.VerifyMigrations(ctx, params)
+				Expect(err).To(MatchError(ent.ErrChecksumMismatch))
+				Expect(err).To(MatchError("atlas.sum: checksum mismatch: aurora_schema_table_test.sql was changed"))
+			})
+		})
+	})
+
+	Describe("LintMigrations", func() {
+		var params *ent.LintMigrationsParams
+
+		BeforeEach(func() {
+			params = &ent.LintMigrationsParams{}
+		})
+
+		It("lints the migration files", func(ctx SpecContext) {
+			findings, err := repository.LintMigrations(ctx, params)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(findings).To(HaveLen(1))
+			Expect(findings[0].File).To(Equal("aurora_schema_table_test.sql"))
+			Expect(findings[0].Rule).To(Equal("serial"))
+
+			gateway := repository.Gateway.(*FakeGateway)
+			Expect(gateway.Invocations()).To(BeEmpty())
+		})
+
+		When("the analyzers are set", func() {
+			BeforeEach(func() {
+				params.Analyzers = append(params.Analyzers, lint.AnalyzerFunc(func(*lint.File) []*lint.Finding {
+					return nil
+				}))
+			})
+
+			It("runs the analyzers", func(ctx SpecContext) {
+				findings, err := repository.LintMigrations(ctx, params)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(findings).To(BeEmpty())
+			})
+		})
+
+		When("the file system fails", func() {
+			BeforeEach(func() {
+				fs := repository.FileSystem.(*FakeFileSystem)
+				fs.ReadFileReturns(nil, fmt.Errorf("oh no"))
+			})
+
+			It("returns an error", func(ctx SpecContext) {
+				findings, err := repository.LintMigrations(ctx, params)
+				Expect(err).To(MatchError("oh no"))
+				Expect(findings).To(BeEmpty())
 			})
 		})
 	})
@@ -131,6 +1630,124 @@ var _ = Describe("MigrationRepository", func() {
 			Expect(migrations).NotTo(BeEmpty())
 		})
 
+		When("the revision table does not exist", func() {
+			BeforeEach(func() {
+				gateway := repository.Gateway.(*FakeGateway)
+				gateway.GetRevisionReturns(nil, &ent.Error{Code: ent.ErrCodeUndefinedTable})
+			})
+
+			It("lists the migrations as pending", func(ctx SpecContext) {
+				migrations, err := repository.ListMigrations(ctx, params)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(migrations).To(HaveLen(1))
+				Expect(migrations[0].Revision.ExecutedAt).To(BeZero())
+			})
+		})
+
+		When("the revision table was created by an older version", func() {
+			var executedAt time.Time
+
+			BeforeEach(func() {
+				executedAt = time.Now().UTC()
+
+				gateway := repository.Gateway.(*FakeGateway)
+				gateway.GetRevisionReturns(nil, &ent.Error{Code: ent.ErrCodeUndefinedColumn})
+				gateway.GetLegacyRevisionReturns(&ent.GetLegacyRevisionRow{
+					ID:            "20060102150405",
+					Description:   "schema_table_test",
+					Total:         2,
+					Count:         2,
+					ExecutedAt:    executedAt,
+					ExecutionTime: time.Second,
+				}, nil)
+			})
+
+			It("lists the migrations with the revisions of the older version", func(ctx SpecContext) {
+				migrations, err := repository.ListMigrations(ctx, params)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(migrations).To(HaveLen(1))
+				Expect(migrations[0].Revision.Count).To(Equal(2))
+				Expect(migrations[0].Revision.ExecutedAt).To(Equal(executedAt))
+				Expect(migrations[0].Revision.ExecutionTime).To(Equal(time.Second))
+				Expect(migrations[0].Revision.Hash).To(BeNil())
+
+				gateway := repository.Gateway.(*FakeGateway)
+				_, query := gateway.GetRevisionArgsForCall(0)
+				_, args := gateway.GetLegacyRevisionArgsForCall(0)
+				Expect(args.ID).To(Equal(query.ID))
+			})
+
+			When("the revision does not exist", func() {
+				BeforeEach(func() {
+					gateway := repository.Gateway.(*FakeGateway)
+					gateway.GetLegacyRevisionReturns(nil, ent.ErrNoRows)
+				})
+
+				It("lists the migrations as pending", func(ctx SpecContext) {
+					migrations, err := repository.ListMigrations(ctx, params)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(migrations).To(HaveLen(1))
+					Expect(migrations[0].Revision.ExecutedAt).To(BeZero())
+				})
+			})
+		})
+
+		It("computes the checksum of the file", func(ctx SpecContext) {
+			data, err := repository.FileSystem.(*FakeFileSystem).ReadFile("aurora_schema_table_test.sql")
+			Expect(err).NotTo(HaveOccurred())
+
+			migrations, err := repository.ListMigrations(ctx, params)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(migrations).To(HaveLen(1))
+			Expect(migrations[0].Hash).To(Equal(ent.Checksum(data)))
+		})
+
+		When("the file has the parallel directive", func() {
+			BeforeEach(func() {
+				fs := repository.FileSystem.(*FakeFileSystem)
+				fs.ReadFileReturns([]byte("-- +parallel\nCREATE INDEX idx_a ON a (id);\nCREATE INDEX idx_b ON b (id);\n"), nil)
+			})
+
+			It("enables the parallel mode", func(ctx SpecContext) {
+				migrations, err := repository.ListMigrations(ctx, params)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(migrations).To(HaveLen(1))
+				Expect(migrations[0].Parallel).To(BeTrue())
+			})
+		})
+
+		When("the file has the destructive directive", func() {
+			BeforeEach(func() {
+				fs := repository.FileSystem.(*FakeFileSystem)
+				fs.ReadFileReturns([]byte("-- +destructive\nDROP TABLE IF EXISTS legacy;\n"), nil)
+			})
+
+			It("allows the destructive changes", func(ctx SpecContext) {
+				migrations, err := repository.ListMigrations(ctx, params)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(migrations).To(HaveLen(1))
+				Expect(migrations[0].Destructive).To(BeTrue())
+			})
+		})
+
+		When("the file has semicolons inside literals", func() {
+			BeforeEach(func() {
+				fs := repository.FileSystem.(*FakeFileSystem)
+				fs.ReadFileReturns([]byte("-- users;\nINSERT INTO users (name) VALUES ('a;b');\nCREATE FUNCTION f() RETURNS INT AS $$ SELECT 1; $$ LANGUAGE SQL;\n"), nil)
+
+				gateway := repository.Gateway.(*FakeGateway)
+				gateway.GetRevisionReturns(nil, ent.ErrNoRows)
+			})
+
+			It("splits the statements", func(ctx SpecContext) {
+				migrations, err := repository.ListMigrations(ctx, params)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(migrations).To(HaveLen(1))
+				Expect(migrations[0].Statements).To(HaveLen(2))
+				Expect(migrations[0].Revision.Total).To(Equal(2))
+			})
+		})
+
 		ItReturnsError := func(msg string) {
 			It("returns an error", func(ctx SpecContext) {
 				migrations, err := repository.ListMigrations(ctx, params)
@@ -165,5 +1782,48 @@ var _ = Describe("MigrationRepository", func() {
 
 			ItReturnsError("oh no")
 		})
+
+		When("the file has a down section", func() {
+			BeforeEach(func() {
+				fs := repository.FileSystem.(*FakeFileSystem)
+				fs.ReadFileReturns([]byte("CREATE TABLE IF NOT EXISTS a (id INT);\n-- +down\nDROP TABLE IF EXISTS a;\n"), nil)
+			})
+
+			It("splits the down statements", func(ctx SpecContext) {
+				migrations, err := repository.ListMigrations(ctx, params)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(migrations).To(HaveLen(1))
+				Expect(migrations[0].Statements).To(HaveLen(1))
+				Expect(migrations[0].Down).To(HaveLen(1))
+				Expect(migrations[0].DownName).To(Equal("aurora_schema_table_test.sql"))
+			})
+		})
+
+		When("the directory has a down file", func() {
+			BeforeEach(func() {
+				fs := repository.FileSystem.(*FakeFileSystem)
+				fs.GlobReturns([]string{"1_test.down.sql", "1_test.sql"}, nil)
+				fs.ReadFileReturnsOnCall(0, []byte("CREATE TABLE IF NOT EXISTS a (id INT);"), nil)
+				fs.ReadFileReturnsOnCall(1, []byte("DROP TABLE IF EXISTS a;"), nil)
+			})
+
+			It("loads the down statements", func(ctx SpecContext) {
+				migrations, err := repository.ListMigrations(ctx, params)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(migrations).To(HaveLen(1))
+				Expect(migrations[0].Statements).To(HaveLen(1))
+				Expect(migrations[0].Down).To(HaveLen(1))
+				Expect(migrations[0].DownName).To(Equal("1_test.down.sql"))
+			})
+		})
+
+		When("the file has an unterminated literal", func() {
+			BeforeEach(func() {
+				fs := repository.FileSystem.(*FakeFileSystem)
+				fs.ReadFileReturns([]byte("SELECT 1;\nSELECT 'a;"), nil)
+			})
+
+			ItReturnsError("aurora_schema_table_test.sql:2:8: unterminated quoted string")
+		})
 	})
 })
@@ -67,6 +67,31 @@ func (c *ExecInsertLockParamsConverterImpl) SetFromLock(target *ExecInsertLockPa
 	if source != nil {
 		target.ID = source.ID
 		target.CreatedAt = source.CreatedAt
+		target.Owner = source.Owner
+		target.Hostname = source.Hostname
+		target.Pid = source.Pid
+		target.Version = source.Version
+		target.ExpiresAt = source.ExpiresAt
+	}
+}
+
+type ExecInsertRevisionEventParamsConverterImpl struct{}
+
+func (c *ExecInsertRevisionEventParamsConverterImpl) SetFromRevisionEvent(target *ExecInsertRevisionEventParams, source *RevisionEvent) {
+	if source != nil {
+		target.ID = source.ID
+		target.RevisionID = source.RevisionID
+		target.Action = source.Action
+		target.Error = source.Error
+		target.Operator = source.Operator
+		target.Version = source.Version
+		target.CreatedAt = source.CreatedAt
+		target.StatementIndex = source.StatementIndex
+		target.Statement = source.Statement
+		target.JobID = source.JobID
+		target.Outcome = source.Outcome
+		target.StartedAt = source.StartedAt
+		target.FinishedAt = source.FinishedAt
 	}
 }
 
@@ -82,6 +107,53 @@ func (c *ExecInsertRevisionParamsConverterImpl) SetFromRevision(target *ExecInse
 		target.ErrorStmt = source.ErrorStmt
 		target.ExecutedAt = source.ExecutedAt
 		target.ExecutionTime = source.ExecutionTime
+		target.Hash = source.Hash
+		target.PartialHashes = source.PartialHashes
+	}
+}
+
+type ExecRefreshLockParamsConverterImpl struct{}
+
+func (c *ExecRefreshLockParamsConverterImpl) SetFromLock(target *ExecRefreshLockParams, source *Lock) {
+	if source != nil {
+		target.ExpiresAt = source.ExpiresAt
+		target.ID = source.ID
+		target.Owner = source.Owner
+	}
+}
+
+type ExecReleaseLockParamsConverterImpl struct{}
+
+func (c *ExecReleaseLockParamsConverterImpl) SetFromLock(target *ExecReleaseLockParams, source *Lock) {
+	if source != nil {
+		target.ID = source.ID
+		target.Owner = source.Owner
+	}
+}
+
+type ExecTakeoverLockParamsConverterImpl struct{}
+
+func (c *ExecTakeoverLockParamsConverterImpl) SetFromLock(target *ExecTakeoverLockParams, source *Lock) {
+	if source != nil {
+		target.CreatedAt = source.CreatedAt
+		target.Owner = source.Owner
+		target.Hostname = source.Hostname
+		target.Pid = source.Pid
+		target.Version = source.Version
+		target.ExpiresAt = source.ExpiresAt
+		target.ID = source.ID
+	}
+}
+
+type ExecUpdateRevisionEventParamsConverterImpl struct{}
+
+func (c *ExecUpdateRevisionEventParamsConverterImpl) SetFromRevisionEvent(target *ExecUpdateRevisionEventParams, source *RevisionEvent) {
+	if source != nil {
+		target.JobID = source.JobID
+		target.Outcome = source.Outcome
+		target.Error = source.Error
+		target.FinishedAt = source.FinishedAt
+		target.ID = source.ID
 	}
 }
 
@@ -96,6 +168,10 @@ func (c *ExecUpdateRevisionParamsConverterImpl) SetFromRevision(target *ExecUpda
 		target.ErrorStmt = source.ErrorStmt
 		target.ExecutedAt = source.ExecutedAt
 		target.ExecutionTime = source.ExecutionTime
+		target.RevertCount = source.RevertCount
+		target.RevertedAt = source.RevertedAt
+		target.Hash = source.Hash
+		target.PartialHashes = source.PartialHashes
 		target.ID = source.ID
 	}
 }
@@ -112,6 +188,8 @@ func (c *ExecUpsertRevisionParamsConverterImpl) SetFromRevision(target *ExecUpse
 		target.ErrorStmt = source.ErrorStmt
 		target.ExecutedAt = source.ExecutedAt
 		target.ExecutionTime = source.ExecutionTime
+		target.Hash = source.Hash
+		target.PartialHashes = source.PartialHashes
 	}
 }
 
@@ -155,6 +233,31 @@ func (c *InsertLockParamsConverterImpl) SetFromLock(target *InsertLockParams, so
 	if source != nil {
 		target.ID = source.ID
 		target.CreatedAt = source.CreatedAt
+		target.Owner = source.Owner
+		target.Hostname = source.Hostname
+		target.Pid = source.Pid
+		target.Version = source.Version
+		target.ExpiresAt = source.ExpiresAt
+	}
+}
+
+type InsertRevisionEventParamsConverterImpl struct{}
+
+func (c *InsertRevisionEventParamsConverterImpl) SetFromRevisionEvent(target *InsertRevisionEventParams, source *RevisionEvent) {
+	if source != nil {
+		target.ID = source.ID
+		target.RevisionID = source.RevisionID
+		target.Action = source.Action
+		target.Error = source.Error
+		target.Operator = source.Operator
+		target.Version = source.Version
+		target.CreatedAt = source.CreatedAt
+		target.StatementIndex = source.StatementIndex
+		target.Statement = source.Statement
+		target.JobID = source.JobID
+		target.Outcome = source.Outcome
+		target.StartedAt = source.StartedAt
+		target.FinishedAt = source.FinishedAt
 	}
 }
 
@@ -170,6 +273,8 @@ func (c *InsertRevisionParamsConverterImpl) SetFromRevision(target *InsertRevisi
 		target.ErrorStmt = source.ErrorStmt
 		target.ExecutedAt = source.ExecutedAt
 		target.ExecutionTime = source.ExecutionTime
+		target.Hash = source.Hash
+		target.PartialHashes = source.PartialHashes
 	}
 }
 
@@ -184,6 +289,10 @@ func (c *UpdateRevisionParamsConverterImpl) SetFromRevision(target *UpdateRevisi
 		target.ErrorStmt = source.ErrorStmt
 		target.ExecutedAt = source.ExecutedAt
 		target.ExecutionTime = source.ExecutionTime
+		target.RevertCount = source.RevertCount
+		target.RevertedAt = source.RevertedAt
+		target.Hash = source.Hash
+		target.PartialHashes = source.PartialHashes
 		target.ID = source.ID
 	}
 }
@@ -200,5 +309,7 @@ func (c *UpsertRevisionParamsConverterImpl) SetFromRevision(target *UpsertRevisi
 		target.ErrorStmt = source.ErrorStmt
 		target.ExecutedAt = source.ExecutedAt
 		target.ExecutionTime = source.ExecutionTime
+		target.Hash = source.Hash
+		target.PartialHashes = source.PartialHashes
 	}
 }
@@ -1,21 +1,194 @@
 package ent
 
 import (
+	"crypto/sha256"
+	"database/sql/driver"
+	"encoding/base64"
+	"fmt"
 	"strings"
+	"time"
+
+	"github.com/aws-contrib/aurora/internal/database/ent/lint"
+	"github.com/aws-contrib/aurora/internal/database/ent/parser"
 )
 
 // Migration represents a database migration with its details.
 type Migration struct {
 	Revision   *Revision
-	Statements []string
+	Statements []*parser.Statement
+	// Down contains the statements that revert the migration.
+	Down []*parser.Statement
+	// DownName is the name of the file that contains the down statements.
+	DownName string
+	// Hash is the checksum of the migration file content.
+	Hash string
+	// Parallel is set by the '-- +parallel' directive at the top of the file.
+	Parallel bool
+	// Destructive is set by the '-- +destructive' directive at the top of
+	// the file. It allows the destructive changes of the migration.
+	Destructive bool
+}
+
+// GetDestructiveChanges returns the destructive changes of the statements
+// that are left to apply. The nolint directives do not apply: only the
+// '-- +destructive' directive or the caller can allow the changes.
+func (x *Migration) GetDestructiveChanges() []*lint.Finding {
+	file := &lint.File{}
+	file.Name = x.Revision.GetName()
+	file.Statements = x.Statements[min(x.Revision.Count, len(x.Statements)):]
+
+	return lint.Destructive.Analyze(file)
+}
+
+// HasDrift reports whether the statements executed for the revision have
+// changed since they were applied. Revisions applied before checksums were
+// recorded never drift.
+func (x *Migration) HasDrift() bool {
+	revision := x.Revision
+
+	switch {
+	case revision == nil || revision.Hash == nil:
+		return false
+	case *revision.Hash == x.Hash:
+		return false
+	case revision.Count >= revision.Total:
+		// The whole file was applied, any change is a drift
+		return true
+	}
+
+	// Only the executed statements of a partially applied revision must match
+	for index, hash := range revision.PartialHashes {
+		if index >= len(x.Statements) || hash != Checksum([]byte(x.Statements[index].Text)) {
+			return true
+		}
+	}
+
+	return false
 }
 
 // MigrationState represents the state of a migration operation.
 type MigrationState struct {
-	Next     *Revision
-	Current  *Revision
-	Pending  []*Revision
-	Executed []*Revision
+	Next     *Revision   `json:"next"`
+	Current  *Revision   `json:"current"`
+	Pending  []*Revision `json:"pending"`
+	Executed []*Revision `json:"executed"`
+	// Drifted contains the executed revisions whose files changed after they were applied.
+	Drifted []*Revision `json:"drifted"`
+	// Lock is the migration lock, if it is held.
+	Lock *Lock `json:"lock"`
+}
+
+// GetOwner returns the hostname and pid of the process that holds the lock.
+func (x *Lock) GetOwner() string {
+	owner := "unknown"
+	if x.Hostname != nil {
+		owner = *x.Hostname
+	}
+
+	if x.Pid != nil {
+		owner = fmt.Sprintf("%s (pid %d)", owner, *x.Pid)
+	}
+
+	return owner
+}
+
+const (
+	// RevisionEventApply is the action of a statement executed by ApplyMigration.
+	RevisionEventApply = "apply"
+	// RevisionEventRevert is the action of a statement executed by RevertMigration.
+	RevisionEventRevert = "revert"
+)
+
+const (
+	// RevisionEventRunning is the outcome of an action that has not finished,
+	// or whose process was stopped before it finished.
+	RevisionEventRunning = "running"
+	// RevisionEventSucceeded is the outcome of a successful action.
+	RevisionEventSucceeded = "succeeded"
+	// RevisionEventFailed is the outcome of a failed action.
+	RevisionEventFailed = "failed"
+)
+
+// GetPosition returns the 1-based position of the statement of the event, or
+// "-" when the event is not about a statement.
+func (x *RevisionEvent) GetPosition() string {
+	if x.StatementIndex == nil {
+		return "-"
+	}
+
+	return fmt.Sprintf("#%d", *x.StatementIndex+1)
+}
+
+// GetDuration returns how long the action took, or zero if it is not finished.
+func (x *RevisionEvent) GetDuration() time.Duration {
+	if x.FinishedAt == nil {
+		return 0
+	}
+
+	return x.FinishedAt.Sub(x.StartedAt)
+}
+
+// MigrationPlan represents the statements that are left to apply for a revision.
+type MigrationPlan struct {
+	// Revision is the revision to apply.
+	Revision *Revision `json:"revision"`
+	// Baseline reports whether the revision is recorded as applied without
+	// executing its statements.
+	Baseline bool `json:"baseline"`
+	// Statements are the statements left to apply, ready to be executed.
+	Statements []*MigrationPlanStatement `json:"statements"`
+	// AllowDestructive reports whether the destructive statements are applied.
+	AllowDestructive bool `json:"allow_destructive"`
+}
+
+// MigrationPlanStatement represents a statement of a migration plan.
+type MigrationPlanStatement struct {
+	// Pos is the position of the statement in the migration file.
+	Pos parser.Position `json:"position"`
+	// Query is the query as it is executed.
+	Query string `json:"query"`
+	// Async reports whether the query creates an asynchronous job.
+	Async bool `json:"async"`
+	// Destructive are the destructive changes of the statement.
+	Destructive []*lint.Finding `json:"destructive"`
+}
+
+// Checksum returns the base64 encoded SHA-256 checksum of the given data.
+func Checksum(data []byte) string {
+	hash := sha256.Sum256(data)
+	return base64.StdEncoding.EncodeToString(hash[:])
+}
+
+// Hashes represents a list of checksums stored as a comma separated string.
+type Hashes []string
+
+var _ driver.Valuer = Hashes(nil)
+
+// Value implements driver.Valuer.
+func (x Hashes) Value() (driver.Value, error) {
+	if x == nil {
+		return nil, nil
+	}
+
+	return strings.Join(x, ","), nil
+}
+
+// Scan implements sql.Scanner.
+func (x *Hashes) Scan(src any) error {
+	switch value := src.(type) {
+	case nil:
+		*x = nil
+	case string:
+		if value == "" {
+			*x = Hashes{}
+		} else {
+			*x = strings.Split(value, ",")
+		}
+	default:
+		return fmt.Errorf("cannot scan %T into Hashes", src)
+	}
+
+	return nil
 }
 
 // GetName returns the name of the revision file based on its ID and description.
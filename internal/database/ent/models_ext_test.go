@@ -1,7 +1,10 @@
 package ent_test
 
 import (
+	"time"
+
 	"github.com/aws-contrib/aurora/internal/database/ent"
+	"github.com/aws-contrib/aurora/internal/database/ent/parser"
 
 	. "github.com/aws-contrib/aurora/internal/database/ent/fake"
 	. "github.com/onsi/ginkgo/v2"
@@ -29,3 +32,196 @@ var _ = Describe("Revision", func() {
 		})
 	})
 })
+
+var _ = Describe("Migration", func() {
+	var entity *ent.Migration
+
+	BeforeEach(func() {
+		entity = NewFakeMigration()
+		entity.Revision.Total = 1
+		entity.Revision.Count = 1
+		hash := entity.Hash
+		entity.Revision.Hash = &hash
+		entity.Revision.PartialHashes = ent.Hashes{ent.Checksum([]byte(entity.Statements[0].Text))}
+	})
+
+	Describe("HasDrift", func() {
+		It("returns false", func() {
+			Expect(entity.HasDrift()).To(BeFalse())
+		})
+
+		When("the file was changed", func() {
+			BeforeEach(func() {
+				entity.Hash = ent.Checksum([]byte("changed"))
+			})
+
+			It("returns true", func() {
+				Expect(entity.HasDrift()).To(BeTrue())
+			})
+
+			When("the revision is partially applied", func() {
+				BeforeEach(func() {
+					entity.Revision.Total = 2
+				})
+
+				It("returns false", func() {
+					Expect(entity.HasDrift()).To(BeFalse())
+				})
+
+				When("an executed statement was changed", func() {
+					BeforeEach(func() {
+						entity.Revision.PartialHashes = ent.Hashes{ent.Checksum([]byte("changed"))}
+					})
+
+					It("returns true", func() {
+						Expect(entity.HasDrift()).To(BeTrue())
+					})
+				})
+			})
+		})
+
+		When("the revision has no checksum", func() {
+			BeforeEach(func() {
+				entity.Hash = ent.Checksum([]byte("changed"))
+				entity.Revision.Hash = nil
+			})
+
+			It("returns false", func() {
+				Expect(entity.HasDrift()).To(BeFalse())
+			})
+		})
+	})
+
+	Describe("GetDestructiveChanges", func() {
+		BeforeEach(func() {
+			var err error
+			entity.Statements, err = parser.Split("DROP TABLE IF EXISTS a;\nDELETE FROM b;\n")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("returns the changes of the statements left to apply", func() {
+			findings := entity.GetDestructiveChanges()
+			Expect(findings).To(HaveLen(1))
+			Expect(findings[0].File).To(Equal(entity.Revision.GetName()))
+			Expect(findings[0].Rule).To(Equal("unbounded-delete"))
+			Expect(findings[0].Pos.Line).To(Equal(2))
+		})
+
+		When("the statement has a nolint directive", func() {
+			BeforeEach(func() {
+				var err error
+				entity.Statements, err = parser.Split("-- aurora:nolint\nDROP TABLE IF EXISTS users;\n")
+				Expect(err).NotTo(HaveOccurred())
+				entity.Revision.Count = 0
+			})
+
+			It("returns the changes", func() {
+				findings := entity.GetDestructiveChanges()
+				Expect(findings).To(HaveLen(1))
+				Expect(findings[0].Rule).To(Equal("drop-table"))
+			})
+		})
+	})
+})
+
+var _ = Describe("Hashes", func() {
+	Describe("Value", func() {
+		It("returns the joined checksums", func() {
+			Expect(ent.Hashes{"a", "b"}.Value()).To(Equal("a,b"))
+		})
+
+		When("the hashes are nil", func() {
+			It("returns nil", func() {
+				Expect(ent.Hashes(nil).Value()).To(BeNil())
+			})
+		})
+	})
+
+	Describe("Scan", func() {
+		It("scans the checksums", func() {
+			var hashes ent.Hashes
+			Expect(hashes.Scan("a,b")).To(Succeed())
+			Expect(hashes).To(Equal(ent.Hashes{"a", "b"}))
+		})
+
+		When("the value is nil", func() {
+			It("scans nil", func() {
+				hashes := ent.Hashes{"a"}
+				Expect(hashes.Scan(nil)).To(Succeed())
+				Expect(hashes).To(BeNil())
+			})
+		})
+
+		When("the value is not a string", func() {
+			It("returns an error", func() {
+				var hashes ent.Hashes
+				Expect(hashes.Scan(42)).To(MatchError("cannot scan int into Hashes"))
+			})
+		})
+	})
+})
+
+var _ = Describe("Lock", func() {
+	var entity *ent.Lock
+
+	BeforeEach(func() {
+		entity = NewFakeLock()
+	})
+
+	Describe("GetOwner", func() {
+		It("returns the owner", func() {
+			Expect(entity.GetOwner()).To(Equal("localhost (pid 42)"))
+		})
+
+		When("the lock has no owner", func() {
+			BeforeEach(func() {
+				entity.Hostname = nil
+				entity.Pid = nil
+			})
+
+			It("returns unknown", func() {
+				Expect(entity.GetOwner()).To(Equal("unknown"))
+			})
+		})
+	})
+})
+
+var _ = Describe("RevisionEvent", func() {
+	var entity *ent.RevisionEvent
+
+	BeforeEach(func() {
+		entity = NewFakeRevisionEvent()
+	})
+
+	Describe("GetPosition", func() {
+		It("returns the position of the statement", func() {
+			Expect(entity.GetPosition()).To(Equal("#1"))
+		})
+
+		When("the event is not about a statement", func() {
+			BeforeEach(func() {
+				entity.StatementIndex = nil
+			})
+
+			It("returns a dash", func() {
+				Expect(entity.GetPosition()).To(Equal("-"))
+			})
+		})
+	})
+
+	Describe("GetDuration", func() {
+		It("returns the duration", func() {
+			Expect(entity.GetDuration()).To(Equal(500 * time.Millisecond))
+		})
+
+		When("the event is not finished", func() {
+			BeforeEach(func() {
+				entity.FinishedAt = nil
+			})
+
+			It("returns zero", func() {
+				Expect(entity.GetDuration()).To(BeZero())
+			})
+		})
+	})
+})
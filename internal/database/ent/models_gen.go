@@ -15,8 +15,13 @@ type Job struct {
 }
 
 type Lock struct {
-	ID        string    `db:"id" json:"id"`
-	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	ID        string     `db:"id" json:"id"`
+	CreatedAt time.Time  `db:"created_at" json:"created_at"`
+	Owner     *string    `db:"owner" json:"owner"`
+	Hostname  *string    `db:"hostname" json:"hostname"`
+	Pid       *int       `db:"pid" json:"pid"`
+	Version   *string    `db:"version" json:"version"`
+	ExpiresAt *time.Time `db:"expires_at" json:"expires_at"`
 }
 
 type Revision struct {
@@ -28,4 +33,24 @@ type Revision struct {
 	ErrorStmt     *string       `db:"error_stmt" json:"error_stmt"`
 	ExecutedAt    time.Time     `db:"executed_at" json:"executed_at"`
 	ExecutionTime time.Duration `db:"execution_time" json:"execution_time"`
+	RevertCount   *int          `db:"revert_count" json:"revert_count"`
+	RevertedAt    *time.Time    `db:"reverted_at" json:"reverted_at"`
+	Hash          *string       `db:"hash" json:"hash"`
+	PartialHashes Hashes        `db:"partial_hashes" json:"partial_hashes"`
+}
+
+type RevisionEvent struct {
+	ID             string     `db:"id" json:"id"`
+	RevisionID     string     `db:"revision_id" json:"revision_id"`
+	Action         string     `db:"action" json:"action"`
+	Error          *string    `db:"error" json:"error"`
+	Operator       *string    `db:"operator" json:"operator"`
+	Version        *string    `db:"version" json:"version"`
+	CreatedAt      time.Time  `db:"created_at" json:"created_at"`
+	StatementIndex *int       `db:"statement_index" json:"statement_index"`
+	Statement      *string    `db:"statement" json:"statement"`
+	JobID          *string    `db:"job_id" json:"job_id"`
+	Outcome        string     `db:"outcome" json:"outcome"`
+	StartedAt      time.Time  `db:"started_at" json:"started_at"`
+	FinishedAt     *time.Time `db:"finished_at" json:"finished_at"`
 }
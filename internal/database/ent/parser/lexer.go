@@ -0,0 +1,448 @@
+// Package parser provides a PostgreSQL aware lexer and statement splitter for migration files.
+package parser
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// TokenKind represents the kind of a lexical token.
+type TokenKind int
+
+const (
+	// TokenKeyword is an unquoted identifier or keyword (e.g. CREATE, users).
+	TokenKeyword TokenKind = iota
+	// TokenIdent is a quoted identifier (e.g. "users").
+	TokenIdent
+	// TokenString is a string literal (e.g. 'text', E'text', $$text$$).
+	TokenString
+	// TokenNumber is a numeric literal.
+	TokenNumber
+	// TokenParam is a positional parameter (e.g. $1).
+	TokenParam
+	// TokenOperator is an operator or punctuation character.
+	TokenOperator
+	// TokenComment is a line or block comment.
+	TokenComment
+	// TokenSemicolon is the statement terminator.
+	TokenSemicolon
+)
+
+// String returns the name of the token kind.
+func (x TokenKind) String() string {
+	switch x {
+	case TokenKeyword:
+		return "keyword"
+	case TokenIdent:
+		return "ident"
+	case TokenString:
+		return "string"
+	case TokenNumber:
+		return "number"
+	case TokenParam:
+		return "param"
+	case TokenOperator:
+		return "operator"
+	case TokenComment:
+		return "comment"
+	case TokenSemicolon:
+		return "semicolon"
+	default:
+		return "unknown"
+	}
+}
+
+// Position represents a location in the source text.
+type Position struct {
+	// Offset is the byte offset, starting at 0.
+	Offset int `json:"offset"`
+	// Line is the line number, starting at 1.
+	Line int `json:"line"`
+	// Column is the column number in characters, starting at 1.
+	Column int `json:"column"`
+}
+
+// String returns the position in the line:column format.
+func (x Position) String() string {
+	return fmt.Sprintf("%d:%d", x.Line, x.Column)
+}
+
+// Token represents a lexical token.
+type Token struct {
+	// Kind is the kind of the token.
+	Kind TokenKind
+	// Text is the raw text of the token.
+	Text string
+	// Pos is the position where the token starts.
+	Pos Position
+}
+
+// Is reports whether the token is the given keyword (case insensitive).
+func (x *Token) Is(keyword string) bool {
+	return x.Kind == TokenKeyword && strings.EqualFold(x.Text, keyword)
+}
+
+// Value returns the unquoted value of identifiers and keywords. Unquoted
+// identifiers are folded to lower case like PostgreSQL does.
+func (x *Token) Value() string {
+	switch x.Kind {
+	case TokenKeyword:
+		return strings.ToLower(x.Text)
+	case TokenIdent:
+		value := strings.TrimPrefix(x.Text, "U&")
+		value = strings.TrimPrefix(value, "u&")
+		value = strings.TrimSuffix(strings.TrimPrefix(value, `"`), `"`)
+		return strings.ReplaceAll(value, `""`, `"`)
+	default:
+		return x.Text
+	}
+}
+
+// SyntaxError represents a lexical error in the source text.
+type SyntaxError struct {
+	// Pos is the position where the error was found.
+	Pos Position
+	// Message describes the error.
+	Message string
+}
+
+// Error implements error.
+func (x *SyntaxError) Error() string {
+	return fmt.Sprintf("%s: %s", x.Pos, x.Message)
+}
+
+// Lexer splits PostgreSQL source text into tokens.
+type Lexer struct {
+	text   string
+	offset int
+	line   int
+	column int
+}
+
+// NewLexer returns a new lexer for the given source text.
+func NewLexer(text string) *Lexer {
+	return &Lexer{
+		text:   text,
+		line:   1,
+		column: 1,
+	}
+}
+
+// Tokenize returns all tokens in the given source text.
+func Tokenize(text string) ([]*Token, error) {
+	lexer := NewLexer(text)
+
+	var collection []*Token
+	for {
+		token, err := lexer.Next()
+		switch {
+		case err != nil:
+			return nil, err
+		case token == nil:
+			return collection, nil
+		default:
+			collection = append(collection, token)
+		}
+	}
+}
+
+// Next returns the next token or nil when the end of the text is reached.
+func (x *Lexer) Next() (*Token, error) {
+	x.skipSpace()
+
+	if x.offset >= len(x.text) {
+		return nil, nil
+	}
+
+	start := x.position()
+
+	var (
+		kind TokenKind
+		err  error
+	)
+
+	ch := x.text[x.offset]
+	switch {
+	case x.hasPrefix("--"):
+		kind = TokenComment
+		x.scanLineComment()
+	case x.hasPrefix("/*"):
+		kind = TokenComment
+		err = x.scanBlockComment()
+	case ch == ';':
+		kind = TokenSemicolon
+		x.advance(1)
+	case ch == '\'':
+		kind = TokenString
+		err = x.scanString(false)
+	case (ch == 'E' || ch == 'e') && x.peek(1) == '\'':
+		kind = TokenString
+		x.advance(1)
+		err = x.scanString(true)
+	case (ch == 'B' || ch == 'b' || ch == 'X' || ch == 'x' || ch == 'N' || ch == 'n') && x.peek(1) == '\'':
+		kind = TokenString
+		x.advance(1)
+		err = x.scanString(false)
+	case (ch == 'U' || ch == 'u') && x.peek(1) == '&' && x.peek(2) == '\'':
+		kind = TokenString
+		x.advance(2)
+		err = x.scanString(false)
+	case (ch == 'U' || ch == 'u') && x.peek(1) == '&' && x.peek(2) == '"':
+		kind = TokenIdent
+		x.advance(2)
+		err = x.scanQuotedIdent()
+	case ch == '"':
+		kind = TokenIdent
+		err = x.scanQuotedIdent()
+	case ch == '$':
+		if tag, ok := x.dollarTag(); ok {
+			kind = TokenString
+			err = x.scanDollarString(tag)
+		} else if isDigit(x.peek(1)) {
+			kind = TokenParam
+			x.advance(1)
+			x.scanWhile(isDigit)
+		} else {
+			kind = TokenOperator
+			x.advance(1)
+		}
+	case isDigit(ch) || (ch == '.' && isDigit(x.peek(1))):
+		kind = TokenNumber
+		x.scanNumber()
+	case isIdentStart(x.rune()):
+		kind = TokenKeyword
+		x.scanIdent()
+	default:
+		kind = TokenOperator
+		x.scanOperator()
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	token := &Token{
+		Kind: kind,
+		Text: x.text[start.Offset:x.offset],
+		Pos:  start,
+	}
+
+	return token, nil
+}
+
+func (x *Lexer) position() Position {
+	return Position{
+		Offset: x.offset,
+		Line:   x.line,
+		Column: x.column,
+	}
+}
+
+func (x *Lexer) hasPrefix(prefix string) bool {
+	return strings.HasPrefix(x.text[x.offset:], prefix)
+}
+
+func (x *Lexer) peek(n int) byte {
+	if x.offset+n < len(x.text) {
+		return x.text[x.offset+n]
+	}
+	return 0
+}
+
+func (x *Lexer) rune() rune {
+	ch, _ := utf8.DecodeRuneInString(x.text[x.offset:])
+	return ch
+}
+
+// advance moves the lexer n bytes forward while tracking lines and columns.
+func (x *Lexer) advance(n int) {
+	end := min(x.offset+n, len(x.text))
+
+	for x.offset < end {
+		ch, size := utf8.DecodeRuneInString(x.text[x.offset:])
+		x.offset += size
+
+		if ch == '\n' {
+			x.line++
+			x.column = 1
+		} else {
+			x.column++
+		}
+	}
+}
+
+func (x *Lexer) scanWhile(fn func(byte) bool) {
+	for x.offset < len(x.text) && fn(x.text[x.offset]) {
+		x.advance(1)
+	}
+}
+
+func (x *Lexer) skipSpace() {
+	for x.offset < len(x.text) {
+		ch, size := utf8.DecodeRuneInString(x.text[x.offset:])
+		if !unicode.IsSpace(ch) {
+			return
+		}
+		x.advance(size)
+	}
+}
+
+func (x *Lexer) scanLineComment() {
+	x.scanWhile(func(ch byte) bool { return ch != '\n' })
+}
+
+func (x *Lexer) scanBlockComment() error {
+	start := x.position()
+	depth := 0
+
+	for x.offset < len(x.text) {
+		switch {
+		case x.hasPrefix("/*"):
+			depth++
+			x.advance(2)
+		case x.hasPrefix("*/"):
+			depth--
+			x.advance(2)
+			if depth == 0 {
+				return nil
+			}
+		default:
+			x.advance(1)
+		}
+	}
+
+	return &SyntaxError{Pos: start, Message: "unterminated block comment"}
+}
+
+func (x *Lexer) scanString(escape bool) error {
+	start := x.position()
+	// skip the opening quote
+	x.advance(1)
+
+	for x.offset < len(x.text) {
+		ch := x.text[x.offset]
+		switch {
+		case escape && ch == '\\':
+			x.advance(2)
+		case ch == '\'' && x.peek(1) == '\'':
+			x.advance(2)
+		case ch == '\'':
+			x.advance(1)
+			return nil
+		default:
+			x.advance(1)
+		}
+	}
+
+	return &SyntaxError{Pos: start, Message: "unterminated quoted string"}
+}
+
+func (x *Lexer) scanQuotedIdent() error {
+	start := x.position()
+	// skip the opening quote
+	x.advance(1)
+
+	for x.offset < len(x.text) {
+		ch := x.text[x.offset]
+		switch {
+		case ch == '"' && x.peek(1) == '"':
+			x.advance(2)
+		case ch == '"':
+			x.advance(1)
+			return nil
+		default:
+			x.advance(1)
+		}
+	}
+
+	return &SyntaxError{Pos: start, Message: "unterminated quoted identifier"}
+}
+
+// dollarTag returns the dollar-quote tag (e.g. $$ or $body$) at the current offset.
+func (x *Lexer) dollarTag() (string, bool) {
+	for index := x.offset + 1; index < len(x.text); index++ {
+		ch := x.text[index]
+		switch {
+		case ch == '$':
+			return x.text[x.offset : index+1], true
+		case index == x.offset+1 && isDigit(ch):
+			return "", false
+		case !isIdentPart(ch):
+			return "", false
+		}
+	}
+
+	return "", false
+}
+
+func (x *Lexer) scanDollarString(tag string) error {
+	start := x.position()
+	x.advance(len(tag))
+
+	if index := strings.Index(x.text[x.offset:], tag); index >= 0 {
+		x.advance(index + len(tag))
+		return nil
+	}
+
+	x.advance(len(x.text))
+	return &SyntaxError{Pos: start, Message: fmt.Sprintf("unterminated dollar-quoted string %s", tag)}
+}
+
+func (x *Lexer) scanNumber() {
+	x.scanWhile(func(ch byte) bool { return isDigit(ch) || ch == '_' })
+
+	if x.peek(0) == '.' && x.peek(1) != '.' {
+		x.advance(1)
+		x.scanWhile(func(ch byte) bool { return isDigit(ch) || ch == '_' })
+	}
+
+	if ch := x.peek(0); ch == 'e' || ch == 'E' {
+		next := x.peek(1)
+		if isDigit(next) || ((next == '+' || next == '-') && isDigit(x.peek(2))) {
+			x.advance(2)
+			x.scanWhile(isDigit)
+		}
+	}
+}
+
+func (x *Lexer) scanIdent() {
+	for x.offset < len(x.text) {
+		ch, size := utf8.DecodeRuneInString(x.text[x.offset:])
+		if !isIdentStart(ch) && !unicode.IsDigit(ch) && ch != '$' {
+			return
+		}
+		x.advance(size)
+	}
+}
+
+func (x *Lexer) scanOperator() {
+	const symbols = "+-*/<>=~!@#%^&|`?:"
+
+	// An invalid byte is decoded as utf8.RuneError of size 1
+	if ch, size := utf8.DecodeRuneInString(x.text[x.offset:]); !strings.ContainsRune(symbols, ch) {
+		x.advance(size)
+		return
+	}
+
+	for x.offset < len(x.text) && strings.IndexByte(symbols, x.text[x.offset]) >= 0 {
+		// comments start inside an operator sequence
+		if x.hasPrefix("--") || x.hasPrefix("/*") {
+			return
+		}
+		x.advance(1)
+	}
+}
+
+func isDigit(ch byte) bool {
+	return ch >= '0' && ch <= '9'
+}
+
+func isIdentStart(ch rune) bool {
+	return ch == '_' || unicode.IsLetter(ch)
+}
+
+func isIdentPart(ch byte) bool {
+	return ch == '_' || isDigit(ch) || (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z') || ch >= utf8.RuneSelf
+}
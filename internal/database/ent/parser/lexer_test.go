@@ -0,0 +1,102 @@
+package parser_test
+
+import (
+	"github.com/aws-contrib/aurora/internal/database/ent/parser"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Tokenize", func() {
+	kinds := func(tokens []*parser.Token) []parser.TokenKind {
+		var collection []parser.TokenKind
+		for _, token := range tokens {
+			collection = append(collection, token.Kind)
+		}
+		return collection
+	}
+
+	It("tokenizes a statement", func() {
+		tokens, err := parser.Tokenize(`SELECT "Name", 'a''b', 1.5e3, $1 FROM users;`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(kinds(tokens)).To(Equal([]parser.TokenKind{
+			parser.TokenKeyword,
+			parser.TokenIdent,
+			parser.TokenOperator,
+			parser.TokenString,
+			parser.TokenOperator,
+			parser.TokenNumber,
+			parser.TokenOperator,
+			parser.TokenParam,
+			parser.TokenKeyword,
+			parser.TokenKeyword,
+			parser.TokenSemicolon,
+		}))
+		Expect(tokens[1].Value()).To(Equal("Name"))
+		Expect(tokens[9].Value()).To(Equal("users"))
+	})
+
+	It("tracks lines and columns", func() {
+		tokens, err := parser.Tokenize("SELECT\n  1;")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tokens[1].Pos).To(Equal(parser.Position{Offset: 9, Line: 2, Column: 3}))
+	})
+
+	It("tokenizes invalid bytes one at a time", func() {
+		tokens, err := parser.Tokenize("\xff'a;b';")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(kinds(tokens)).To(Equal([]parser.TokenKind{
+			parser.TokenOperator,
+			parser.TokenString,
+			parser.TokenSemicolon,
+		}))
+		Expect(tokens[0].Text).To(Equal("\xff"))
+		Expect(tokens[1].Text).To(Equal("'a;b'"))
+	})
+
+	It("tokenizes escape strings", func() {
+		tokens, err := parser.Tokenize(`E'it\'s;'`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tokens).To(HaveLen(1))
+		Expect(tokens[0].Kind).To(Equal(parser.TokenString))
+	})
+
+	It("tokenizes dollar-quoted strings", func() {
+		tokens, err := parser.Tokenize(`$body$ SELECT 1; $$ $body$`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tokens).To(HaveLen(1))
+		Expect(tokens[0].Kind).To(Equal(parser.TokenString))
+	})
+
+	It("tokenizes nested block comments", func() {
+		tokens, err := parser.Tokenize(`/* a /* b; */ c; */ SELECT`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(kinds(tokens)).To(Equal([]parser.TokenKind{
+			parser.TokenComment,
+			parser.TokenKeyword,
+		}))
+	})
+
+	It("tokenizes line comments", func() {
+		tokens, err := parser.Tokenize("SELECT 1 -- one;\n;")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(kinds(tokens)).To(Equal([]parser.TokenKind{
+			parser.TokenKeyword,
+			parser.TokenNumber,
+			parser.TokenComment,
+			parser.TokenSemicolon,
+		}))
+	})
+
+	DescribeTable("unterminated tokens",
+		func(text, msg string) {
+			tokens, err := parser.Tokenize(text)
+			Expect(err).To(MatchError(msg))
+			Expect(tokens).To(BeEmpty())
+		},
+		Entry("string", "SELECT 'a", "1:8: unterminated quoted string"),
+		Entry("identifier", `SELECT "a`, "1:8: unterminated quoted identifier"),
+		Entry("dollar-quoted string", "SELECT $x$ a", "1:8: unterminated dollar-quoted string $x$"),
+		Entry("block comment", "/* /* */", "1:1: unterminated block comment"),
+	)
+})
@@ -0,0 +1,183 @@
+package parser
+
+import (
+	"strings"
+)
+
+// Statement represents a single SQL statement in a migration file.
+type Statement struct {
+	// Text is the statement text, including the terminating semicolon when present.
+	Text string
+	// Pos is the position where the statement starts in the file.
+	Pos Position
+	// Tokens are the tokens of the statement, including inline comments.
+	Tokens []*Token
+	// Comments are the comments that precede the statement.
+	Comments []*Token
+}
+
+// String returns the statement text.
+func (x *Statement) String() string {
+	return x.Text
+}
+
+// Split splits the source text into statements. Semicolons inside string
+// literals, quoted identifiers, dollar-quoted bodies, comments and the
+// BEGIN ATOMIC ... END body of a function or procedure do not terminate a
+// statement. Comments between statements are attached to the statement that
+// follows them. Blocks that contain only comments are not returned.
+func Split(text string) ([]*Statement, error) {
+	lexer := NewLexer(text)
+
+	var (
+		collection []*Statement
+		comments   []*Token
+		current    *Statement
+		// depth is the nesting of the BEGIN and CASE blocks of a routine
+		// body, as psql counts them
+		depth int
+		begin *Token
+	)
+
+	flush := func(end int) {
+		current.Text = text[current.Pos.Offset:end]
+		collection = append(collection, current)
+		current = nil
+	}
+
+	for {
+		token, err := lexer.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		if token == nil {
+			break
+		}
+
+		switch {
+		case current == nil && token.Kind == TokenComment:
+			comments = append(comments, token)
+		case current == nil && token.Kind == TokenSemicolon:
+			// empty statement
+			comments = nil
+		case current == nil:
+			current = &Statement{
+				Pos:      token.Pos,
+				Tokens:   []*Token{token},
+				Comments: comments,
+			}
+			comments = nil
+		case token.Kind == TokenSemicolon && depth == 0:
+			current.Tokens = append(current.Tokens, token)
+			flush(token.Pos.Offset + len(token.Text))
+		default:
+			current.Tokens = append(current.Tokens, token)
+
+			switch {
+			case (token.Is("BEGIN") || token.Is("CASE")) && isRoutine(current.Tokens):
+				if depth == 0 {
+					begin = token
+				}
+				depth++
+			case token.Is("END") && depth > 0:
+				depth--
+			}
+		}
+	}
+
+	if depth > 0 {
+		return nil, &SyntaxError{Pos: begin.Pos, Message: "unterminated BEGIN block"}
+	}
+
+	if current != nil {
+		// the last statement does not have a terminating semicolon
+		for current.Tokens[len(current.Tokens)-1].Kind == TokenComment {
+			current.Tokens = current.Tokens[:len(current.Tokens)-1]
+		}
+
+		last := current.Tokens[len(current.Tokens)-1]
+		flush(last.Pos.Offset + len(last.Text))
+	}
+
+	return collection, nil
+}
+
+// isRoutine reports whether the tokens start a CREATE [OR REPLACE] FUNCTION
+// or PROCEDURE statement, whose SQL-standard body can contain semicolons.
+func isRoutine(tokens []*Token) bool {
+	var keywords []*Token
+	for _, token := range tokens {
+		if token.Kind == TokenComment {
+			continue
+		}
+
+		if keywords = append(keywords, token); len(keywords) == 4 {
+			break
+		}
+	}
+
+	if len(keywords) < 2 || !keywords[0].Is("CREATE") {
+		return false
+	}
+
+	if keywords = keywords[1:]; len(keywords) >= 3 && keywords[0].Is("OR") && keywords[1].Is("REPLACE") {
+		keywords = keywords[2:]
+	}
+
+	return keywords[0].Is("FUNCTION") || keywords[0].Is("PROCEDURE")
+}
+
+// StripComments returns the statement text without comments.
+func (x *Statement) StripComments() string {
+	var (
+		builder strings.Builder
+		offset  = x.Pos.Offset
+	)
+
+	for _, token := range x.Tokens {
+		if token.Kind != TokenComment {
+			continue
+		}
+
+		builder.WriteString(x.Text[offset-x.Pos.Offset : token.Pos.Offset-x.Pos.Offset])
+		// keep the tokens around a block comment apart
+		if strings.HasPrefix(token.Text, "/*") {
+			builder.WriteString(" ")
+		}
+
+		offset = token.Pos.Offset + len(token.Text)
+	}
+
+	builder.WriteString(x.Text[offset-x.Pos.Offset:])
+	return builder.String()
+}
+
+// HasDirective reports whether the comments that precede the statement
+// contain the given directive (e.g. "+down").
+func (x *Statement) HasDirective(name string) bool {
+	_, ok := Directive(x.Comments, name)
+	return ok
+}
+
+// Directive returns the arguments of the directive with the given name.
+// A directive is a line comment in the form "-- name [args]".
+func Directive(comments []*Token, name string) (string, bool) {
+	for _, comment := range comments {
+		text, ok := strings.CutPrefix(comment.Text, "--")
+		if !ok {
+			continue
+		}
+
+		text = strings.TrimSpace(text)
+		if text == name {
+			return "", true
+		}
+
+		if args, ok := strings.CutPrefix(text, name+" "); ok {
+			return strings.TrimSpace(args), true
+		}
+	}
+
+	return "", false
+}
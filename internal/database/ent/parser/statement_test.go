@@ -0,0 +1,132 @@
+package parser_test
+
+import (
+	"github.com/aws-contrib/aurora/internal/database/ent/parser"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Split", func() {
+	texts := func(statements []*parser.Statement) []string {
+		var collection []string
+		for _, stmt := range statements {
+			collection = append(collection, stmt.Text)
+		}
+		return collection
+	}
+
+	It("splits the statements", func() {
+		statements, err := parser.Split("CREATE TABLE a (id INT);\n\nCREATE TABLE b (id INT);\n")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(texts(statements)).To(Equal([]string{
+			"CREATE TABLE a (id INT);",
+			"CREATE TABLE b (id INT);",
+		}))
+		Expect(statements[1].Pos).To(Equal(parser.Position{Offset: 26, Line: 3, Column: 1}))
+	})
+
+	It("ignores semicolons inside literals and comments", func() {
+		text := "INSERT INTO \"a;b\" VALUES ('x;y', E'\\';');\n" +
+			"/* skip; */ CREATE FUNCTION f() RETURNS INT AS $fn$ SELECT 1; $fn$ LANGUAGE SQL;\n" +
+			"SELECT 1 -- one;\n;"
+
+		statements, err := parser.Split(text)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(texts(statements)).To(Equal([]string{
+			"INSERT INTO \"a;b\" VALUES ('x;y', E'\\';');",
+			"CREATE FUNCTION f() RETURNS INT AS $fn$ SELECT 1; $fn$ LANGUAGE SQL;",
+			"SELECT 1 -- one;\n;",
+		}))
+	})
+
+	It("keeps the SQL-standard routine bodies", func() {
+		text := "CREATE OR REPLACE FUNCTION f() RETURNS INT LANGUAGE SQL\n" +
+			"BEGIN ATOMIC\n  SELECT CASE WHEN true THEN 1 END;\n  SELECT 2;\nEND;\n" +
+			"CREATE PROCEDURE p() BEGIN ATOMIC INSERT INTO a VALUES (1); END;\n" +
+			"BEGIN;\nSELECT CASE WHEN true THEN 1 END;\nEND;"
+
+		statements, err := parser.Split(text)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(texts(statements)).To(Equal([]string{
+			"CREATE OR REPLACE FUNCTION f() RETURNS INT LANGUAGE SQL\nBEGIN ATOMIC\n  SELECT CASE WHEN true THEN 1 END;\n  SELECT 2;\nEND;",
+			"CREATE PROCEDURE p() BEGIN ATOMIC INSERT INTO a VALUES (1); END;",
+			"BEGIN;",
+			"SELECT CASE WHEN true THEN 1 END;",
+			"END;",
+		}))
+	})
+
+	It("attaches the leading comments", func() {
+		statements, err := parser.Split("-- users table\nCREATE TABLE users (id INT);")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(statements).To(HaveLen(1))
+		Expect(statements[0].Comments).To(HaveLen(1))
+		Expect(statements[0].Comments[0].Text).To(Equal("-- users table"))
+		Expect(statements[0].Pos.Line).To(Equal(2))
+	})
+
+	It("keeps the last statement without semicolon", func() {
+		statements, err := parser.Split("SELECT 1;\nSELECT 2\n-- done\n")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(texts(statements)).To(Equal([]string{"SELECT 1;", "SELECT 2"}))
+	})
+
+	It("skips empty statements and comments", func() {
+		statements, err := parser.Split(";\n-- nothing\n/* here */;\n")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(statements).To(BeEmpty())
+	})
+
+	When("the text is not terminated", func() {
+		It("returns an error", func() {
+			statements, err := parser.Split("SELECT 1;\nSELECT $$ 1;")
+			Expect(err).To(MatchError("2:8: unterminated dollar-quoted string $$"))
+			Expect(statements).To(BeEmpty())
+		})
+
+		It("returns an error for an unterminated routine body", func() {
+			statements, err := parser.Split("CREATE FUNCTION f() RETURNS INT LANGUAGE SQL\nBEGIN ATOMIC\n  SELECT 1;\n")
+			Expect(err).To(MatchError("2:1: unterminated BEGIN block"))
+			Expect(statements).To(BeEmpty())
+		})
+	})
+})
+
+var _ = Describe("Statement", func() {
+	Describe("StripComments", func() {
+		It("removes the comments", func() {
+			statements, err := parser.Split("SELECT 1, -- one\n  '--two'/* three */FROM t;")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(statements).To(HaveLen(1))
+			Expect(statements[0].StripComments()).To(Equal("SELECT 1, \n  '--two' FROM t;"))
+		})
+	})
+})
+
+var _ = Describe("Directive", func() {
+	var comments []*parser.Token
+
+	BeforeEach(func() {
+		var err error
+		comments, err = parser.Tokenize("-- +down\n-- aurora:nolint idempotency\n/* +up */")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("returns the directive", func() {
+		args, ok := parser.Directive(comments, "+down")
+		Expect(ok).To(BeTrue())
+		Expect(args).To(BeEmpty())
+	})
+
+	It("returns the directive arguments", func() {
+		args, ok := parser.Directive(comments, "aurora:nolint")
+		Expect(ok).To(BeTrue())
+		Expect(args).To(Equal("idempotency"))
+	})
+
+	It("ignores block comments", func() {
+		_, ok := parser.Directive(comments, "+up")
+		Expect(ok).To(BeFalse())
+	})
+})
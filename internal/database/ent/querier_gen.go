@@ -9,6 +9,24 @@ import (
 )
 
 type Querier interface {
+	// Adds the column 'expires_at' to the table 'aurora_schema_locks'
+	AlterTableLocksAddExpiresAt(ctx context.Context) error
+	// Adds the column 'hostname' to the table 'aurora_schema_locks'
+	AlterTableLocksAddHostname(ctx context.Context) error
+	// Adds the column 'owner' to the table 'aurora_schema_locks'
+	AlterTableLocksAddOwner(ctx context.Context) error
+	// Adds the column 'pid' to the table 'aurora_schema_locks'
+	AlterTableLocksAddPid(ctx context.Context) error
+	// Adds the column 'version' to the table 'aurora_schema_locks'
+	AlterTableLocksAddVersion(ctx context.Context) error
+	// Adds the column 'hash' to the table 'aurora_schema_revisions'
+	AlterTableRevisionsAddHash(ctx context.Context) error
+	// Adds the column 'partial_hashes' to the table 'aurora_schema_revisions'
+	AlterTableRevisionsAddPartialHashes(ctx context.Context) error
+	// Adds the column 'revert_count' to the table 'aurora_schema_revisions'
+	AlterTableRevisionsAddRevertCount(ctx context.Context) error
+	// Adds the column 'reverted_at' to the table 'aurora_schema_revisions'
+	AlterTableRevisionsAddRevertedAt(ctx context.Context) error
 	// The schema 'sys' is created to hold system-related tables.
 	CreateSchemaSys(ctx context.Context) error
 	// Creates a table named 'sys.jobs' with the following columns:
@@ -16,6 +34,8 @@ type Querier interface {
 	CreateTableJobs(ctx context.Context) error
 	// Creates a table named 'aurora_schema_locks' with the following columns:
 	CreateTableLocks(ctx context.Context) error
+	// Creates a table named 'aurora_schema_revision_events' with the following columns:
+	CreateTableRevisionEvents(ctx context.Context) error
 	// Creates a table named 'aurora_schema_revisions' with the following columns:
 	CreateTableRevisions(ctx context.Context) error
 	// Deletes a row from the table 'sys.jobs' with option ':one'
@@ -36,22 +56,49 @@ type Querier interface {
 	ExecInsertLock(ctx context.Context, arg *ExecInsertLockParams) error
 	// Inserts a row into the table 'aurora_schema_revisions' with option ':exec'
 	ExecInsertRevision(ctx context.Context, arg *ExecInsertRevisionParams) error
+	// Inserts a row into the table 'aurora_schema_revision_events' with option ':exec'
+	ExecInsertRevisionEvent(ctx context.Context, arg *ExecInsertRevisionEventParams) error
+	// Extends the expiration of a row owned by the given owner in the table 'aurora_schema_locks'
+	ExecRefreshLock(ctx context.Context, arg *ExecRefreshLockParams) (int64, error)
+	// Deletes a row owned by the given owner from the table 'aurora_schema_locks'
+	ExecReleaseLock(ctx context.Context, arg *ExecReleaseLockParams) error
+	// Deletes a row from the table 'aurora_schema_locks' if it was not taken over since it was read.
+	// A new holder sets a new creation timestamp, which every version of the table has.
+	ExecReleaseObservedLock(ctx context.Context, arg *ExecReleaseObservedLockParams) (int64, error)
+	// Takes over a row from the table 'aurora_schema_locks' if it is still held by the observed owner
+	// and has expired according to the database clock. A row without expiration never expires.
+	ExecTakeoverLock(ctx context.Context, arg *ExecTakeoverLockParams) (int64, error)
 	// Updates a row in the table 'revision' with option ':exec'
 	ExecUpdateRevision(ctx context.Context, arg *ExecUpdateRevisionParams) error
+	// Records the outcome of a row of the table 'aurora_schema_revision_events' once its action is finished
+	ExecUpdateRevisionEvent(ctx context.Context, arg *ExecUpdateRevisionEventParams) error
 	// Upserts a row into the table 'aurora_schema_revisions' with option ':exec'
 	ExecUpsertRevision(ctx context.Context, arg *ExecUpsertRevisionParams) error
 	// Retrieves a row from the table 'sys.jobs' with option ':one'
 	GetJob(ctx context.Context, arg *GetJobParams) (*Job, error)
+	// Retrieves a row from the table 'aurora_schema_locks' with the columns of its first version,
+	// when the table was not upgraded yet.
+	GetLegacyLock(ctx context.Context, arg *GetLegacyLockParams) (*GetLegacyLockRow, error)
+	// Retrieves a row from the table 'aurora_schema_revisions' with the columns of its first version,
+	// when the table was not upgraded yet.
+	GetLegacyRevision(ctx context.Context, arg *GetLegacyRevisionParams) (*GetLegacyRevisionRow, error)
 	// Retrieves a row from the table 'aurora_schema_locks' with option ':one'
 	GetLock(ctx context.Context, arg *GetLockParams) (*Lock, error)
 	// Retrieves a row from the table 'aurora_schema_revisions' with option ':one'
 	GetRevision(ctx context.Context, arg *GetRevisionParams) (*Revision, error)
+	// Reports whether the database provides the view 'sys.iam_pg_role_mappings',
+	// which only Aurora DSQL has
+	HasIAMRoleMappings(ctx context.Context) (bool, error)
 	// Inserts a row into the table 'sys.jobs' with option ':one'
 	InsertJob(ctx context.Context, arg *InsertJobParams) (*Job, error)
 	// Inserts a row into the table 'aurora_schema_locks' with option ':one'
 	InsertLock(ctx context.Context, arg *InsertLockParams) (*Lock, error)
 	// Inserts a row into the table 'aurora_schema_revisions' with option ':one'
 	InsertRevision(ctx context.Context, arg *InsertRevisionParams) (*Revision, error)
+	// Inserts a row into the table 'aurora_schema_revision_events' with option ':one'
+	InsertRevisionEvent(ctx context.Context, arg *InsertRevisionEventParams) (*RevisionEvent, error)
+	// Retrieves a list of rows from the table 'aurora_schema_revision_events' with option ':many'
+	ListRevisionEvents(ctx context.Context, arg *ListRevisionEventsParams) ([]*RevisionEvent, error)
 	// Retrieves a list of rows from the table 'aurora_schema_revisions' with option ':many'
 	ListRevisions(ctx context.Context, arg *ListRevisionsParams) ([]*Revision, error)
 	// Updates a row in the table 'revision' with option ':one'
@@ -0,0 +1,10 @@
+-- sqlfluff:dialect:postgres
+-- sqlfluff:max_line_length:1024
+-- sqlfluff:rules:capitalisation.keywords:capitalisation_policy:upper
+
+SET search_path TO public;
+
+-- Reports whether the database provides the view 'sys.iam_pg_role_mappings',
+-- which only Aurora DSQL has
+-- name: HasIAMRoleMappings :one
+SELECT (to_regclass('sys.iam_pg_role_mappings') IS NOT NULL)::BOOLEAN AS exists;
@@ -0,0 +1,140 @@
+-- sqlfluff:dialect:postgres
+-- sqlfluff:max_line_length:1024
+-- sqlfluff:rules:capitalisation.keywords:capitalisation_policy:upper
+
+SET search_path TO public;
+
+-- Creates a table named 'aurora_schema_revision_events' with the following columns:
+-- name: CreateTableRevisionEvents :exec
+CREATE TABLE IF NOT EXISTS aurora_schema_revision_events (
+    -- primary key column
+    id TEXT PRIMARY KEY,
+    -- revision of the event
+    revision_id TEXT NOT NULL,
+    -- action that was taken on the revision
+    action TEXT NOT NULL,
+    -- error issued by the action
+    error TEXT NULL,
+    -- operator who took the action
+    operator TEXT NULL,
+    -- version of the tool that took the action
+    version TEXT NULL,
+    -- creation timestamp column
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    -- position of the statement in the migration file
+    statement_index INT NULL,
+    -- statement that was executed
+    statement TEXT NULL,
+    -- asynchronous job created by the statement
+    job_id TEXT NULL,
+    -- outcome of the action
+    outcome TEXT NOT NULL,
+    -- start timestamp column
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    -- end timestamp column
+    finished_at TIMESTAMP WITH TIME ZONE NULL
+);
+
+-- Inserts a row into the table 'aurora_schema_revision_events' with option ':one'
+-- name: InsertRevisionEvent :one
+INSERT INTO aurora_schema_revision_events (
+    id,
+    revision_id,
+    action,
+    error,
+    operator,
+    version,
+    created_at,
+    statement_index,
+    statement,
+    job_id,
+    outcome,
+    started_at,
+    finished_at
+) VALUES (
+    sqlc.arg(id),
+    sqlc.arg(revision_id),
+    sqlc.arg(action),
+    sqlc.narg(error),
+    sqlc.narg(operator),
+    sqlc.narg(version),
+    sqlc.arg(created_at),
+    sqlc.narg(statement_index),
+    sqlc.narg(statement),
+    sqlc.narg(job_id),
+    sqlc.arg(outcome),
+    sqlc.arg(started_at),
+    sqlc.narg(finished_at)
+)
+RETURNING *;
+
+-- Inserts a row into the table 'aurora_schema_revision_events' with option ':exec'
+-- name: ExecInsertRevisionEvent :exec
+INSERT INTO aurora_schema_revision_events (
+    id,
+    revision_id,
+    action,
+    error,
+    operator,
+    version,
+    created_at,
+    statement_index,
+    statement,
+    job_id,
+    outcome,
+    started_at,
+    finished_at
+) VALUES (
+    sqlc.arg(id),
+    sqlc.arg(revision_id),
+    sqlc.arg(action),
+    sqlc.narg(error),
+    sqlc.narg(operator),
+    sqlc.narg(version),
+    sqlc.arg(created_at),
+    sqlc.narg(statement_index),
+    sqlc.narg(statement),
+    sqlc.narg(job_id),
+    sqlc.arg(outcome),
+    sqlc.arg(started_at),
+    sqlc.narg(finished_at)
+);
+
+-- Records the outcome of a row of the table 'aurora_schema_revision_events' once its action is finished
+-- name: ExecUpdateRevisionEvent :exec
+UPDATE aurora_schema_revision_events
+SET
+    job_id = sqlc.narg(job_id),
+    outcome = sqlc.arg(outcome),
+    error = sqlc.narg(error),
+    finished_at = sqlc.narg(finished_at)
+WHERE
+    id = sqlc.arg(id);
+
+-- Retrieves a list of rows from the table 'aurora_schema_revision_events' with option ':many'
+-- name: ListRevisionEvents :many
+SELECT
+    id,
+    revision_id,
+    action,
+    error,
+    operator,
+    version,
+    created_at,
+    statement_index,
+    statement,
+    job_id,
+    outcome,
+    started_at,
+    finished_at
+FROM
+    aurora_schema_revision_events
+WHERE
+    revision_id = COALESCE(sqlc.narg(revision_id), revision_id)
+ORDER BY
+    started_at,
+    id
+LIMIT
+    sqlc.narg(page_limit)::INT
+    OFFSET
+    sqlc.narg(page_offset)::INT;
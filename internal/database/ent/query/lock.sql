@@ -10,11 +10,54 @@ CREATE TABLE IF NOT EXISTS aurora_schema_locks (
     -- primary key column
     id TEXT PRIMARY KEY,
     -- execution timestamp column
-    created_at TIMESTAMP WITH TIME ZONE NOT NULL
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    -- owner columns
+    owner TEXT NULL,
+    hostname TEXT NULL,
+    pid INT NULL,
+    version TEXT NULL,
+    -- expiration timestamp column
+    expires_at TIMESTAMP WITH TIME ZONE NULL
 );
 
+-- Adds the column 'owner' to the table 'aurora_schema_locks'
+-- name: AlterTableLocksAddOwner :exec
+ALTER TABLE aurora_schema_locks ADD COLUMN IF NOT EXISTS owner TEXT;
+
+-- Adds the column 'hostname' to the table 'aurora_schema_locks'
+-- name: AlterTableLocksAddHostname :exec
+ALTER TABLE aurora_schema_locks ADD COLUMN IF NOT EXISTS hostname TEXT;
+
+-- Adds the column 'pid' to the table 'aurora_schema_locks'
+-- name: AlterTableLocksAddPid :exec
+ALTER TABLE aurora_schema_locks ADD COLUMN IF NOT EXISTS pid INT;
+
+-- Adds the column 'version' to the table 'aurora_schema_locks'
+-- name: AlterTableLocksAddVersion :exec
+ALTER TABLE aurora_schema_locks ADD COLUMN IF NOT EXISTS version TEXT;
+
+-- Adds the column 'expires_at' to the table 'aurora_schema_locks'
+-- name: AlterTableLocksAddExpiresAt :exec
+ALTER TABLE aurora_schema_locks ADD COLUMN IF NOT EXISTS expires_at TIMESTAMP WITH TIME ZONE;
+
 -- Retrieves a row from the table 'aurora_schema_locks' with option ':one'
 -- name: GetLock :one
+SELECT
+    id,
+    created_at,
+    owner,
+    hostname,
+    pid,
+    version,
+    expires_at
+FROM
+    aurora_schema_locks
+WHERE
+    id = sqlc.arg(id);
+
+-- Retrieves a row from the table 'aurora_schema_locks' with the columns of its first version,
+-- when the table was not upgraded yet.
+-- name: GetLegacyLock :one
 SELECT
     id,
     created_at
@@ -27,10 +70,20 @@ WHERE
 -- name: InsertLock :one
 INSERT INTO aurora_schema_locks (
     id,
-    created_at
+    created_at,
+    owner,
+    hostname,
+    pid,
+    version,
+    expires_at
 ) VALUES (
     sqlc.arg(id),
-    sqlc.arg(created_at)
+    sqlc.arg(created_at),
+    sqlc.narg(owner),
+    sqlc.narg(hostname),
+    sqlc.narg(pid),
+    sqlc.narg(version),
+    sqlc.narg(expires_at)
 )
 RETURNING *;
 
@@ -38,12 +91,64 @@ RETURNING *;
 -- name: ExecInsertLock :exec
 INSERT INTO aurora_schema_locks (
     id,
-    created_at
+    created_at,
+    owner,
+    hostname,
+    pid,
+    version,
+    expires_at
 ) VALUES (
     sqlc.arg(id),
-    sqlc.arg(created_at)
+    sqlc.arg(created_at),
+    sqlc.narg(owner),
+    sqlc.narg(hostname),
+    sqlc.narg(pid),
+    sqlc.narg(version),
+    sqlc.narg(expires_at)
 );
 
+-- Extends the expiration of a row owned by the given owner in the table 'aurora_schema_locks'
+-- name: ExecRefreshLock :execrows
+UPDATE aurora_schema_locks
+SET
+    expires_at = sqlc.narg(expires_at)
+WHERE
+    id = sqlc.arg(id)
+    AND owner = sqlc.narg(owner);
+
+-- Takes over a row from the table 'aurora_schema_locks' if it is still held by the observed owner
+-- and has expired according to the database clock. A row without expiration never expires.
+-- name: ExecTakeoverLock :execrows
+UPDATE aurora_schema_locks
+SET
+    created_at = sqlc.arg(created_at),
+    owner = sqlc.narg(owner),
+    hostname = sqlc.narg(hostname),
+    pid = sqlc.narg(pid),
+    version = sqlc.narg(version),
+    expires_at = sqlc.narg(expires_at)
+WHERE
+    id = sqlc.arg(id)
+    AND created_at = sqlc.arg(observed_created_at)
+    AND owner IS NOT DISTINCT FROM sqlc.narg(observed_owner)
+    AND expires_at IS NOT DISTINCT FROM sqlc.narg(observed_expires_at)
+    AND expires_at < now();
+
+-- Deletes a row owned by the given owner from the table 'aurora_schema_locks'
+-- name: ExecReleaseLock :exec
+DELETE FROM aurora_schema_locks
+WHERE
+    id = sqlc.arg(id)
+    AND owner = sqlc.narg(owner);
+
+-- Deletes a row from the table 'aurora_schema_locks' if it was not taken over since it was read.
+-- A new holder sets a new creation timestamp, which every version of the table has.
+-- name: ExecReleaseObservedLock :execrows
+DELETE FROM aurora_schema_locks
+WHERE
+    id = sqlc.arg(id)
+    AND created_at = sqlc.arg(observed_created_at);
+
 -- Deletes a row from the table 'aurora_schema_locks' with option ':one'
 -- name: DeleteLock :one
 DELETE FROM aurora_schema_locks
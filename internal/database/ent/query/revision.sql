@@ -22,11 +22,56 @@ CREATE TABLE IF NOT EXISTS aurora_schema_revisions (
     -- execution timestamp column
     executed_at TIMESTAMP WITH TIME ZONE NOT NULL,
     -- execution time column
-    execution_time BIGINT NOT NULL DEFAULT 0
+    execution_time BIGINT NOT NULL DEFAULT 0,
+    -- count of down statements executed while reverting the revision
+    revert_count INT NULL,
+    -- revert timestamp column
+    reverted_at TIMESTAMP WITH TIME ZONE NULL,
+    -- checksum of the revision file
+    hash TEXT NULL,
+    -- checksums of the executed statements
+    partial_hashes TEXT NULL
 );
 
+-- Adds the column 'revert_count' to the table 'aurora_schema_revisions'
+-- name: AlterTableRevisionsAddRevertCount :exec
+ALTER TABLE aurora_schema_revisions ADD COLUMN IF NOT EXISTS revert_count INT;
+
+-- Adds the column 'reverted_at' to the table 'aurora_schema_revisions'
+-- name: AlterTableRevisionsAddRevertedAt :exec
+ALTER TABLE aurora_schema_revisions ADD COLUMN IF NOT EXISTS reverted_at TIMESTAMP WITH TIME ZONE;
+
+-- Adds the column 'hash' to the table 'aurora_schema_revisions'
+-- name: AlterTableRevisionsAddHash :exec
+ALTER TABLE aurora_schema_revisions ADD COLUMN IF NOT EXISTS hash TEXT;
+
+-- Adds the column 'partial_hashes' to the table 'aurora_schema_revisions'
+-- name: AlterTableRevisionsAddPartialHashes :exec
+ALTER TABLE aurora_schema_revisions ADD COLUMN IF NOT EXISTS partial_hashes TEXT;
+
 -- Retrieves a row from the table 'aurora_schema_revisions' with option ':one'
 -- name: GetRevision :one
+SELECT
+    id,
+    description,
+    total,
+    count,
+    error,
+    error_stmt,
+    executed_at,
+    execution_time,
+    revert_count,
+    reverted_at,
+    hash,
+    partial_hashes
+FROM
+    aurora_schema_revisions
+WHERE
+    id = sqlc.arg(id);
+
+-- Retrieves a row from the table 'aurora_schema_revisions' with the columns of its first version,
+-- when the table was not upgraded yet.
+-- name: GetLegacyRevision :one
 SELECT
     id,
     description,
@@ -51,7 +96,9 @@ INSERT INTO aurora_schema_revisions (
     error,
     error_stmt,
     executed_at,
-    execution_time
+    execution_time,
+    hash,
+    partial_hashes
 ) VALUES (
     sqlc.arg(id),
     sqlc.arg(description),
@@ -60,7 +107,9 @@ INSERT INTO aurora_schema_revisions (
     sqlc.narg(error),
     sqlc.narg(error_stmt),
     sqlc.arg(executed_at),
-    sqlc.arg(execution_time)
+    sqlc.arg(execution_time),
+    sqlc.narg(hash),
+    sqlc.narg(partial_hashes)
 )
 RETURNING *;
 
@@ -74,7 +123,9 @@ INSERT INTO aurora_schema_revisions (
     error,
     error_stmt,
     executed_at,
-    execution_time
+    execution_time,
+    hash,
+    partial_hashes
 ) VALUES (
     sqlc.arg(id),
     sqlc.arg(description),
@@ -83,7 +134,9 @@ INSERT INTO aurora_schema_revisions (
     sqlc.narg(error),
     sqlc.narg(error_stmt),
     sqlc.arg(executed_at),
-    sqlc.arg(execution_time)
+    sqlc.arg(execution_time),
+    sqlc.narg(hash),
+    sqlc.narg(partial_hashes)
 );
 
 -- Upserts a row into the table 'aurora_schema_revisions' with option ':one'
@@ -96,7 +149,9 @@ INSERT INTO aurora_schema_revisions (
     error,
     error_stmt,
     executed_at,
-    execution_time
+    execution_time,
+    hash,
+    partial_hashes
 ) VALUES (
     sqlc.arg(id),
     sqlc.arg(description),
@@ -105,7 +160,9 @@ INSERT INTO aurora_schema_revisions (
     sqlc.narg(error),
     sqlc.narg(error_stmt),
     sqlc.arg(executed_at),
-    sqlc.arg(execution_time)
+    sqlc.arg(execution_time),
+    sqlc.narg(hash),
+    sqlc.narg(partial_hashes)
 )
 ON CONFLICT (id) DO UPDATE SET id = sqlc.arg(id)
 RETURNING *;
@@ -120,7 +177,9 @@ INSERT INTO aurora_schema_revisions (
     error,
     error_stmt,
     executed_at,
-    execution_time
+    execution_time,
+    hash,
+    partial_hashes
 ) VALUES (
     sqlc.arg(id),
     sqlc.arg(description),
@@ -129,7 +188,9 @@ INSERT INTO aurora_schema_revisions (
     sqlc.narg(error),
     sqlc.narg(error_stmt),
     sqlc.arg(executed_at),
-    sqlc.arg(execution_time)
+    sqlc.arg(execution_time),
+    sqlc.narg(hash),
+    sqlc.narg(partial_hashes)
 )
 ON CONFLICT (id) DO UPDATE SET id = sqlc.arg(id);
 
@@ -172,6 +233,26 @@ SET
         WHEN 'execution_time' = ANY(sqlc.arg(update_mask)::TEXT [])
             THEN sqlc.arg(execution_time)
         ELSE execution_time
+    END,
+    revert_count = CASE
+        WHEN 'revert_count' = ANY(sqlc.arg(update_mask)::TEXT [])
+            THEN sqlc.narg(revert_count)
+        ELSE revert_count
+    END,
+    reverted_at = CASE
+        WHEN 'reverted_at' = ANY(sqlc.arg(update_mask)::TEXT [])
+            THEN sqlc.narg(reverted_at)
+        ELSE reverted_at
+    END,
+    hash = CASE
+        WHEN 'hash' = ANY(sqlc.arg(update_mask)::TEXT [])
+            THEN sqlc.narg(hash)
+        ELSE hash
+    END,
+    partial_hashes = CASE
+        WHEN 'partial_hashes' = ANY(sqlc.arg(update_mask)::TEXT [])
+            THEN sqlc.narg(partial_hashes)
+        ELSE partial_hashes
     END
 WHERE
     id = sqlc.arg(id)
@@ -215,6 +296,26 @@ SET
         WHEN 'execution_time' = ANY(sqlc.arg(update_mask)::TEXT [])
             THEN sqlc.arg(execution_time)
         ELSE execution_time
+    END,
+    revert_count = CASE
+        WHEN 'revert_count' = ANY(sqlc.arg(update_mask)::TEXT [])
+            THEN sqlc.narg(revert_count)
+        ELSE revert_count
+    END,
+    reverted_at = CASE
+        WHEN 'reverted_at' = ANY(sqlc.arg(update_mask)::TEXT [])
+            THEN sqlc.narg(reverted_at)
+        ELSE reverted_at
+    END,
+    hash = CASE
+        WHEN 'hash' = ANY(sqlc.arg(update_mask)::TEXT [])
+            THEN sqlc.narg(hash)
+        ELSE hash
+    END,
+    partial_hashes = CASE
+        WHEN 'partial_hashes' = ANY(sqlc.arg(update_mask)::TEXT [])
+            THEN sqlc.narg(partial_hashes)
+        ELSE partial_hashes
     END
 WHERE
     id = sqlc.arg(id);
@@ -240,7 +341,11 @@ SELECT
     error,
     error_stmt,
     executed_at,
-    execution_time
+    execution_time,
+    revert_count,
+    reverted_at,
+    hash,
+    partial_hashes
 FROM
     aurora_schema_revisions
 ORDER BY
@@ -10,6 +10,46 @@ import (
 	"time"
 )
 
+const alterTableRevisionsAddHash = `-- name: AlterTableRevisionsAddHash :exec
+ALTER TABLE aurora_schema_revisions ADD COLUMN IF NOT EXISTS hash TEXT
+`
+
+// Adds the column 'hash' to the table 'aurora_schema_revisions'
+func (q *Queries) AlterTableRevisionsAddHash(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, alterTableRevisionsAddHash)
+	return err
+}
+
+const alterTableRevisionsAddPartialHashes = `-- name: AlterTableRevisionsAddPartialHashes :exec
+ALTER TABLE aurora_schema_revisions ADD COLUMN IF NOT EXISTS partial_hashes TEXT
+`
+
+// Adds the column 'partial_hashes' to the table 'aurora_schema_revisions'
+func (q *Queries) AlterTableRevisionsAddPartialHashes(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, alterTableRevisionsAddPartialHashes)
+	return err
+}
+
+const alterTableRevisionsAddRevertCount = `-- name: AlterTableRevisionsAddRevertCount :exec
+ALTER TABLE aurora_schema_revisions ADD COLUMN IF NOT EXISTS revert_count INT
+`
+
+// Adds the column 'revert_count' to the table 'aurora_schema_revisions'
+func (q *Queries) AlterTableRevisionsAddRevertCount(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, alterTableRevisionsAddRevertCount)
+	return err
+}
+
+const alterTableRevisionsAddRevertedAt = `-- name: AlterTableRevisionsAddRevertedAt :exec
+ALTER TABLE aurora_schema_revisions ADD COLUMN IF NOT EXISTS reverted_at TIMESTAMP WITH TIME ZONE
+`
+
+// Adds the column 'reverted_at' to the table 'aurora_schema_revisions'
+func (q *Queries) AlterTableRevisionsAddRevertedAt(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, alterTableRevisionsAddRevertedAt)
+	return err
+}
+
 const createTableRevisions = `-- name: CreateTableRevisions :exec
 CREATE TABLE IF NOT EXISTS aurora_schema_revisions (
     -- primary key column
@@ -27,7 +67,15 @@ CREATE TABLE IF NOT EXISTS aurora_schema_revisions (
     -- execution timestamp column
     executed_at TIMESTAMP WITH TIME ZONE NOT NULL,
     -- execution time column
-    execution_time BIGINT NOT NULL DEFAULT 0
+    execution_time BIGINT NOT NULL DEFAULT 0,
+    -- count of down statements executed while reverting the revision
+    revert_count INT NULL,
+    -- revert timestamp column
+    reverted_at TIMESTAMP WITH TIME ZONE NULL,
+    -- checksum of the revision file
+    hash TEXT NULL,
+    -- checksums of the executed statements
+    partial_hashes TEXT NULL
 )
 `
 
@@ -40,7 +88,7 @@ func (q *Queries) CreateTableRevisions(ctx context.Context) error {
 const deleteRevision = `-- name: DeleteRevision :one
 DELETE FROM aurora_schema_revisions
 WHERE id = $1
-RETURNING id, description, total, count, error, error_stmt, executed_at, execution_time
+RETURNING id, description, total, count, error, error_stmt, executed_at, execution_time, revert_count, reverted_at, hash, partial_hashes
 `
 
 type DeleteRevisionParams struct {
@@ -60,6 +108,10 @@ func (q *Queries) DeleteRevision(ctx context.Context, arg *DeleteRevisionParams)
 		&i.ErrorStmt,
 		&i.ExecutedAt,
 		&i.ExecutionTime,
+		&i.RevertCount,
+		&i.RevertedAt,
+		&i.Hash,
+		&i.PartialHashes,
 	)
 	return &i, err
 }
@@ -88,7 +140,9 @@ INSERT INTO aurora_schema_revisions (
     error,
     error_stmt,
     executed_at,
-    execution_time
+    execution_time,
+    hash,
+    partial_hashes
 ) VALUES (
     $1,
     $2,
@@ -97,7 +151,9 @@ INSERT INTO aurora_schema_revisions (
     $5,
     $6,
     $7,
-    $8
+    $8,
+    $9,
+    $10
 )
 `
 
@@ -110,6 +166,8 @@ type ExecInsertRevisionParams struct {
 	ErrorStmt     *string       `db:"error_stmt" json:"error_stmt"`
 	ExecutedAt    time.Time     `db:"executed_at" json:"executed_at"`
 	ExecutionTime time.Duration `db:"execution_time" json:"execution_time"`
+	Hash          *string       `db:"hash" json:"hash"`
+	PartialHashes Hashes        `db:"partial_hashes" json:"partial_hashes"`
 }
 
 // Inserts a row into the table 'aurora_schema_revisions' with option ':exec'
@@ -123,6 +181,8 @@ func (q *Queries) ExecInsertRevision(ctx context.Context, arg *ExecInsertRevisio
 		arg.ErrorStmt,
 		arg.ExecutedAt,
 		arg.ExecutionTime,
+		arg.Hash,
+		arg.PartialHashes,
 	)
 	return err
 }
@@ -164,9 +224,29 @@ SET
         WHEN 'execution_time' = ANY($1::TEXT [])
             THEN $8
         ELSE execution_time
+    END,
+    revert_count = CASE
+        WHEN 'revert_count' = ANY($1::TEXT [])
+            THEN $9
+        ELSE revert_count
+    END,
+    reverted_at = CASE
+        WHEN 'reverted_at' = ANY($1::TEXT [])
+            THEN $10
+        ELSE reverted_at
+    END,
+    hash = CASE
+        WHEN 'hash' = ANY($1::TEXT [])
+            THEN $11
+        ELSE hash
+    END,
+    partial_hashes = CASE
+        WHEN 'partial_hashes' = ANY($1::TEXT [])
+            THEN $12
+        ELSE partial_hashes
     END
 WHERE
-    id = $9
+    id = $13
 `
 
 type ExecUpdateRevisionParams struct {
@@ -178,6 +258,10 @@ type ExecUpdateRevisionParams struct {
 	ErrorStmt     *string       `db:"error_stmt" json:"error_stmt"`
 	ExecutedAt    time.Time     `db:"executed_at" json:"executed_at"`
 	ExecutionTime time.Duration `db:"execution_time" json:"execution_time"`
+	RevertCount   *int          `db:"revert_count" json:"revert_count"`
+	RevertedAt    *time.Time    `db:"reverted_at" json:"reverted_at"`
+	Hash          *string       `db:"hash" json:"hash"`
+	PartialHashes Hashes        `db:"partial_hashes" json:"partial_hashes"`
 	ID            string        `db:"id" json:"id"`
 }
 
@@ -192,6 +276,10 @@ func (q *Queries) ExecUpdateRevision(ctx context.Context, arg *ExecUpdateRevisio
 		arg.ErrorStmt,
 		arg.ExecutedAt,
 		arg.ExecutionTime,
+		arg.RevertCount,
+		arg.RevertedAt,
+		arg.Hash,
+		arg.PartialHashes,
 		arg.ID,
 	)
 	return err
@@ -206,7 +294,9 @@ INSERT INTO aurora_schema_revisions (
     error,
     error_stmt,
     executed_at,
-    execution_time
+    execution_time,
+    hash,
+    partial_hashes
 ) VALUES (
     $1,
     $2,
@@ -215,7 +305,9 @@ INSERT INTO aurora_schema_revisions (
     $5,
     $6,
     $7,
-    $8
+    $8,
+    $9,
+    $10
 )
 ON CONFLICT (id) DO UPDATE SET id = $1
 `
@@ -229,6 +321,8 @@ type ExecUpsertRevisionParams struct {
 	ErrorStmt     *string       `db:"error_stmt" json:"error_stmt"`
 	ExecutedAt    time.Time     `db:"executed_at" json:"executed_at"`
 	ExecutionTime time.Duration `db:"execution_time" json:"execution_time"`
+	Hash          *string       `db:"hash" json:"hash"`
+	PartialHashes Hashes        `db:"partial_hashes" json:"partial_hashes"`
 }
 
 // Upserts a row into the table 'aurora_schema_revisions' with option ':exec'
@@ -242,11 +336,13 @@ func (q *Queries) ExecUpsertRevision(ctx context.Context, arg *ExecUpsertRevisio
 		arg.ErrorStmt,
 		arg.ExecutedAt,
 		arg.ExecutionTime,
+		arg.Hash,
+		arg.PartialHashes,
 	)
 	return err
 }
 
-const getRevision = `-- name: GetRevision :one
+const getLegacyRevision = `-- name: GetLegacyRevision :one
 SELECT
     id,
     description,
@@ -262,6 +358,59 @@ WHERE
     id = $1
 `
 
+type GetLegacyRevisionParams struct {
+	ID string `db:"id" json:"id"`
+}
+
+type GetLegacyRevisionRow struct {
+	ID            string        `db:"id" json:"id"`
+	Description   string        `db:"description" json:"description"`
+	Total         int           `db:"total" json:"total"`
+	Count         int           `db:"count" json:"count"`
+	Error         *string       `db:"error" json:"error"`
+	ErrorStmt     *string       `db:"error_stmt" json:"error_stmt"`
+	ExecutedAt    time.Time     `db:"executed_at" json:"executed_at"`
+	ExecutionTime time.Duration `db:"execution_time" json:"execution_time"`
+}
+
+// Retrieves a row from the table 'aurora_schema_revisions' with the columns of its first version,
+// when the table was not upgraded yet.
+func (q *Queries) GetLegacyRevision(ctx context.Context, arg *GetLegacyRevisionParams) (*GetLegacyRevisionRow, error) {
+	row := q.db.QueryRow(ctx, getLegacyRevision, arg.ID)
+	var i GetLegacyRevisionRow
+	err := row.Scan(
+		&i.ID,
+		&i.Description,
+		&i.Total,
+		&i.Count,
+		&i.Error,
+		&i.ErrorStmt,
+		&i.ExecutedAt,
+		&i.ExecutionTime,
+	)
+	return &i, err
+}
+
+const getRevision = `-- name: GetRevision :one
+SELECT
+    id,
+    description,
+    total,
+    count,
+    error,
+    error_stmt,
+    executed_at,
+    execution_time,
+    revert_count,
+    reverted_at,
+    hash,
+    partial_hashes
+FROM
+    aurora_schema_revisions
+WHERE
+    id = $1
+`
+
 type GetRevisionParams struct {
 	ID string `db:"id" json:"id"`
 }
@@ -279,6 +428,10 @@ func (q *Queries) GetRevision(ctx context.Context, arg *GetRevisionParams) (*Rev
 		&i.ErrorStmt,
 		&i.ExecutedAt,
 		&i.ExecutionTime,
+		&i.RevertCount,
+		&i.RevertedAt,
+		&i.Hash,
+		&i.PartialHashes,
 	)
 	return &i, err
 }
@@ -292,7 +445,9 @@ INSERT INTO aurora_schema_revisions (
     error,
     error_stmt,
     executed_at,
-    execution_time
+    execution_time,
+    hash,
+    partial_hashes
 ) VALUES (
     $1,
     $2,
@@ -301,9 +456,11 @@ INSERT INTO aurora_schema_revisions (
     $5,
     $6,
     $7,
-    $8
+    $8,
+    $9,
+    $10
 )
-RETURNING id, description, total, count, error, error_stmt, executed_at, execution_time
+RETURNING id, description, total, count, error, error_stmt, executed_at, execution_time, revert_count, reverted_at, hash, partial_hashes
 `
 
 type InsertRevisionParams struct {
@@ -315,6 +472,8 @@ type InsertRevisionParams struct {
 	ErrorStmt     *string       `db:"error_stmt" json:"error_stmt"`
 	ExecutedAt    time.Time     `db:"executed_at" json:"executed_at"`
 	ExecutionTime time.Duration `db:"execution_time" json:"execution_time"`
+	Hash          *string       `db:"hash" json:"hash"`
+	PartialHashes Hashes        `db:"partial_hashes" json:"partial_hashes"`
 }
 
 // Inserts a row into the table 'aurora_schema_revisions' with option ':one'
@@ -328,6 +487,8 @@ func (q *Queries) InsertRevision(ctx context.Context, arg *InsertRevisionParams)
 		arg.ErrorStmt,
 		arg.ExecutedAt,
 		arg.ExecutionTime,
+		arg.Hash,
+		arg.PartialHashes,
 	)
 	var i Revision
 	err := row.Scan(
@@ -339,6 +500,10 @@ func (q *Queries) InsertRevision(ctx context.Context, arg *InsertRevisionParams)
 		&i.ErrorStmt,
 		&i.ExecutedAt,
 		&i.ExecutionTime,
+		&i.RevertCount,
+		&i.RevertedAt,
+		&i.Hash,
+		&i.PartialHashes,
 	)
 	return &i, err
 }
@@ -352,7 +517,11 @@ SELECT
     error,
     error_stmt,
     executed_at,
-    execution_time
+    execution_time,
+    revert_count,
+    reverted_at,
+    hash,
+    partial_hashes
 FROM
     aurora_schema_revisions
 ORDER BY
@@ -387,6 +556,10 @@ func (q *Queries) ListRevisions(ctx context.Context, arg *ListRevisionsParams) (
 			&i.ErrorStmt,
 			&i.ExecutedAt,
 			&i.ExecutionTime,
+			&i.RevertCount,
+			&i.RevertedAt,
+			&i.Hash,
+			&i.PartialHashes,
 		); err != nil {
 			return nil, err
 		}
@@ -435,10 +608,30 @@ SET
         WHEN 'execution_time' = ANY($1::TEXT [])
             THEN $8
         ELSE execution_time
+    END,
+    revert_count = CASE
+        WHEN 'revert_count' = ANY($1::TEXT [])
+            THEN $9
+        ELSE revert_count
+    END,
+    reverted_at = CASE
+        WHEN 'reverted_at' = ANY($1::TEXT [])
+            THEN $10
+        ELSE reverted_at
+    END,
+    hash = CASE
+        WHEN 'hash' = ANY($1::TEXT [])
+            THEN $11
+        ELSE hash
+    END,
+    partial_hashes = CASE
+        WHEN 'partial_hashes' = ANY($1::TEXT [])
+            THEN $12
+        ELSE partial_hashes
     END
 WHERE
-    id = $9
-RETURNING id, description, total, count, error, error_stmt, executed_at, execution_time
+    id = $13
+RETURNING id, description, total, count, error, error_stmt, executed_at, execution_time, revert_count, reverted_at, hash, partial_hashes
 `
 
 type UpdateRevisionParams struct {
@@ -450,6 +643,10 @@ type UpdateRevisionParams struct {
 	ErrorStmt     *string       `db:"error_stmt" json:"error_stmt"`
 	ExecutedAt    time.Time     `db:"executed_at" json:"executed_at"`
 	ExecutionTime time.Duration `db:"execution_time" json:"execution_time"`
+	RevertCount   *int          `db:"revert_count" json:"revert_count"`
+	RevertedAt    *time.Time    `db:"reverted_at" json:"reverted_at"`
+	Hash          *string       `db:"hash" json:"hash"`
+	PartialHashes Hashes        `db:"partial_hashes" json:"partial_hashes"`
 	ID            string        `db:"id" json:"id"`
 }
 
@@ -464,6 +661,10 @@ func (q *Queries) UpdateRevision(ctx context.Context, arg *UpdateRevisionParams)
 		arg.ErrorStmt,
 		arg.ExecutedAt,
 		arg.ExecutionTime,
+		arg.RevertCount,
+		arg.RevertedAt,
+		arg.Hash,
+		arg.PartialHashes,
 		arg.ID,
 	)
 	var i Revision
@@ -476,6 +677,10 @@ func (q *Queries) UpdateRevision(ctx context.Context, arg *UpdateRevisionParams)
 		&i.ErrorStmt,
 		&i.ExecutedAt,
 		&i.ExecutionTime,
+		&i.RevertCount,
+		&i.RevertedAt,
+		&i.Hash,
+		&i.PartialHashes,
 	)
 	return &i, err
 }
@@ -489,7 +694,9 @@ INSERT INTO aurora_schema_revisions (
     error,
     error_stmt,
     executed_at,
-    execution_time
+    execution_time,
+    hash,
+    partial_hashes
 ) VALUES (
     $1,
     $2,
@@ -498,10 +705,12 @@ INSERT INTO aurora_schema_revisions (
     $5,
     $6,
     $7,
-    $8
+    $8,
+    $9,
+    $10
 )
 ON CONFLICT (id) DO UPDATE SET id = $1
-RETURNING id, description, total, count, error, error_stmt, executed_at, execution_time
+RETURNING id, description, total, count, error, error_stmt, executed_at, execution_time, revert_count, reverted_at, hash, partial_hashes
 `
 
 type UpsertRevisionParams struct {
@@ -513,6 +722,8 @@ type UpsertRevisionParams struct {
 	ErrorStmt     *string       `db:"error_stmt" json:"error_stmt"`
 	ExecutedAt    time.Time     `db:"executed_at" json:"executed_at"`
 	ExecutionTime time.Duration `db:"execution_time" json:"execution_time"`
+	Hash          *string       `db:"hash" json:"hash"`
+	PartialHashes Hashes        `db:"partial_hashes" json:"partial_hashes"`
 }
 
 // Upserts a row into the table 'aurora_schema_revisions' with option ':one'
@@ -526,6 +737,8 @@ func (q *Queries) UpsertRevision(ctx context.Context, arg *UpsertRevisionParams)
 		arg.ErrorStmt,
 		arg.ExecutedAt,
 		arg.ExecutionTime,
+		arg.Hash,
+		arg.PartialHashes,
 	)
 	var i Revision
 	err := row.Scan(
@@ -537,6 +750,10 @@ func (q *Queries) UpsertRevision(ctx context.Context, arg *UpsertRevisionParams)
 		&i.ErrorStmt,
 		&i.ExecutedAt,
 		&i.ExecutionTime,
+		&i.RevertCount,
+		&i.RevertedAt,
+		&i.Hash,
+		&i.PartialHashes,
 	)
 	return &i, err
 }
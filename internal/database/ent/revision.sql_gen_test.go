@@ -92,6 +92,22 @@ var _ = Describe("Gateway", Ordered, func() {
 			})
 		})
 
+		Describe("ExecReleaseObservedLock", func() {
+			var params *ent.ExecReleaseObservedLockParams
+
+			BeforeEach(func() {
+				params = &ent.ExecReleaseObservedLockParams{}
+				params.ID = entity.ID
+				params.ObservedCreatedAt = entity.CreatedAt
+			})
+
+			It("deletes the observed revision", func(ctx SpecContext) {
+				count, err := gateway.ExecReleaseObservedLock(ctx, params)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(count).To(BeEquivalentTo(1))
+			})
+		})
+
 		Describe("ExecDeleteLock", func() {
 			var params *ent.ExecDeleteLockParams
 
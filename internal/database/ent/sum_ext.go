@@ -0,0 +1,150 @@
+package ent
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding"
+	"encoding/base64"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+)
+
+// HashFileName is the name of the integrity file of the migration directory.
+const HashFileName = "atlas.sum"
+
+// FileHash represents the checksum of a single migration file.
+type FileHash struct {
+	// Name is the name of the migration file.
+	Name string
+	// Hash is the base64 encoded checksum of the file.
+	Hash string
+}
+
+// HashFile represents the integrity file of the migration directory. The
+// format is compatible with the atlas.sum file created by Atlas.
+type HashFile []*FileHash
+
+// NewHashFile computes the integrity file of the migration files in the given file system.
+func NewHashFile(fsys fs.FS) (HashFile, error) {
+	matches, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		collection HashFile
+		hash       = sha256.New()
+	)
+
+	for _, path := range sort.StringSlice(matches) {
+		data, err := fs.ReadFile(fsys, path)
+		if err != nil {
+			return nil, err
+		}
+		// The checksum of each file includes all the files before it
+		hash.Write([]byte(path))
+		hash.Write(data)
+
+		collection = append(collection, &FileHash{
+			Name: path,
+			Hash: base64.StdEncoding.EncodeToString(hash.Sum(nil)),
+		})
+	}
+
+	return collection, nil
+}
+
+// Sum returns the checksum of the whole integrity file.
+func (x HashFile) Sum() string {
+	hash := sha256.New()
+
+	for _, item := range x {
+		hash.Write([]byte(item.Name))
+		hash.Write([]byte(item.Hash))
+	}
+
+	return base64.StdEncoding.EncodeToString(hash.Sum(nil))
+}
+
+// Compare returns an error that describes the first difference between the files.
+func (x HashFile) Compare(other HashFile) error {
+	files := make(map[string]string, len(other))
+
+	for _, item := range other {
+		files[item.Name] = item.Hash
+	}
+
+	for _, item := range x {
+		hash, ok := files[item.Name]
+		switch {
+		case !ok:
+			return fmt.Errorf("%w: %s was removed", ErrChecksumMismatch, item.Name)
+		case hash != item.Hash:
+			return fmt.Errorf("%w: %s was changed", ErrChecksumMismatch, item.Name)
+		}
+
+		delete(files, item.Name)
+	}
+
+	for _, item := range other {
+		if _, ok := files[item.Name]; ok {
+			return fmt.Errorf("%w: %s was added", ErrChecksumMismatch, item.Name)
+		}
+	}
+
+	return nil
+}
+
+var _ encoding.TextMarshaler = HashFile(nil)
+
+// MarshalText implements encoding.TextMarshaler.
+func (x HashFile) MarshalText() ([]byte, error) {
+	buffer := &bytes.Buffer{}
+	fmt.Fprintf(buffer, "h1:%s\n", x.Sum())
+
+	for _, item := range x {
+		fmt.Fprintf(buffer, "%s h1:%s\n", item.Name, item.Hash)
+	}
+
+	return buffer.Bytes(), nil
+}
+
+var _ encoding.TextUnmarshaler = (*HashFile)(nil)
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (x *HashFile) UnmarshalText(text []byte) error {
+	scanner := bufio.NewScanner(bytes.NewReader(text))
+	// The first line contains the sum of the file
+	if !scanner.Scan() {
+		return fmt.Errorf("%s: the file is empty", HashFileName)
+	}
+
+	sum := strings.TrimPrefix(strings.TrimSpace(scanner.Text()), "h1:")
+
+	var collection HashFile
+	for scanner.Scan() {
+		name, hash, ok := strings.Cut(scanner.Text(), "h1:")
+		if !ok {
+			return fmt.Errorf("%s: the line %q is invalid", HashFileName, scanner.Text())
+		}
+
+		collection = append(collection, &FileHash{
+			Name: strings.TrimSpace(name),
+			Hash: strings.TrimSpace(hash),
+		})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	if sum != collection.Sum() {
+		return fmt.Errorf("%w: %s was edited manually", ErrChecksumMismatch, HashFileName)
+	}
+
+	*x = collection
+	return nil
+}
@@ -0,0 +1,111 @@
+package ent_test
+
+import (
+	"testing/fstest"
+
+	"github.com/aws-contrib/aurora/internal/database/ent"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("HashFile", func() {
+	const text = "h1:zqTLb8p2BS4UJYXVUPjCtqhDOnRh3P/xgSxCN4JV7Zc=\n" +
+		"1_init.sql h1:4Fd7f4+rcjKVSeHBv7KbM1QW3GBV2ByecnAAQWVLIdA=\n" +
+		"2_users.sql h1:Wq2bd8jITn0BifW5RT/6gl7vKjZmicSGZ5zge6RFA1w=\n"
+
+	var fsys fstest.MapFS
+
+	BeforeEach(func() {
+		fsys = fstest.MapFS{
+			"1_init.sql":  &fstest.MapFile{Data: []byte("CREATE TABLE a (id INT);\n")},
+			"2_users.sql": &fstest.MapFile{Data: []byte("CREATE TABLE b (id INT);\n")},
+			"README.md":   &fstest.MapFile{Data: []byte("# migrations")},
+		}
+	})
+
+	Describe("NewHashFile", func() {
+		It("computes the integrity file", func() {
+			file, err := ent.NewHashFile(fsys)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(file).To(HaveLen(2))
+
+			data, err := file.MarshalText()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).To(Equal(text))
+		})
+	})
+
+	Describe("UnmarshalText", func() {
+		It("unmarshals the integrity file", func() {
+			var file ent.HashFile
+			Expect(file.UnmarshalText([]byte(text))).To(Succeed())
+			Expect(file).To(HaveLen(2))
+			Expect(file[1].Name).To(Equal("2_users.sql"))
+		})
+
+		When("the file was edited manually", func() {
+			It("returns an error", func() {
+				var file ent.HashFile
+				Expect(file.UnmarshalText([]byte(text + "3_posts.sql h1:abc\n"))).To(MatchError(ent.ErrChecksumMismatch))
+			})
+		})
+
+		When("the file is invalid", func() {
+			It("returns an error", func() {
+				var file ent.HashFile
+				Expect(file.UnmarshalText([]byte("h1:abc\ninvalid\n"))).To(MatchError(`atlas.sum: the line "invalid" is invalid`))
+			})
+		})
+	})
+
+	Describe("Compare", func() {
+		var expected ent.HashFile
+
+		BeforeEach(func() {
+			Expect(expected.UnmarshalText([]byte(text))).To(Succeed())
+		})
+
+		It("returns no error", func() {
+			actual, err := ent.NewHashFile(fsys)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(expected.Compare(actual)).To(Succeed())
+		})
+
+		When("a file was changed", func() {
+			BeforeEach(func() {
+				fsys["1_init.sql"] = &fstest.MapFile{Data: []byte("CREATE TABLE c (id INT);\n")}
+			})
+
+			It("returns an error", func() {
+				actual, err := ent.NewHashFile(fsys)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(expected.Compare(actual)).To(MatchError("checksum mismatch: 1_init.sql was changed"))
+			})
+		})
+
+		When("a file was added", func() {
+			BeforeEach(func() {
+				fsys["3_posts.sql"] = &fstest.MapFile{Data: []byte("CREATE TABLE c (id INT);\n")}
+			})
+
+			It("returns an error", func() {
+				actual, err := ent.NewHashFile(fsys)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(expected.Compare(actual)).To(MatchError("checksum mismatch: 3_posts.sql was added"))
+			})
+		})
+
+		When("a file was removed", func() {
+			BeforeEach(func() {
+				delete(fsys, "2_users.sql")
+			})
+
+			It("returns an error", func() {
+				actual, err := ent.NewHashFile(fsys)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(expected.Compare(actual)).To(MatchError("checksum mismatch: 2_users.sql was removed"))
+			})
+		})
+	})
+})
@@ -0,0 +1,103 @@
+package template
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"text/template"
+
+	"go.yaml.in/yaml/v3"
+)
+
+const (
+	// FormatText renders the colored template of the report. It is the default format.
+	FormatText = "text"
+	// FormatJSON renders the data as indented JSON.
+	FormatJSON = "json"
+	// FormatYAML renders the data as YAML.
+	FormatYAML = "yaml"
+)
+
+// Format renders the data in the given format, writing the output to the
+// provided writer. Any format other than text, json and yaml is parsed as
+// a Go template (e.g. '{{ .Current.ID }}').
+func Format(w io.Writer, name, format string, data any) error {
+	switch format {
+	case FormatText, "":
+		return Execute(w, name, data)
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(data)
+	case FormatYAML:
+		return encodeYAML(w, data)
+	}
+
+	funcs := template.FuncMap{
+		"json": func(value any) (string, error) {
+			data, err := json.Marshal(value)
+			return string(data), err
+		},
+	}
+
+	for key, fn := range FunMap {
+		funcs[key] = fn
+	}
+
+	tmpl, err := template.New(name).Funcs(funcs).Parse(format)
+	if err != nil {
+		return err
+	}
+
+	if err := tmpl.Execute(w, data); err != nil {
+		return err
+	}
+
+	// Custom templates rarely end with a new line
+	if !strings.HasSuffix(format, "\n") {
+		_, err = io.WriteString(w, "\n")
+	}
+
+	return err
+}
+
+// encodeYAML writes the data as YAML. The data is encoded as JSON first, so
+// the YAML document has the same keys as the JSON one.
+func encodeYAML(w io.Writer, data any) error {
+	text, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	node := &yaml.Node{}
+	if err := yaml.Unmarshal(text, node); err != nil {
+		return err
+	}
+
+	// JSON is parsed in flow style with quoted strings
+	var plain func(*yaml.Node)
+	plain = func(node *yaml.Node) {
+		node.Style = 0
+		for _, child := range node.Content {
+			plain(child)
+		}
+	}
+
+	plain(node)
+
+	buffer := &bytes.Buffer{}
+	encoder := yaml.NewEncoder(buffer)
+	encoder.SetIndent(2)
+
+	if err := encoder.Encode(node); err != nil {
+		return err
+	}
+
+	if err := encoder.Close(); err != nil {
+		return err
+	}
+
+	_, err = w.Write(buffer.Bytes())
+	return err
+}
@@ -0,0 +1,181 @@
+package template_test
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/aws-contrib/aurora/internal/database/ent"
+	"github.com/aws-contrib/aurora/internal/database/ent/lint"
+	"github.com/aws-contrib/aurora/internal/database/ent/parser"
+	"github.com/aws-contrib/aurora/internal/database/ent/template"
+
+	. "github.com/aws-contrib/aurora/internal/database/ent/fake"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Format", func() {
+	var (
+		buffer *bytes.Buffer
+		state  *ent.MigrationState
+	)
+
+	BeforeEach(func() {
+		buffer = &bytes.Buffer{}
+
+		state = &ent.MigrationState{}
+		state.Current = NewFakeRevision()
+		state.Executed = append(state.Executed, state.Current)
+		state.Next = NewFakeRevision()
+		state.Pending = append(state.Pending, state.Next)
+		state.Lock = NewFakeLock()
+	})
+
+	It("renders the colored template", func() {
+		Expect(template.Format(buffer, "status", template.FormatText, state)).To(Succeed())
+		Expect(buffer.String()).To(ContainSubstring("Migration Status:"))
+		Expect(buffer.String()).To(ContainSubstring("Locked By:"))
+	})
+
+	When("the format is json", func() {
+		It("renders the state", func() {
+			Expect(template.Format(buffer, "status", template.FormatJSON, state)).To(Succeed())
+
+			var document map[string]any
+			Expect(json.Unmarshal(buffer.Bytes(), &document)).To(Succeed())
+			Expect(document).To(HaveKeyWithValue("current", HaveKeyWithValue("id", state.Current.ID)))
+			Expect(document).To(HaveKeyWithValue("pending", HaveLen(1)))
+			Expect(document).To(HaveKeyWithValue("lock", HaveKeyWithValue("hostname", "localhost")))
+		})
+	})
+
+	When("the format is yaml", func() {
+		It("renders the state", func() {
+			Expect(template.Format(buffer, "status", template.FormatYAML, state)).To(Succeed())
+			Expect(buffer.String()).To(ContainSubstring("current:\n  id: " + state.Current.ID + "\n"))
+			Expect(buffer.String()).To(ContainSubstring("  hostname: localhost\n"))
+		})
+	})
+
+	When("the format is a template", func() {
+		It("renders the template", func() {
+			Expect(template.Format(buffer, "status", "{{ .Current.ID }} {{ len .Pending }}", state)).To(Succeed())
+			Expect(buffer.String()).To(Equal(state.Current.ID + " 1\n"))
+		})
+
+		It("supports the json function", func() {
+			Expect(template.Format(buffer, "status", "{{ json .Lock.Hostname }}", state)).To(Succeed())
+			Expect(buffer.String()).To(Equal("\"localhost\"\n"))
+		})
+
+		When("the template is invalid", func() {
+			It("returns an error", func() {
+				Expect(template.Format(buffer, "status", "{{ .Current.ID", state)).NotTo(Succeed())
+			})
+		})
+	})
+
+	When("the template is the plan", func() {
+		It("renders the plan", func() {
+			plan := &ent.MigrationPlan{}
+			plan.Revision = NewFakeRevision()
+			plan.Statements = append(plan.Statements, &ent.MigrationPlanStatement{
+				Query: "CREATE INDEX ASYNC idx_name ON example (name);",
+				Async: true,
+			})
+
+			Expect(template.Format(buffer, "plan", template.FormatText, []*ent.MigrationPlan{plan})).To(Succeed())
+			Expect(buffer.String()).To(ContainSubstring("1 pending files"))
+			Expect(buffer.String()).To(ContainSubstring(plan.Revision.GetName()))
+			Expect(buffer.String()).To(ContainSubstring("[async job]"))
+			Expect(buffer.String()).To(ContainSubstring("CREATE INDEX ASYNC idx_name ON example (name);"))
+		})
+
+		It("renders the destructive statements", func() {
+			plan := &ent.MigrationPlan{}
+			plan.Revision = NewFakeRevision()
+			plan.Statements = append(plan.Statements, &ent.MigrationPlanStatement{
+				Query:       "DROP TABLE IF EXISTS legacy;",
+				Destructive: []*lint.Finding{{Rule: "drop-table", Severity: lint.SeverityWarning}},
+			})
+
+			Expect(template.Format(buffer, "plan", template.FormatText, []*ent.MigrationPlan{plan})).To(Succeed())
+			Expect(buffer.String()).To(ContainSubstring("[destructive: drop-table, not allowed]"))
+
+			buffer.Reset()
+			plan.AllowDestructive = true
+
+			Expect(template.Format(buffer, "plan", template.FormatText, []*ent.MigrationPlan{plan})).To(Succeed())
+			Expect(buffer.String()).To(ContainSubstring("[destructive: drop-table]"))
+		})
+
+		It("renders the baseline revisions", func() {
+			plan := &ent.MigrationPlan{}
+			plan.Revision = NewFakeRevision()
+			plan.Revision.Count = 3
+			plan.Baseline = true
+
+			Expect(template.Format(buffer, "plan", template.FormatText, []*ent.MigrationPlan{plan})).To(Succeed())
+			Expect(buffer.String()).To(ContainSubstring("[baseline]"))
+			Expect(buffer.String()).To(ContainSubstring("3 statements recorded as applied"))
+		})
+	})
+
+	When("the template is the history", func() {
+		It("renders the events", func() {
+			msg := "oh no"
+
+			failed := NewFakeRevisionEvent()
+			failed.Outcome = ent.RevisionEventFailed
+			failed.Error = &msg
+
+			Expect(template.Format(buffer, "history", template.FormatText, []*ent.RevisionEvent{NewFakeRevisionEvent(), failed})).To(Succeed())
+			Expect(buffer.String()).To(ContainSubstring("2 events"))
+			Expect(buffer.String()).To(ContainSubstring(failed.RevisionID))
+			Expect(buffer.String()).To(ContainSubstring("#1"))
+			Expect(buffer.String()).To(ContainSubstring("500ms"))
+			Expect(buffer.String()).To(ContainSubstring("root@localhost (v1.0.0)"))
+			Expect(buffer.String()).To(ContainSubstring("oh no"))
+		})
+
+		It("renders the running events", func() {
+			running := NewFakeRevisionEvent()
+			running.Outcome = ent.RevisionEventRunning
+			running.FinishedAt = nil
+
+			Expect(template.Format(buffer, "history", template.FormatText, []*ent.RevisionEvent{running})).To(Succeed())
+			Expect(buffer.String()).To(ContainSubstring("running"))
+			Expect(buffer.String()).To(ContainSubstring("0s"))
+		})
+
+		It("renders no events", func() {
+			Expect(template.Format(buffer, "history", template.FormatText, []*ent.RevisionEvent{})).To(Succeed())
+			Expect(buffer.String()).To(ContainSubstring("NO EVENTS"))
+		})
+	})
+
+	When("the template is the lint", func() {
+		It("renders the findings", func() {
+			finding := &lint.Finding{
+				File:       "20250101000000_schema.sql",
+				Pos:        parser.Position{Line: 3, Column: 16},
+				Rule:       "foreign-key",
+				Severity:   lint.SeverityError,
+				Message:    "Aurora DSQL does not support foreign key constraints",
+				Suggestion: "remove the constraint",
+			}
+
+			Expect(template.Format(buffer, "lint", template.FormatText, []*lint.Finding{finding})).To(Succeed())
+			Expect(buffer.String()).To(ContainSubstring("1 findings"))
+			Expect(buffer.String()).To(ContainSubstring("ERROR"))
+			Expect(buffer.String()).To(ContainSubstring("20250101000000_schema.sql:3:16"))
+			Expect(buffer.String()).To(ContainSubstring("(foreign-key)"))
+			Expect(buffer.String()).To(ContainSubstring("remove the constraint"))
+		})
+
+		It("renders no findings", func() {
+			Expect(template.Format(buffer, "lint", template.FormatText, []*lint.Finding{})).To(Succeed())
+			Expect(buffer.String()).To(ContainSubstring("NO ISSUES"))
+		})
+	})
+})
@@ -0,0 +1,14 @@
+Migration History: {{ if . }}{{ cyan "%d events" (len .) }}{{- else }}{{ green "NO EVENTS" }}{{- end }}
+{{- if . }}
+
+  {{ printf "%-16s %-25s %-15s %-6s %-10s %-12s %s" "VERSION" "STARTED AT" "ACTION" "STMT" "OUTCOME" "DURATION" "OPERATOR" }}
+{{- range . }}
+  {{ cyan "%-16s" .RevisionID }} {{ printf "%-25s" (.StartedAt.Format "2006-01-02T15:04:05Z07:00") }} {{ printf "%-15s" .Action }} {{ printf "%-6s" .GetPosition }} {{ if eq .Outcome "failed" }}{{ red "%-10s" .Outcome }}{{- else if eq .Outcome "running" }}{{ yellow "%-10s" .Outcome }}{{- else }}{{ green "%-10s" .Outcome }}{{- end }} {{ printf "%-12s" .GetDuration.String }} {{ with .Operator }}{{ . }}{{- else }}unknown{{- end }}{{ with .Version }} ({{ . }}){{- end }}
+{{- with .JobID }}
+     {{ yellow "--" }} Job: {{ . }}
+{{- end }}
+{{- with .Error }}
+     {{ red "--" }} {{ . }}
+{{- end }}
+{{- end }}
+{{- end }}
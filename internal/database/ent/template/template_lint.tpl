@@ -0,0 +1,6 @@
+Migration Lint: {{ if . }}{{ yellow "%d findings" (len .) }}{{- else }}{{ green "NO ISSUES" }}{{- end }}
+{{- range . }}
+
+  {{ if eq .Severity "error" }}{{ red "ERROR" }}{{- else }}{{ yellow "WARNING" }}{{- end }} {{ cyan "%s:%s" .File .Pos }}: {{ .Message }} ({{ .Rule }})
+     {{ yellow "--" }} {{ .Suggestion }}
+{{- end }}
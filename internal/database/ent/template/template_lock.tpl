@@ -0,0 +1,7 @@
+Lock Status: {{ if . }}{{ yellow "LOCKED" }}{{- else }}{{ green "UNLOCKED" }}{{- end }}
+{{- with . }}
+  {{ yellow "--" }} Owner:      {{ cyan .GetOwner }}
+  {{ yellow "--" }} Version:    {{ with .Version }}{{ . }}{{- else }}unknown{{- end }}
+  {{ yellow "--" }} Locked At:  {{ .CreatedAt.Format "2006-01-02T15:04:05Z07:00" }}
+  {{ yellow "--" }} Expires At: {{ with .ExpiresAt }}{{ .Format "2006-01-02T15:04:05Z07:00" }}{{- else }}NONE{{- end }}
+{{- end }}
@@ -0,0 +1,15 @@
+Migration Plan: {{ if . }}{{ yellow "%d pending files" (len .) }}{{- else }}{{ green "NOTHING TO APPLY" }}{{- end }}
+{{- range . }}
+{{- if .Baseline }}
+
+  {{ yellow "--" }} Version {{ cyan .Revision.ID }} ({{ .Revision.GetName }}): {{ yellow "[baseline]" }} {{ .Revision.Count }} statements recorded as applied
+{{- else }}
+
+  {{ yellow "--" }} Version {{ cyan .Revision.ID }} ({{ .Revision.GetName }}): {{ len .Statements }} statements
+{{- $allow := .AllowDestructive }}
+{{- range .Statements }}
+     {{ .Pos }}{{ if .Async }} {{ yellow "[async job]" }}{{ end }}{{ range .Destructive }} {{ if $allow }}{{ yellow "[destructive: %s]" .Rule }}{{ else }}{{ red "[destructive: %s, not allowed]" .Rule }}{{ end }}{{ end }}
+     {{ .Query }}
+{{- end }}
+{{- end }}
+{{- end }}
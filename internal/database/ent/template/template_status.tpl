@@ -3,6 +3,16 @@ Migration Status: {{ if .Current }}{{- if eq .Current.Count .Current.Total }}{{
   {{ yellow "--" }} Next Version:    {{ if .Next }}{{ cyan .Next.ID }}{{- if lt .Next.Count .Next.Total }}{{ printf " (%s statements left)" (yellow "%d" (sub .Next.Total .Next.Count)) }}{{- end }}{{- else }}NONE{{- end }}
   {{ yellow "--" }} Executed Files:  {{ len .Executed }}{{ if and .Current (lt .Current.Count .Current.Total) }} (last one partially){{ end }}
   {{ yellow "--" }} Pending Files:   {{ len .Pending }}
+{{- with .Lock }}
+  {{ yellow "--" }} Locked By:       {{ cyan .GetOwner }} since {{ .CreatedAt.Format "2006-01-02T15:04:05Z07:00" }}
+{{- end }}
+
+{{- if .Drifted }}
+Applied migration files were changed:
+{{- range .Drifted }}
+  {{ yellow "--" }} {{ red "DRIFT:" }} {{ .GetName }}
+{{- end }}
+{{- end }}
 
 {{- if and .Current .Current.Error }}
 Last migration attempt had errors: